@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// autoscaleInterval is how often -autoscale measures and adjusts concurrency
+const autoscaleInterval = 2 * time.Second
+
+// autoscaleMinGain is the relative throughput increase that counts as still
+// rising; smaller changes are treated as noise at the knee
+const autoscaleMinGain = 0.05
+
+// concurrencyLimiter is a counting semaphore whose limit can change while
+// workers hold slots. Lowering the limit never preempts a holder; new
+// acquisitions wait until enough slots are released.
+type concurrencyLimiter struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+}
+
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+	l := &concurrencyLimiter{limit: max(limit, 1)}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Acquire blocks until a slot is free under the current limit. Once ctx is
+// cancelled it returns ctx's error without taking a slot, even if one is
+// free.
+func (l *concurrencyLimiter) Acquire(ctx context.Context) error {
+	// Wake the wait below on cancellation. The broadcast takes the lock, so
+	// it cannot slip in between the ctx check and cond.Wait.
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.cond.Broadcast()
+	})
+	defer stop()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if l.active < l.limit {
+			l.active++
+			return nil
+		}
+		l.cond.Wait()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *concurrencyLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.cond.Broadcast()
+}
+
+// SetLimit changes the number of slots, waking waiters if it grew
+func (l *concurrencyLimiter) SetLimit(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = max(n, 1)
+	l.cond.Broadcast()
+}
+
+// Limit returns the current number of slots
+func (l *concurrencyLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// autoscaler is an AIMD controller for worker concurrency. It adds step
+// while throughput keeps rising and p99 stays under the SLO, halves the
+// limit when p99 breaches the SLO, and settles once an increase stops
+// paying off: that point is the knee of the throughput curve.
+type autoscaler struct {
+	slo     time.Duration
+	step    int
+	ceiling int
+
+	limit          int
+	lastIncrease   int
+	lastThroughput float64
+	settled        bool
+}
+
+// newAutoscaler starts at one step and never exceeds maxLimit
+func newAutoscaler(slo time.Duration, maxLimit int) *autoscaler {
+	step := max(maxLimit/20, 1)
+	return &autoscaler{
+		slo:     slo,
+		step:    step,
+		ceiling: maxLimit,
+		limit:   min(step, maxLimit),
+	}
+}
+
+// observe takes one interval's throughput and p99 at the current limit and
+// returns the limit to use next
+func (a *autoscaler) observe(throughput float64, p99 time.Duration) int {
+	rising := throughput > a.lastThroughput*(1+autoscaleMinGain)
+	a.lastThroughput = throughput
+	switch {
+	case p99 > a.slo:
+		// Never climb back to a level that breached the SLO. Throughput at
+		// the halved limit is the new baseline, so forget the old one.
+		a.ceiling = max(a.limit-1, 1)
+		a.limit = max(a.limit/2, 1)
+		a.lastIncrease = 0
+		a.lastThroughput = 0
+		a.settled = false
+	case a.settled:
+	case rising && a.limit < a.ceiling:
+		next := min(a.limit+a.step, a.ceiling)
+		a.lastIncrease = next - a.limit
+		a.limit = next
+	default:
+		// The last increase bought no throughput, so it went past the knee
+		if !rising {
+			a.limit -= a.lastIncrease
+		}
+		a.lastIncrease = 0
+		a.settled = true
+	}
+	return a.limit
+}
+
+// startAutoscaler lowers limiter to the autoscaler's starting level, then
+// adjusts it every autoscaleInterval from the order latencies recorded in
+// that interval until ctx is cancelled. The limiter's current limit is the
+// most it will ever allow.
+func startAutoscaler(ctx context.Context, limiter *concurrencyLimiter, slo time.Duration) {
+	a := newAutoscaler(slo, limiter.Limit())
+	limiter.SetLimit(a.limit)
+	intervalWindowsEnabled[windowAutoscale].Store(true)
+	slog.Info("autoscaling concurrency", "start", a.limit, "max", a.ceiling, "latency_slo", slo)
+	go a.run(ctx, limiter)
+}
+
+func (a *autoscaler) run(ctx context.Context, limiter *concurrencyLimiter) {
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			window := stats.takeIntervalLatencies(windowAutoscale)
+			// Users still logging in have not sent orders yet
+			if window.Count() == 0 {
+				continue
+			}
+			throughput := float64(window.Count()) / autoscaleInterval.Seconds()
+			p99 := window.Percentiles(0.99)[0]
+			prev := a.limit
+			if next := a.observe(throughput, p99); next != prev {
+				limiter.SetLimit(next)
+				slog.Info("autoscale", "concurrency", next, "was", prev,
+					"orders_per_sec", round1(throughput), "p99", p99)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAutoscaleConvergesNearKnee(t *testing.T) {
+	// Throughput grows with concurrency up to the knee and is flat beyond
+	// it, while p99 grows quadratically past the knee
+	const knee = 40
+	base := 2 * time.Millisecond
+	curve := func(c int) (float64, time.Duration) {
+		throughput := float64(min(c, knee)) * 1000
+		p99 := base
+		if c > knee {
+			p99 = time.Duration(float64(base) * math.Pow(float64(c)/knee, 2))
+		}
+		return throughput, p99
+	}
+	converge := func(slo time.Duration) (limit, step int) {
+		a := newAutoscaler(slo, 100)
+		for i := 0; i < 200; i++ {
+			limit = a.observe(curve(a.limit))
+		}
+		return limit, a.step
+	}
+
+	// A loose SLO leaves the throughput knee as the limit
+	if limit, step := converge(3 * base); limit < knee-step || limit > knee {
+		t.Errorf("loose SLO: converged on %d, want within %d below the knee at %d", limit, step, knee)
+	}
+
+	// A tight SLO caps concurrency below the point where p99 breaches it
+	slo := base * 11 / 10
+	limit, step := converge(slo)
+	if _, p99 := curve(limit); p99 > slo {
+		t.Errorf("tight SLO: converged on %d with p99 %v over the %v SLO", limit, p99, slo)
+	}
+	if limit < knee-step {
+		t.Errorf("tight SLO: converged on %d, want within %d of the knee at %d", limit, step, knee)
+	}
+}
+
+func TestConcurrencyLimiterResize(t *testing.T) {
+	l := newConcurrencyLimiter(1)
+	l.Acquire(context.Background())
+
+	acquired := make(chan struct{})
+	go func() {
+		l.Acquire(context.Background())
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second Acquire succeeded past a limit of 1")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	l.SetLimit(2)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("raising the limit did not wake the waiting Acquire")
+	}
+}
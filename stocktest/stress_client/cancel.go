@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// recentOrdersCap is how many accepted order IDs each user keeps to cancel
+// or modify
+const recentOrdersCap = 256
+
+// cancelAckTimeout bounds the wait for a cancel ack. Engines whose TCP server
+// does not route cancels drop the frame without replying.
+const cancelAckTimeout = 5 * time.Second
+
+// errUnanswered means a request was not sent because an earlier one of its
+// kind went unanswered on the same connection. It is neither retried nor a
+// reason to replace the connection.
+var errUnanswered = errors.New("earlier request on this connection went unanswered")
+
+// recentOrders is a fixed-size ring of recently accepted order IDs. When full,
+// the oldest ID is overwritten.
+type recentOrders struct {
+	mu   sync.Mutex
+	ids  [recentOrdersCap]string
+	next int
+	size int
+}
+
+// Push records an accepted order ID
+func (r *recentOrders) Push(orderID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids[r.next] = orderID
+	r.next = (r.next + 1) % recentOrdersCap
+	if r.size < recentOrdersCap {
+		r.size++
+	}
+}
+
+// Pop removes and returns the most recently accepted order ID, so each order
+// is cancelled at most once.
+func (r *recentOrders) Pop() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size == 0 {
+		return "", false
+	}
+	r.next = (r.next - 1 + recentOrdersCap) % recentOrdersCap
+	r.size--
+	orderID := r.ids[r.next]
+	r.ids[r.next] = ""
+	return orderID, true
+}
+
+// Peek returns the most recently accepted order ID without removing it, so
+// an order can be modified more than once and still be cancelled later.
+func (r *recentOrders) Peek() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size == 0 {
+		return "", false
+	}
+	return r.ids[(r.next-1+recentOrdersCap)%recentOrdersCap], true
+}
+
+// submitCancelTCP cancels a previously accepted order and reports whether the
+// engine acknowledged the cancel. Once a cancel times out on a connection,
+// later cancels on it return errUnanswered without being sent, so an engine
+// that drops cancels costs cancelAckTimeout once per connection.
+func submitCancelTCP(conn net.Conn, orderID string) (bool, error) {
+	pc := unwrapPushConn(conn)
+	if pc != nil && pc.cancelsUnanswered.Load() {
+		atomic.AddInt64(&stats.CancelsSkipped, 1)
+		return false, fmt.Errorf("cancel %s: %w", orderID, errUnanswered)
+	}
+
+	route, routed := routeOrderResponse(conn, orderID)
+	if routed {
+		defer route.Close()
+	}
+	if _, err := conn.Write(protocol.EncodeCancelOrder(orderID)); err != nil {
+		recordError(classifyError(err, ErrCategoryWrite))
+		return false, fmt.Errorf("TCP write cancel failed: %w", err)
+	}
+
+	var resp protocol.OrderResponse
+	var err error
+	if routed {
+		resp, err = route.Await(context.Background(), cancelAckTimeout)
+	} else {
+		conn.SetReadDeadline(time.Now().Add(cancelAckTimeout))
+		resp, err = readOrderResponse(conn, orderID)
+		conn.SetReadDeadline(time.Time{})
+	}
+	if err != nil {
+		if pc != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+			pc.cancelsUnanswered.Store(true)
+		}
+		recordError(classifyError(err, ErrCategoryRead))
+		return false, fmt.Errorf("TCP read cancel ack failed: %w", err)
+	}
+
+	atomic.AddInt64(&stats.CancelsSubmitted, 1)
+	if resp.Accepted {
+		atomic.AddInt64(&stats.CancelsAccepted, 1)
+	}
+	return resp.Accepted, nil
+}
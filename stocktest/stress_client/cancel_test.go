@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestRecentOrdersEviction(t *testing.T) {
+	var r recentOrders
+	if _, ok := r.Peek(); ok {
+		t.Fatal("Peek on empty history returned an order")
+	}
+	const extra = 10
+	for i := 0; i < recentOrdersCap+extra; i++ {
+		r.Push(strconv.Itoa(i))
+	}
+
+	if got, _ := r.Peek(); got != strconv.Itoa(recentOrdersCap+extra-1) {
+		t.Errorf("Peek = %q, want the newest order", got)
+	}
+
+	// Only the newest recentOrdersCap IDs remain, newest first
+	for i := recentOrdersCap + extra - 1; i >= extra; i-- {
+		got, ok := r.Pop()
+		if !ok || got != strconv.Itoa(i) {
+			t.Fatalf("Pop = %q, %v, want %d", got, ok, i)
+		}
+	}
+	if got, ok := r.Pop(); ok {
+		t.Errorf("Pop returned evicted order %q", got)
+	}
+}
+
+func TestCancelsSkippedAfterUnansweredCancel(t *testing.T) {
+	resetGlobals(t)
+
+	// Cut the 5s ack wait short
+	ioTimeout = 50 * time.Millisecond
+	defer func() { ioTimeout = 0 }()
+
+	// The engine drops cancels without replying, like its TCP server today
+	raw, server := net.Pipe()
+	var cancels atomic.Int64
+	go func() {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			if len(body) > 0 && body[0] == protocol.MessageTypeCancelOrder {
+				cancels.Add(1)
+			}
+		}
+	}()
+	dials := 0
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		dials++
+		return newPushConn(raw, recordPush), nil
+	})
+	defer pool.Close()
+
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		orderID := fmt.Sprintf("order_%d", i)
+		err := withRetry(ctx, pool, 2, func(conn net.Conn) error {
+			_, err := submitCancelTCP(conn, orderID)
+			return err
+		})
+		if i == 1 && (err == nil || errors.Is(err, errUnanswered)) {
+			t.Errorf("first cancel: err = %v, want an ack timeout", err)
+		}
+		if i > 1 && !errors.Is(err, errUnanswered) {
+			t.Errorf("cancel %d: err = %v, want errUnanswered", i, err)
+		}
+	}
+
+	if got := cancels.Load(); got != 1 {
+		t.Errorf("engine received %d cancels, want only the first", got)
+	}
+	// The first cancel's retry is skipped too, leaving its timeout standing
+	if got := atomic.LoadInt64(&stats.CancelsSkipped); got != 3 {
+		t.Errorf("%d cancels skipped, want 3", got)
+	}
+	if dials != 1 {
+		t.Errorf("%d connections dialed, want the first kept", dials)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// clockSkewSamples is how many order round trips to an engine are taken
+// before its clock offset is logged. The estimate keeps improving after.
+const clockSkewSamples = 16
+
+// clockSample is one NTP-style offset estimate from an order round trip:
+// the engine stamps its response somewhere between our write and our read,
+// so taking it as stamped at the midpoint is wrong by at most RTT/2
+type clockSample struct {
+	// Offset is how far the engine's clock is ahead of ours
+	Offset time.Duration
+	RTT    time.Duration
+}
+
+// newClockSample estimates the offset from an order written at sent,
+// stamped by the engine at server and answered at received
+func newClockSample(sent, server, received time.Time) clockSample {
+	rtt := received.Sub(sent)
+	return clockSample{Offset: server.Sub(sent.Add(rtt / 2)), RTT: rtt}
+}
+
+// clockSkewEstimate is the best sample from one engine: the one with the
+// shortest round trip, whose error bound is tightest
+type clockSkewEstimate struct {
+	Best    clockSample
+	Samples int
+}
+
+// clockSkewTracker estimates each engine's clock offset from the server
+// times in its protocol version 3 order responses, so timestamps in engine
+// logs can be lined up with the client's
+type clockSkewTracker struct {
+	mu      sync.Mutex
+	engines map[string]*clockSkewEstimate
+}
+
+// clockSkews tracks every engine the run trades with, keyed by address
+var clockSkews = newClockSkewTracker()
+
+func newClockSkewTracker() *clockSkewTracker {
+	return &clockSkewTracker{engines: make(map[string]*clockSkewEstimate)}
+}
+
+// Add records one round trip to the engine at addr, logging the estimate
+// once clockSkewSamples have been taken
+func (t *clockSkewTracker) Add(addr string, sent, server, received time.Time) {
+	s := newClockSample(sent, server, received)
+	t.mu.Lock()
+	e := t.engines[addr]
+	if e == nil {
+		e = &clockSkewEstimate{Best: s}
+		t.engines[addr] = e
+	}
+	e.Samples++
+	if s.RTT < e.Best.RTT {
+		e.Best = s
+	}
+	estimate := *e
+	t.mu.Unlock()
+
+	if estimate.Samples == clockSkewSamples {
+		logClockSkew(addr, estimate)
+	}
+}
+
+// Estimates returns a copy of every engine's current estimate
+func (t *clockSkewTracker) Estimates() map[string]clockSkewEstimate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string]clockSkewEstimate, len(t.engines))
+	for addr, e := range t.engines {
+		out[addr] = *e
+	}
+	return out
+}
+
+// logClockSkew prints one engine's clock offset
+func logClockSkew(addr string, e clockSkewEstimate) {
+	log.Printf("Clock Skew: engine %s clock is %v ahead of the client (±%v, best of %d round trips)",
+		addr, e.Best.Offset.Round(time.Microsecond), (e.Best.RTT / 2).Round(time.Microsecond), e.Samples)
+}
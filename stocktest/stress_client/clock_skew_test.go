@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"log"
+	"math"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestClockSkewEstimate(t *testing.T) {
+	resetGlobals(t)
+
+	defer func(v int) { protocolVersion = v }(protocolVersion)
+	protocolVersion = ProtocolVersionServerTime
+	defer func(t *clockSkewTracker) { clockSkews = t }(clockSkews)
+	clockSkews = newClockSkewTracker()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// The midpoint of a round trip lines up exactly with the server stamp
+	sent := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	if s := newClockSample(sent, sent.Add(5*time.Millisecond-time.Second), sent.Add(10*time.Millisecond)); s.Offset != -time.Second || s.RTT != 10*time.Millisecond {
+		t.Errorf("sample = %+v, want offset -1s and rtt 10ms", s)
+	}
+
+	// The fake engine's clock runs 250ms ahead and it takes a while to
+	// answer, stamping its response halfway through
+	const engineAhead = 250 * time.Millisecond
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, _ := protocol.DecodeSubmitOrder(body)
+			time.Sleep(time.Millisecond)
+			stamp := time.Now().Add(engineAhead).UnixMicro()
+			time.Sleep(time.Millisecond)
+			extra := binary.BigEndian.AppendUint64([]byte{0, 0}, uint64(stamp))
+			server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true, Message: "ok", Extra: extra}))
+		}
+	}()
+
+	for i := 0; i < clockSkewSamples; i++ {
+		if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e, ok := clockSkews.Estimates()[client.RemoteAddr().String()]
+	if !ok || e.Samples != clockSkewSamples {
+		t.Fatalf("estimate = %+v, %v; want %d samples", e, ok, clockSkewSamples)
+	}
+	if diff := (e.Best.Offset - engineAhead).Abs(); diff > e.Best.RTT/2 {
+		t.Errorf("estimated offset %v, want %v within ±%v", e.Best.Offset, engineAhead, e.Best.RTT/2)
+	}
+
+	snap := stats.snapshot()
+	r := buildReport(&snap, StressConfig{}, time.Second, false)
+	if c, ok := r.ClockSkew[client.RemoteAddr().String()]; !ok || c.Samples != clockSkewSamples || math.Abs(c.OffsetMs-250) > c.ErrorMs {
+		t.Errorf("clock_skew = %+v, want an offset of about 250ms", r.ClockSkew)
+	}
+}
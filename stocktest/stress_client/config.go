@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadConfigFile overlays the keys present in a YAML config file onto cfg.
+// Keys missing from the file keep their current values.
+func loadConfigFile(path string, cfg *StressConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyConfigFile loads path into cfg after flag parsing, then re-applies
+// every flag that was set on the command line so explicit flags win over
+// file values.
+func applyConfigFile(fs *flag.FlagSet, path string, cfg *StressConfig) error {
+	explicit := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = f.Value.String()
+	})
+
+	if err := loadConfigFile(path, cfg); err != nil {
+		return err
+	}
+
+	for name, value := range explicit {
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("failed to re-apply -%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the merged configuration before the run starts
+func (c StressConfig) validate() error {
+	var errs []error
+	addrs, err := parseEngineAddrs(c.EngineAddr)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	switch c.ShardBy {
+	case "", ShardRoundRobin, ShardSymbol:
+	default:
+		errs = append(errs, fmt.Errorf("unknown shard-by %q (want round-robin or symbol)", c.ShardBy))
+	}
+	if c.NumUsers < 1 {
+		errs = append(errs, errors.New("users must be at least 1"))
+	}
+	if c.OrdersPerUser < 0 {
+		errs = append(errs, errors.New("orders must not be negative"))
+	}
+	if c.TotalOrders < 0 {
+		errs = append(errs, errors.New("total-orders must not be negative"))
+	}
+	if c.TotalOrders > 0 && (c.Replay != "" || c.CrossAccounts >= 2 || c.Sweep != "") {
+		errs = append(errs, errors.New("total-orders cannot be combined with replay, cross-accounts or sweep"))
+	}
+	if c.Concurrency < 1 {
+		errs = append(errs, errors.New("concurrency must be at least 1"))
+	}
+	if c.SignupConc < 0 {
+		errs = append(errs, errors.New("signup-concurrency must not be negative"))
+	}
+	if c.ConnsPerUser < 0 {
+		errs = append(errs, errors.New("conns-per-user must not be negative"))
+	}
+	if c.MaxConns < 0 {
+		errs = append(errs, errors.New("max-connections must not be negative"))
+	}
+	// A user opens all of its connections before sending, so it must fit
+	// under the cap on its own
+	need := max(c.ConnsPerUser, 1)
+	if c.ShardBy == ShardSymbol {
+		need *= max(len(addrs), 1)
+	}
+	if c.CrossAccounts >= 2 {
+		need = max(need, c.CrossAccounts)
+	}
+	if c.MaxConns > 0 && c.MaxConns < need {
+		errs = append(errs, fmt.Errorf("max-connections %d is below the %d connections each user opens", c.MaxConns, need))
+	}
+	if c.OrderConcurrency < 1 {
+		errs = append(errs, errors.New("order-concurrency must be at least 1"))
+	}
+	if c.Rate < 0 {
+		errs = append(errs, errors.New("rate must not be negative"))
+	}
+	if c.TestDuration < 0 {
+		errs = append(errs, errors.New("duration must not be negative"))
+	}
+	if c.Soak && c.TestDuration <= 0 {
+		errs = append(errs, errors.New("soak requires a positive duration"))
+	}
+	if c.HTTPTimeout < 0 {
+		errs = append(errs, errors.New("http-timeout must not be negative"))
+	}
+	if c.IOTimeout < 0 {
+		errs = append(errs, errors.New("io-timeout must not be negative"))
+	}
+	if c.MaxRetries < 0 {
+		errs = append(errs, errors.New("max-retries must not be negative"))
+	}
+	if c.MaxErrorRate < 0 || c.MaxErrorRate >= 1 {
+		errs = append(errs, errors.New("max-error-rate must be a fraction from 0 up to, but not including, 1"))
+	}
+	if c.FailErrorRate < 0 || c.FailErrorRate > 1 {
+		errs = append(errs, errors.New("fail-error-rate must be a fraction from 0 to 1"))
+	}
+	if c.OrderIDLen < 0 || c.OrderIDLen > maxPaddedIDLen || c.UserIDLen < 0 || c.UserIDLen > maxPaddedIDLen {
+		errs = append(errs, fmt.Errorf("order-id-len and user-id-len must be between 0 and %d", maxPaddedIDLen))
+	}
+	if c.TLSSessionCache < 0 {
+		errs = append(errs, errors.New("tls-session-cache-size must not be negative"))
+	}
+	if c.SendBuffer < 0 || c.RecvBuffer < 0 {
+		errs = append(errs, errors.New("sndbuf and rcvbuf must not be negative"))
+	}
+	if c.FragmentPct < 0 || c.FragmentPct > 100 {
+		errs = append(errs, errors.New("fragment-pct must be between 0 and 100"))
+	}
+	if c.CancelPct < 0 || c.CancelPct > 100 {
+		errs = append(errs, errors.New("cancel-pct must be between 0 and 100"))
+	}
+	if c.ModifyPct < 0 || c.ModifyPct > 100 {
+		errs = append(errs, errors.New("modify-pct must be between 0 and 100"))
+	}
+	if c.CrossPct < 0 || c.CrossPct > 100 {
+		errs = append(errs, errors.New("cross-pct must be between 0 and 100"))
+	}
+	if c.QueryPct < 0 || c.QueryPct > 100 {
+		errs = append(errs, errors.New("query-pct must be between 0 and 100"))
+	}
+	if _, err := parseOrderMix(c.OrderMix); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := newQuantityModel(c); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := newSymbolPicker(c); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseProfile(c.Profile); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseThinkTime(c.ThinkTime); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseTimestampSkews(c.TimestampSkew); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseHistBuckets(c.HistBuckets); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseReportInterval(c.ReportInterval); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseSweep(c.Sweep); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := parseLogLevel(c.LogLevel); err != nil {
+		errs = append(errs, err)
+	}
+	if c.PriceRef <= 0 || c.PriceSpread < 0 {
+		errs = append(errs, errors.New("price-ref must be positive and price-spread not negative"))
+	}
+	if c.CorrectOmission && c.TargetRate <= 0 {
+		errs = append(errs, errors.New("correct-omission requires a positive target-rate"))
+	}
+	if c.DryRun && c.CrossAccounts >= 2 {
+		errs = append(errs, errors.New("dry-run cannot be combined with cross-accounts, which reads positions from the frontend"))
+	}
+	if c.Autoscale && c.LatencySLO <= 0 {
+		errs = append(errs, errors.New("autoscale requires a positive latency-slo"))
+	}
+	if c.Autoscale && (c.Soak || c.Replay != "") {
+		errs = append(errs, errors.New("autoscale cannot be combined with soak, whose users never give up their slot, or replay"))
+	}
+	if c.ReplayTiming && c.Replay == "" {
+		errs = append(errs, errors.New("replay-timing requires replay"))
+	}
+	if c.Replay != "" && (c.CrossAccounts >= 2 || c.Soak || c.TimestampSkew != "") {
+		errs = append(errs, errors.New("replay cannot be combined with cross-accounts, soak or timestamp-skew"))
+	}
+	if c.Manifest != "" && c.Manifest == c.OutputJSON {
+		errs = append(errs, errors.New("manifest and output-json must be different files"))
+	}
+	if c.Replay != "" && c.Replay == c.OrdersCSV {
+		errs = append(errs, errors.New("replay and csv must be different files"))
+	}
+	if c.DryRun && c.QueryPct > 0 {
+		errs = append(errs, errors.New("dry-run cannot be combined with query-pct, which reads portfolios from the frontend"))
+	}
+	if c.VerifyDepth < 0 {
+		errs = append(errs, errors.New("verify-depth must not be negative"))
+	}
+	if c.VerifyDepth > 0 {
+		if c.DepthSymbol == "" || slices.Contains(c.Symbols, c.DepthSymbol) {
+			errs = append(errs, errors.New("verify-depth-symbol must be set and not one of the traded symbols"))
+		}
+		if c.PriceRef-float64(c.VerifyDepth)*depthVerifyTick <= 0 {
+			errs = append(errs, fmt.Errorf("verify-depth %d rests orders below price 0; lower it or raise price-ref", c.VerifyDepth))
+		}
+		if c.DryRun {
+			errs = append(errs, errors.New("dry-run cannot be combined with verify-depth, since it keeps no book"))
+		}
+	}
+	if c.ReuseTokens && c.TokensFile == "" {
+		errs = append(errs, errors.New("reuse-tokens requires tokens-file"))
+	}
+	if c.TokensFile != "" && (c.DryRun || c.CrossAccounts >= 2 || c.QueryPct > 0) {
+		errs = append(errs, errors.New("tokens-file cannot be combined with dry-run, or with cross-accounts or query-pct, which need frontend sessions"))
+	}
+	if c.SingleUser && (c.TokensFile != "" || c.CrossAccounts >= 2) {
+		errs = append(errs, errors.New("single-user cannot be combined with tokens-file or cross-accounts, which needs distinct accounts"))
+	}
+	if c.Sweep != "" && (c.Soak || c.Replay != "" || c.Autoscale || c.CrossAccounts >= 2 || c.VerifyDepth > 0) {
+		errs = append(errs, errors.New("sweep cannot be combined with soak, replay, autoscale, cross-accounts or verify-depth"))
+	}
+	if c.Probe && (c.Smoke || c.Sweep != "") {
+		errs = append(errs, errors.New("probe cannot be combined with smoke or sweep"))
+	}
+	if c.VerifyTIF {
+		if c.Smoke || c.Probe || c.Sweep != "" {
+			errs = append(errs, errors.New("verify-tif cannot be combined with smoke, probe or sweep"))
+		}
+		if c.DepthSymbol == "" || c.DryRun {
+			errs = append(errs, errors.New("verify-tif requires a verify-depth-symbol and cannot be combined with dry-run, since it keeps no book"))
+		}
+	}
+	if len(c.Symbols) == 0 {
+		errs = append(errs, errors.New("at least one symbol is required"))
+	}
+	return errors.Join(errs...)
+}
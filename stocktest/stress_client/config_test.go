@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadSampleConfig(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg := StressConfig{}
+	fs.StringVar(&cfg.FrontendURL, "frontend", "http://localhost:3000", "")
+	fs.IntVar(&cfg.NumUsers, "users", 10, "")
+	fs.IntVar(&cfg.OrderConcurrency, "order-concurrency", 10, "")
+	fs.DurationVar(&cfg.Heartbeat, "heartbeat", 30*time.Second, "")
+	if err := fs.Parse([]string{"-users", "7", "-heartbeat", "1m"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if err := applyConfigFile(fs, "testdata/sample_config.yaml", &cfg); err != nil {
+		t.Fatalf("applyConfigFile: %v", err)
+	}
+
+	want := StressConfig{
+		FrontendURL:      "http://frontend.test:3000",
+		EngineAddr:       "engine.test:50052",
+		NumUsers:         7, // explicit flag wins over the file
+		OrdersPerUser:    40,
+		Concurrency:      25,
+		OrderConcurrency: 4,
+		TestDuration:     2 * time.Minute,
+		RampUp:           30 * time.Second,
+		Symbols:          []string{"AAPL", "MSFT", "NVDA"},
+		CancelPct:        10,
+		CorrectOmission:  true,
+		TargetRate:       50,
+		Heartbeat:        time.Minute, // explicit flag wins over the file
+		OutputJSON:       "results.json",
+		PriceModel:       PriceModelWalk,
+		PriceRef:         180,
+		PriceSpread:      20,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config =\n%+v\nwant\n%+v", cfg, want)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// connWaitWarnEvery spaces the warnings logged while workers queue for a
+// -max-connections slot
+const connWaitWarnEvery = 5 * time.Second
+
+// connSlots caps the engine connections open at once across all users
+// (-max-connections), so a large run queues for connections instead of
+// running out of file descriptors. A slot is held from before the dial
+// until the connection is closed. Nil leaves connections unlimited.
+var connSlots chan struct{}
+
+// connectGate is held by a user opening several connections under the
+// cap, so users cannot each hold part of their connections while waiting
+// on one another for the rest
+var connectGate = make(chan struct{}, 1)
+
+var (
+	// connWaiters is the number of dials queued for a slot
+	connWaiters atomic.Int64
+	// lastConnWaitWarn is when the last queueing warning was logged, in
+	// Unix nanoseconds
+	lastConnWaitWarn atomic.Int64
+)
+
+// newConnSlots returns a semaphore of n slots, or nil when n is 0
+func newConnSlots(n int) chan struct{} {
+	if n <= 0 {
+		return nil
+	}
+	return make(chan struct{}, n)
+}
+
+// acquireConnSlot waits for a free connection slot and returns the func
+// that frees it, or ctx's error if ctx ends first. While dials are queued a
+// warning is logged at most every connWaitWarnEvery.
+func acquireConnSlot(ctx context.Context) (release func(), err error) {
+	slots := connSlots
+	if slots == nil {
+		return func() {}, nil
+	}
+	release = func() { <-slots }
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	waiting := connWaiters.Add(1)
+	defer connWaiters.Add(-1)
+	now := time.Now().UnixNano()
+	if last := lastConnWaitWarn.Load(); now-last >= int64(connWaitWarnEvery) && lastConnWaitWarn.CompareAndSwap(last, now) {
+		slog.Warn("workers waiting for a connection slot; raise -max-connections if file descriptors allow",
+			"max_connections", cap(slots), "waiting", waiting)
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lockConnect takes connectGate before a user opens its n connections and
+// returns the func that gives it back. Without a cap, or for a single
+// connection, there is nothing to wait for.
+func lockConnect(ctx context.Context, n int) (unlock func(), err error) {
+	if connSlots == nil || n < 2 {
+		return func() {}, nil
+	}
+	select {
+	case connectGate <- struct{}{}:
+		return func() { <-connectGate }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
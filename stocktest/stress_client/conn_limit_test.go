@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestMaxConnectionsCap(t *testing.T) {
+	resetGlobals(t)
+
+	warnings := &countingWriter{marker: "workers waiting for a connection slot"}
+	prev := slog.Default()
+	defer slog.SetDefault(prev)
+	slog.SetDefault(slog.New(slog.NewTextHandler(warnings, nil)))
+	lastConnWaitWarn.Store(0)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, err := protocol.ReadFrame(conn); err == nil {
+					conn.Write(protocol.EncodeLoginResponse(protocol.LoginResponse{Success: true, Message: "ok"}))
+				}
+				io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+
+	const limit, workers = 3, 12
+	connSlots = newConnSlots(limit)
+	defer func() { connSlots = nil }()
+
+	// Each worker holds its connection a while, as a user trading would
+	var open, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := connectEngine(context.Background(), func() (net.Conn, error) {
+				return net.Dial("tcp", ln.Addr().String())
+			}, "token")
+			if err != nil {
+				t.Errorf("connect: %v", err)
+				return
+			}
+			n := open.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(10 * time.Millisecond)
+			open.Add(-1)
+			conn.Close()
+		}()
+	}
+	wg.Wait()
+
+	if p := peak.Load(); p != limit {
+		t.Errorf("%d connections open at once, want the cap of %d", p, limit)
+	}
+	if len(connSlots) != 0 {
+		t.Errorf("%d slots still held after every connection closed", len(connSlots))
+	}
+	// Warnings are spaced out, so a short burst of queueing logs just one
+	if n := warnings.Count(); n != 1 {
+		t.Errorf("%d queueing warnings logged, want 1", n)
+	}
+
+	// A worker queued when its run ends gives up without dialing
+	for range limit {
+		connSlots <- struct{}{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = connectEngine(ctx, func() (net.Conn, error) {
+		t.Error("dialed with every slot taken")
+		return nil, errors.New("unreachable")
+	}, "token")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("queued connect returned %v, want context.Canceled", err)
+	}
+}
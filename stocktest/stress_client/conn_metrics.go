@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+// Connection-level counters shared by every engine connection
+var (
+	activeConns   int64
+	bytesSent     int64
+	bytesReceived int64
+)
+
+// countingConn wraps an engine connection to track bytes on the wire and the
+// number of open connections.
+type countingConn struct {
+	net.Conn
+	closed int32
+	// release frees the connection's -max-connections slot, if any
+	release func()
+}
+
+// newCountingConn wraps conn; release, if not nil, is called once when it
+// is closed
+func newCountingConn(conn net.Conn, release func()) net.Conn {
+	atomic.AddInt64(&activeConns, 1)
+	return &countingConn{Conn: conn, release: release}
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(&bytesReceived, int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	atomic.AddInt64(&bytesSent, int64(n))
+	return n, err
+}
+
+func (c *countingConn) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return c.Conn.Close()
+	}
+	atomic.AddInt64(&activeConns, -1)
+	err := c.Conn.Close()
+	if c.release != nil {
+		c.release()
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestConnectionChurnCounted(t *testing.T) {
+	resetGlobals(t)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// The engine closes every connection after its second order and turns
+	// away the third login
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for logins := 1; ; logins++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				for orders := 0; orders < 2; {
+					body, err := protocol.ReadFrame(conn)
+					if err != nil {
+						return
+					}
+					switch body[0] {
+					case protocol.MessageTypeLoginRequest:
+						conn.Write(protocol.EncodeLoginResponse(protocol.LoginResponse{Success: logins != 3, Message: "ok"}))
+					case protocol.MessageTypeSubmitOrder:
+						o, _ := protocol.DecodeSubmitOrder(body)
+						conn.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true}))
+						orders++
+					}
+				}
+			}()
+		}
+	}()
+
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		return connectEngine(context.Background(), func() (net.Conn, error) {
+			return net.Dial("tcp", ln.Addr().String())
+		}, "token")
+	})
+	defer pool.Close()
+
+	// Without retries only the order that meets the refused login fails
+	var failed int
+	for i := 0; i < 8; i++ {
+		err := withRetry(context.Background(), pool, 0, func(conn net.Conn) error {
+			_, err := submitOrderTCP(context.Background(), conn, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{})
+			return err
+		})
+		if err != nil {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("%d orders failed, want 1", failed)
+	}
+
+	snap := stats.snapshot()
+	if snap.Reconnects == 0 || snap.Reauths == 0 {
+		t.Errorf("%d reconnects and %d reauths after the engine closed connections, want both non-zero", snap.Reconnects, snap.Reauths)
+	}
+	if snap.ReauthFailures != 1 {
+		t.Errorf("%d reauth failures, want the one refused login", snap.ReauthFailures)
+	}
+	r := buildReport(&snap, StressConfig{}, time.Second, false)
+	if r.Reconnects != snap.Reconnects || r.Reauths != snap.Reauths || r.ReauthFailures != 1 {
+		t.Errorf("report has %d reconnects, %d reauths and %d reauth failures, want %d, %d and 1",
+			r.Reconnects, r.Reauths, r.ReauthFailures, snap.Reconnects, snap.Reauths)
+	}
+
+	// Closing the pool is not churn
+	pool.Close()
+	if got := atomic.LoadInt64(&stats.Reauths); got != snap.Reauths {
+		t.Errorf("closing the pool changed reauths from %d to %d", snap.Reauths, got)
+	}
+}
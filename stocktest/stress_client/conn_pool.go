@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// dialEngine opens a TLS connection to the engine and authenticates it with
+// the user's trading token
+func dialEngine(ctx context.Context, addr, tradingToken string) (net.Conn, error) {
+	return connectEngine(ctx, func() (net.Conn, error) {
+		if dryRun {
+			return newDryRunConn(), nil
+		}
+		conn, err := dialTLS(addr)
+		if err != nil {
+			return nil, err
+		}
+		if err := engineSocket.apply(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return newPushConn(conn, recordPush), nil
+	}, tradingToken)
+}
+
+// connectEngine opens a connection with dial and authenticates it, recording
+// the time from the start of the dial (including any TLS handshake) to a
+// successful login as a connect latency. With -max-connections it first
+// waits for a slot, which the connection holds until it is closed.
+func connectEngine(ctx context.Context, dial func() (net.Conn, error), tradingToken string) (net.Conn, error) {
+	release, err := acquireConnSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	start := time.Now()
+	raw, err := dial()
+	if err != nil {
+		release()
+		recordError(classifyError(err, ErrCategoryDial))
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	conn := newCountingConn(raw, release)
+	if err := authenticateTCP(ctx, conn, tradingToken); err != nil {
+		conn.Close()
+		recordError(classifyError(err, ErrCategoryAuth))
+		return nil, fmt.Errorf("authenticate: %w", err)
+	}
+	latency := time.Since(start)
+
+	statsMutex.Lock()
+	stats.ConnectLatencies = append(stats.ConnectLatencies, latency)
+	statsMutex.Unlock()
+	return conn, nil
+}
+
+// releaseConn returns conn to the pool, or discards it if the exchange on it
+// failed and the stream can no longer be trusted. An invalid order fails
+// before anything is written, so its connection is kept.
+func releaseConn(p *ConnPool, conn net.Conn, err error) {
+	if err != nil && !errors.Is(err, protocol.ErrInvalidOrder) && !errors.Is(err, errUnanswered) {
+		slog.Warn("replacing engine connection after error", "err", err)
+		p.Discard(conn)
+		return
+	}
+	p.Put(conn)
+}
+
+// DialFunc opens and authenticates one engine connection
+type DialFunc func() (net.Conn, error)
+
+// ConnPool hands out up to size authenticated engine connections. A caller
+// owns a connection between Get and Put, so a pool of size 1 also serializes
+// use of a single connection. Connections that fail are passed to Discard
+// and the next Get dials a replacement.
+type ConnPool struct {
+	dial  DialFunc
+	idle  chan net.Conn
+	slots chan struct{} // one token per open connection
+	// replace counts discarded connections not yet redialed, so Get can
+	// tell a re-authentication from a first connect
+	replace atomic.Int64
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewConnPool creates a pool of at most size connections opened with dial.
+// Connections are dialed lazily on Get.
+func NewConnPool(size int, dial DialFunc) *ConnPool {
+	if size < 1 {
+		size = 1
+	}
+	return &ConnPool{
+		dial:  dial,
+		idle:  make(chan net.Conn, size),
+		slots: make(chan struct{}, size),
+	}
+}
+
+// Get returns an idle connection, dials a new one if the pool is below its
+// size, or waits for one to be returned. An idle connection whose reader
+// has stopped, which an exchange that returned it early could not discard,
+// is replaced.
+func (p *ConnPool) Get(ctx context.Context) (net.Conn, error) {
+	select {
+	case conn := <-p.idle:
+		if p.usable(conn) {
+			return conn, nil
+		}
+	default:
+	}
+
+	select {
+	case conn := <-p.idle:
+		if p.usable(conn) {
+			return conn, nil
+		}
+		return p.Get(ctx)
+	case p.slots <- struct{}{}:
+		redial := p.takeReplace()
+		conn, err := p.dial()
+		if err != nil {
+			<-p.slots
+			if redial {
+				p.replace.Add(1)
+				atomic.AddInt64(&stats.ReauthFailures, 1)
+			}
+			return nil, err
+		}
+		if redial {
+			atomic.AddInt64(&stats.Reauths, 1)
+		}
+		return conn, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// usable reports whether conn can still carry requests, discarding it if
+// not
+func (p *ConnPool) usable(conn net.Conn) bool {
+	if c := unwrapPushConn(conn); c != nil && c.broken() {
+		p.Discard(conn)
+		return false
+	}
+	return true
+}
+
+// Put returns a healthy connection to the pool
+func (p *ConnPool) Put(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		p.release(conn)
+		return
+	}
+	p.idle <- conn // never blocks: idle holds at most size connections
+}
+
+// Discard closes a failed connection and frees its slot so the next Get
+// dials a replacement
+func (p *ConnPool) Discard(conn net.Conn) {
+	p.replace.Add(1)
+	p.release(conn)
+}
+
+// release closes conn and frees its slot
+func (p *ConnPool) release(conn net.Conn) {
+	conn.Close()
+	<-p.slots
+}
+
+// takeReplace claims one pending replacement, reporting whether there was
+// one
+func (p *ConnPool) takeReplace() bool {
+	for {
+		n := p.replace.Load()
+		if n == 0 {
+			return false
+		}
+		if p.replace.CompareAndSwap(n, n-1) {
+			return true
+		}
+	}
+}
+
+// Close closes idle connections. Connections still checked out are closed
+// when they are returned.
+func (p *ConnPool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	for {
+		select {
+		case conn := <-p.idle:
+			p.release(conn)
+		default:
+			return
+		}
+	}
+}
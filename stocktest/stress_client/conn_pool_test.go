@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConnPoolReplacesFailedConn(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	// The first connection is closed immediately; later ones echo
+	go func() {
+		for n := 0; ; n++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			if n == 0 {
+				conn.Close()
+				continue
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	var dials int32
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return net.Dial("tcp", ln.Addr().String())
+	})
+	defer pool.Close()
+
+	ping := func(conn net.Conn) error {
+		conn.SetDeadline(time.Now().Add(time.Second))
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			return err
+		}
+		buf := make([]byte, 4)
+		_, err := io.ReadFull(conn, buf)
+		return err
+	}
+
+	ctx := context.Background()
+	conn, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := ping(conn); err == nil {
+		t.Fatal("ping on closed connection succeeded")
+	}
+	releaseConn(pool, conn, errors.New("closed by server"))
+
+	conn, err = pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get after discard: %v", err)
+	}
+	if err := ping(conn); err != nil {
+		t.Fatalf("ping on replacement connection: %v", err)
+	}
+	pool.Put(conn)
+
+	// A healthy connection is reused rather than redialed
+	conn, err = pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	pool.Put(conn)
+	if n := atomic.LoadInt32(&dials); n != 2 {
+		t.Errorf("dialed %d times, want 2", n)
+	}
+}
+
+func TestConnectLatencyRecorded(t *testing.T) {
+	resetGlobals(t)
+
+	const handshake = 20 * time.Millisecond
+	conn, err := connectEngine(context.Background(), func() (net.Conn, error) {
+		time.Sleep(handshake)
+		return newDryRunConn(), nil
+	}, "token")
+	if err != nil {
+		t.Fatalf("connectEngine: %v", err)
+	}
+	conn.Close()
+
+	statsMutex.Lock()
+	snap := stats.snapshot()
+	statsMutex.Unlock()
+	report := buildReport(&snap, StressConfig{}, time.Second, false)
+	if report.ConnectLatency.Count != 1 {
+		t.Fatalf("connect latency count = %d, want 1", report.ConnectLatency.Count)
+	}
+	if got := report.ConnectLatency.MinMs; got < toMs(handshake) {
+		t.Errorf("connect latency = %.2fms, want at least the %v dial", got, handshake)
+	}
+	if report.OrderLatency.Count != 0 {
+		t.Errorf("order latency count = %d, want connect time kept out of order latency", report.OrderLatency.Count)
+	}
+}
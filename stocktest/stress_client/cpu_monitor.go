@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Send back-off applied by workers while the client is CPU saturated
+const (
+	cpuBackoffStep = time.Millisecond
+	cpuBackoffMax  = 50 * time.Millisecond
+)
+
+// sendBackoff is the current per-order delay (nanoseconds) requested by the
+// CPU monitor. Workers read it atomically before each order.
+var sendBackoff int64
+
+// peakClientCPU holds math.Float64bits of the highest sampled CPU percentage
+var peakClientCPU uint64
+
+// startCPUMonitor samples the client's CPU utilization once a second. When it
+// exceeds threshold (percent of all cores) a warning is logged, since latency
+// measured from a saturated client reflects client scheduling rather than
+// the engine. With backoff enabled the per-order send delay grows while
+// saturated and decays once utilization drops.
+func startCPUMonitor(ctx context.Context, threshold float64, backoff bool) {
+	lastCPU, ok := processCPUTime()
+	if !ok {
+		slog.Warn("client CPU monitoring is not supported on this platform")
+		return
+	}
+	lastWall := time.Now()
+	numCPU := float64(runtime.NumCPU())
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cpu, _ := processCPUTime()
+			now := time.Now()
+			wall := now.Sub(lastWall)
+			if wall <= 0 {
+				continue
+			}
+			pct := float64(cpu-lastCPU) / (float64(wall) * numCPU) * 100
+			lastCPU, lastWall = cpu, now
+
+			if pct > math.Float64frombits(atomic.LoadUint64(&peakClientCPU)) {
+				atomic.StoreUint64(&peakClientCPU, math.Float64bits(pct))
+			}
+
+			if pct >= threshold {
+				slog.Warn("client CPU saturated, latency results may be client-limited", "cpu_pct", round1(pct), "threshold_pct", threshold)
+				if backoff {
+					delay := atomic.LoadInt64(&sendBackoff) + int64(cpuBackoffStep)
+					if delay > int64(cpuBackoffMax) {
+						delay = int64(cpuBackoffMax)
+					}
+					atomic.StoreInt64(&sendBackoff, delay)
+				}
+			} else if backoff {
+				delay := atomic.LoadInt64(&sendBackoff) / 2
+				if delay < int64(cpuBackoffStep) {
+					delay = 0
+				}
+				atomic.StoreInt64(&sendBackoff, delay)
+			}
+		}
+	}
+}
+
+// peakCPU returns the highest client CPU utilization seen so far
+func peakCPU() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&peakClientCPU))
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import "time"
+
+// processCPUTime is not implemented on this platform; CPU monitoring is skipped.
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// PortfolioResponse is the frontend's portfolio payload
+type PortfolioResponse struct {
+	Positions []struct {
+		Symbol   string `json:"symbol"`
+		Quantity int64  `json:"quantity"`
+	} `json:"positions"`
+}
+
+// crossAccount is one authenticated account driven by a cross-account worker
+type crossAccount struct {
+	userID string
+	tokens AuthTokens
+	conn   net.Conn
+	before map[string]int64
+}
+
+// fetchPortfolio returns the account's position per symbol
+func fetchPortfolio(frontendURL, sessionToken string) (map[string]int64, error) {
+	req, err := http.NewRequest(http.MethodGet, frontendURL+"/api/trading/portfolio", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build portfolio request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+sessionToken)
+
+	resp, err := frontendClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("portfolio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newFrontendError("portfolio", resp)
+	}
+
+	var portfolio PortfolioResponse
+	if err := json.NewDecoder(resp.Body).Decode(&portfolio); err != nil {
+		return nil, fmt.Errorf("failed to decode portfolio response: %w", err)
+	}
+
+	positions := make(map[string]int64, len(portfolio.Positions))
+	for _, p := range portfolio.Positions {
+		positions[p.Symbol] += p.Quantity
+	}
+	return positions, nil
+}
+
+// crossAccountEngine picks the one engine a cross-account worker trades on,
+// and the symbols that engine handles. Both legs of a pair must reach the
+// same book, so the worker's accounts all connect to one engine: its
+// round-robin home, or under -shard-by symbol the engine of one of the
+// symbols, trading only the symbols routed there.
+func crossAccountEngine(addrs []string, shardBy string, workerID int, symbols []string) (string, []string) {
+	engine := (workerID - 1) % len(addrs)
+	if shardBy != ShardSymbol {
+		return addrs[engine], symbols
+	}
+	engine = symbolShard(symbols[(workerID-1)%len(symbols)], len(addrs))
+	var routed []string
+	for _, symbol := range symbols {
+		if symbolShard(symbol, len(addrs)) == engine {
+			routed = append(routed, symbol)
+		}
+	}
+	return addrs[engine], routed
+}
+
+// openCrossAccount creates, logs in and TCP-authenticates one account on the
+// engine at addr
+func openCrossAccount(ctx context.Context, config StressConfig, addr string, userNum int) (*crossAccount, error) {
+	var email, password string
+	err := withSignupSlot(ctx, func() (err error) {
+		email, password, err = createUser(config.FrontendURL, userNum)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create user %d: %w", userNum, err)
+	}
+	var tokens AuthTokens
+	err = withSignupSlot(ctx, func() (err error) {
+		tokens, err = loginUser(config.FrontendURL, email, password)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("login user %d: %w", userNum, err)
+	}
+
+	conn, err := dialEngine(ctx, addr, tokens.TradingToken)
+	if err != nil {
+		return nil, fmt.Errorf("user %d: %w", userNum, err)
+	}
+
+	return &crossAccount{
+		userID: userIDString(userNum),
+		tokens: tokens,
+		conn:   conn,
+	}, nil
+}
+
+// crossAccountWorker drives config.CrossAccounts accounts that trade against
+// each other: for each pair one account rests a limit sell and the next
+// account crosses it with a limit buy at the same price. Once orders settle,
+// portfolio snapshots confirm each account's position moved by exactly the
+// quantity it bought or sold.
+func crossAccountWorker(ctx context.Context, config StressConfig, workerID int, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer recoverWorkerPanic(workerID, "cross_account_worker", workerID)
+
+	// validate has already checked the engine list
+	addrs, _ := parseEngineAddrs(config.EngineAddr)
+	addr, symbols := crossAccountEngine(addrs, config.ShardBy, workerID, config.Symbols)
+
+	n := config.CrossAccounts
+	accounts := make([]*crossAccount, 0, n)
+	defer func() {
+		for _, a := range accounts {
+			a.conn.Close()
+		}
+	}()
+
+	// The pair in flight when ctx is cancelled is read until the drain ends
+	orderCtx, cancelOrders := drainContext(ctx, config.DrainTimeout)
+	defer cancelOrders()
+
+	// Open every account, all or none at a time under -max-connections
+	opened := func() bool {
+		unlock, err := lockConnect(ctx, n)
+		if err != nil {
+			return false
+		}
+		defer unlock()
+		for k := 0; k < n; k++ {
+			select {
+			case <-ctx.Done():
+				return false
+			default:
+			}
+
+			a, err := openCrossAccount(ctx, config, addr, (workerID-1)*n+k+1)
+			if err != nil {
+				slog.Warn("cross-account worker failed to open account", "worker", workerID, "err", err)
+				return false
+			}
+			accounts = append(accounts, a)
+		}
+		return true
+	}()
+	if !opened {
+		return
+	}
+
+	verify := true
+	for _, a := range accounts {
+		before, err := fetchPortfolio(config.FrontendURL, a.tokens.SessionToken)
+		if err != nil {
+			slog.Warn("cross-account initial portfolio query failed", "worker", workerID, "user_id", a.userID, "err", err)
+			atomic.AddInt64(&stats.CrossVerifyErrors, 1)
+			verify = false
+			break
+		}
+		a.before = before
+	}
+
+	// expected[i][symbol] is the net position change account i should see
+	expected := make([]map[string]int64, n)
+	for i := range expected {
+		expected[i] = make(map[string]int64)
+	}
+
+	rng := workerRand(workerID)
+	prices, err := newPriceModel(config)
+	if err != nil {
+		slog.Error("invalid cross-account price model", "worker", workerID, "err", err)
+		recordError(ErrCategoryConfig)
+		return
+	}
+	qtys, err := newQuantityModel(config)
+	if err != nil {
+		slog.Error("invalid cross-account quantity model", "worker", workerID, "err", err)
+		recordError(ErrCategoryConfig)
+		return
+	}
+
+	pairs := config.OrdersPerUser / 2
+	if pairs < 1 {
+		pairs = 1
+	}
+
+	for i := 0; i < pairs; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		seller, buyer := i%n, (i+1)%n
+		symbol := symbols[rng.Intn(len(symbols))]
+		quantity := qtys.Quantity(rng)
+		price := prices.Price(rng, symbol)
+
+		if err := waitForOrderToken(ctx); err != nil {
+			return
+		}
+		atomic.AddInt64(&stats.CrossPairsSubmitted, 1)
+
+		sell, err := submitOrderTCP(orderCtx, accounts[seller].conn, accounts[seller].userID, symbol, protocol.OrderSideSell, protocol.OrderTypeLimit, quantity, price, submitOptions{})
+		if err != nil {
+			slog.Warn("cross-account sell leg failed", "worker", workerID, "user_id", accounts[seller].userID, "symbol", symbol, "err", err)
+			continue
+		}
+		buy, err := submitOrderTCP(orderCtx, accounts[buyer].conn, accounts[buyer].userID, symbol, protocol.OrderSideBuy, protocol.OrderTypeLimit, quantity, price, submitOptions{})
+		if err != nil {
+			slog.Warn("cross-account buy leg failed", "worker", workerID, "user_id", accounts[buyer].userID, "symbol", symbol, "err", err)
+			continue
+		}
+
+		if sell.Accepted && buy.Accepted {
+			atomic.AddInt64(&stats.CrossPairsAccepted, 1)
+			expected[seller][symbol] -= quantity
+			expected[buyer][symbol] += quantity
+		}
+	}
+
+	atomic.AddInt64(&stats.UsersCompleted, 1)
+
+	if !verify {
+		return
+	}
+
+	// Give the engine time to settle trades before checking positions
+	time.Sleep(config.CrossSettle)
+
+	for i, a := range accounts {
+		after, err := fetchPortfolio(config.FrontendURL, a.tokens.SessionToken)
+		if err != nil {
+			slog.Warn("cross-account final portfolio query failed", "worker", workerID, "user_id", a.userID, "err", err)
+			atomic.AddInt64(&stats.CrossVerifyErrors, 1)
+			continue
+		}
+		for symbol, want := range expected[i] {
+			if want == 0 {
+				continue
+			}
+			got := after[symbol] - a.before[symbol]
+			if got == want {
+				atomic.AddInt64(&stats.CrossPositionsVerified, 1)
+			} else {
+				atomic.AddInt64(&stats.CrossPositionsMismatch, 1)
+				slog.Warn("cross-account position mismatch", "worker", workerID, "user_id", a.userID,
+					"symbol", symbol, "changed_by", got, "expected", want)
+			}
+		}
+	}
+}
+
+// logCrossAccount prints the cross-account fill and settlement summary
+func logCrossAccount(c *CrossAccountReport) {
+	log.Printf("Cross-Account: %d pairs submitted, %d with both legs accepted", c.PairsSubmitted, c.PairsAccepted)
+	checked := c.PositionsVerified + c.PositionsMismatched
+	if checked == 0 {
+		log.Printf("Cross-Account Settlement: not verified (%d portfolio query errors)", c.VerifyErrors)
+		return
+	}
+	log.Printf("Cross-Account Settlement: %d/%d positions settled as expected (%.1f%% fill success), %d portfolio query errors",
+		c.PositionsVerified, checked, float64(c.PositionsVerified)/float64(checked)*100, c.VerifyErrors)
+}
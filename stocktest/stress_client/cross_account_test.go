@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCrossAccountEngine(t *testing.T) {
+	addrs := []string{"a:1", "b:2", "c:3"}
+
+	// Round-robin: the worker's home engine, every symbol
+	for workerID := 1; workerID <= 6; workerID++ {
+		addr, symbols := crossAccountEngine(addrs, ShardRoundRobin, workerID, defaultSymbols)
+		if want := addrs[(workerID-1)%len(addrs)]; addr != want {
+			t.Errorf("worker %d trades on %s, want %s", workerID, addr, want)
+		}
+		if !slices.Equal(symbols, defaultSymbols) {
+			t.Errorf("worker %d trades %v, want every symbol", workerID, symbols)
+		}
+	}
+
+	// By symbol: only symbols the chosen engine handles, never none
+	for workerID := 1; workerID <= 6; workerID++ {
+		addr, symbols := crossAccountEngine(addrs, ShardSymbol, workerID, defaultSymbols)
+		if len(symbols) == 0 {
+			t.Fatalf("worker %d has no symbols on %s", workerID, addr)
+		}
+		for _, symbol := range symbols {
+			if got := addrs[symbolShard(symbol, len(addrs))]; got != addr {
+				t.Errorf("worker %d trades %s on %s, but it routes to %s", workerID, symbol, addr, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math"
+	"sync"
+
+	"stress_client/protocol"
+)
+
+// crossMinMargin is how far, as a fraction of the reference, a crossed order
+// is priced through it when -price-spread is smaller than that
+const crossMinMargin = 0.01
+
+// tradePrices tracks the last execution price per symbol, from the engine's
+// execution reports
+type tradePrices struct {
+	mu   sync.RWMutex
+	last map[string]float64
+}
+
+// lastTrades is shared by every worker; crossed orders price off it
+var lastTrades = &tradePrices{last: make(map[string]float64)}
+
+// Record notes an execution at price on symbol
+func (t *tradePrices) Record(symbol string, price float64) {
+	if price <= 0 {
+		return
+	}
+	t.mu.Lock()
+	t.last[symbol] = price
+	t.mu.Unlock()
+}
+
+// Last returns the last execution price on symbol, if any
+func (t *tradePrices) Last(symbol string) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	p, ok := t.last[symbol]
+	return p, ok
+}
+
+// crossReference is the price crossed orders on symbol are priced against:
+// the last execution the engine reported, or -price-ref before any fills
+func crossReference(config StressConfig, symbol string) float64 {
+	if p, ok := lastTrades.Last(symbol); ok {
+		return p
+	}
+	return config.PriceRef
+}
+
+// crossPrice prices an order on side through ref by the full -price-spread,
+// so a buy lands above every ask the price models generate around ref and a
+// sell below every bid
+func crossPrice(side int, ref, spread float64) float64 {
+	margin := math.Max(spread, ref*crossMinMargin)
+	if side == protocol.OrderSideBuy {
+		return ref + margin
+	}
+	return math.Max(minPrice, ref-margin)
+}
+
+// crossSpread turns p into a marketable limit order. No value is drawn, so
+// only the -cross-pct draw itself shifts the seeded stream.
+func (g *orderGenerator) crossSpread(p *orderParams) {
+	p.Type = protocol.OrderTypeLimit
+	p.Price = crossPrice(p.Side, crossReference(g.config, p.Symbol), g.config.PriceSpread)
+}
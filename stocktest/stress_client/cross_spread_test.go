@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+func TestCrossedOrdersPricedThroughReference(t *testing.T) {
+	resetGlobals(t)
+	lastTrades.Record("MSFT", 400)
+
+	config := StressConfig{
+		Symbols:     []string{"AAPL", "MSFT"},
+		OrderMix:    "market=50,limit=50",
+		PriceRef:    150,
+		PriceSpread: 50,
+		CrossPct:    100,
+	}
+	gen, err := newOrderGenerator(config, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		p := gen.Next()
+		ref := map[string]float64{"AAPL": 150, "MSFT": 400}[p.Symbol]
+		if p.Type != protocol.OrderTypeLimit {
+			t.Fatalf("crossed order has type %d, want limit", p.Type)
+		}
+		if p.Side == protocol.OrderSideBuy && p.Price <= ref {
+			t.Fatalf("crossed buy on %s at %.2f, not above reference %.2f", p.Symbol, p.Price, ref)
+		}
+		if p.Side == protocol.OrderSideSell && p.Price >= ref {
+			t.Fatalf("crossed sell on %s at %.2f, not below reference %.2f", p.Symbol, p.Price, ref)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// defaultVerifyDepthSymbol is the symbol -verify-depth rests its orders on.
+// It must not be traded by the load, so its book holds only those orders.
+const defaultVerifyDepthSymbol = "DEPTHCHK"
+
+// depthVerifyTick is the price step between -verify-depth orders, so each
+// rests at its own level
+const depthVerifyTick = 0.01
+
+// depthVerifySettle is how long the engine gets to rest the last order
+// before the book is read
+const depthVerifySettle = 500 * time.Millisecond
+
+// runDepthVerification checks for -verify-depth that every accepted order
+// reaches the book. A dedicated user rests config.VerifyDepth limit buys on
+// the verification symbol while the load runs, one per price level below
+// -price-ref with quantities 1..N, then reads the book's depth. An accepted
+// order missing from the book is a discrepancy, a correctness failure;
+// failing to log in, submit or read the book is reported as an error instead.
+func runDepthVerification(ctx context.Context, config StressConfig) DepthReport {
+	r := DepthReport{Symbol: config.DepthSymbol}
+	addrs, err := parseEngineAddrs(config.EngineAddr)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+
+	userID := config.NumUsers + 1
+	tokens, ok := signupAndLogin(ctx, config, userID)
+	if !ok {
+		r.Error = "could not log in the verification user"
+		return r
+	}
+	engines := newEnginePools(addrs, config.ShardBy, userID, 1, func(addr string) (net.Conn, error) {
+		return dialEngine(ctx, addr, tokens.TradingToken)
+	})
+	defer engines.Close()
+
+	pool := engines.For(r.Symbol)
+	conn, err := pool.Get(ctx)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r, err = verifyDepth(ctx, conn, userIDString(userID), r.Symbol, config.VerifyDepth, config.PriceRef, depthVerifySettle)
+	releaseConn(pool, conn, err)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	for _, d := range r.Discrepancies {
+		slog.Error("depth discrepancy", "symbol", r.Symbol, "detail", d)
+	}
+	return r
+}
+
+// verifyDepth rests n limit buys on symbol over conn, waits settle and
+// compares the book's bids with the accepted orders. The error is set when
+// the exchange with the engine failed, and the report then covers only the
+// orders sent before it.
+func verifyDepth(ctx context.Context, conn net.Conn, userID, symbol string, n int, priceRef float64, settle time.Duration) (DepthReport, error) {
+	r := DepthReport{Symbol: symbol}
+	var expected []protocol.BookLevel
+	for i := range n {
+		level := protocol.BookLevel{
+			Price:    math.Round((priceRef-float64(i+1)*depthVerifyTick)/depthVerifyTick) * depthVerifyTick,
+			Quantity: int64(i + 1),
+		}
+		resp, err := submitOrderTCP(ctx, conn, userID, symbol, protocol.OrderSideBuy, protocol.OrderTypeLimit,
+			level.Quantity, level.Price, submitOptions{})
+		if err != nil {
+			return r, fmt.Errorf("submit verification order %d: %w", i+1, err)
+		}
+		r.OrdersSubmitted++
+		if resp.Accepted {
+			r.OrdersAccepted++
+			expected = append(expected, level)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return r, ctx.Err()
+	case <-time.After(settle):
+	}
+	depth, err := queryDepth(conn, symbol)
+	if err != nil {
+		return r, err
+	}
+	r.LevelsExpected = len(expected)
+	r.LevelsFound = len(depth.Bids)
+	r.Discrepancies = compareDepth(expected, depth.Bids)
+	return r, nil
+}
+
+// compareDepth lists each expected bid level that is missing from bids or
+// holds less than the expected quantity. Prices are matched to the tick, so
+// an engine that stores fixed-point prices still matches.
+func compareDepth(expected, bids []protocol.BookLevel) []string {
+	ticks := func(price float64) int64 { return int64(math.Round(price / depthVerifyTick)) }
+	found := make(map[int64]int64, len(bids))
+	for _, l := range bids {
+		found[ticks(l.Price)] += l.Quantity
+	}
+
+	var discrepancies []string
+	for _, want := range expected {
+		got, ok := found[ticks(want.Price)]
+		switch {
+		case !ok:
+			discrepancies = append(discrepancies, fmt.Sprintf("bid %.2f missing, want quantity %d", want.Price, want.Quantity))
+		case got < want.Quantity:
+			discrepancies = append(discrepancies, fmt.Sprintf("bid %.2f has quantity %d, want at least %d", want.Price, got, want.Quantity))
+		}
+	}
+	return discrepancies
+}
+
+// queryDepth asks the engine for every level of symbol's book
+func queryDepth(conn net.Conn, symbol string) (protocol.BookDepth, error) {
+	if _, err := conn.Write(protocol.EncodeDepthRequest(symbol)); err != nil {
+		recordError(classifyError(err, ErrCategoryWrite))
+		return protocol.BookDepth{}, fmt.Errorf("TCP write depth request failed: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(marketDataTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	for {
+		body, err := protocol.ReadFrame(conn)
+		if err != nil {
+			recordError(classifyError(err, ErrCategoryRead))
+			return protocol.BookDepth{}, fmt.Errorf("TCP read depth failed: %w", err)
+		}
+		if len(body) > 0 && body[0] == protocol.MessageTypeHeartbeatAck {
+			continue
+		}
+		depth, err := protocol.ParseDepthResponse(body)
+		if err != nil {
+			recordError(ErrCategoryMalformedResponse)
+			return protocol.BookDepth{}, fmt.Errorf("%w: %v", errMalformedResponse, err)
+		}
+		return depth, nil
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+func TestDepthVerificationReportsLostOrder(t *testing.T) {
+	resetGlobals(t)
+
+	// The fake engine accepts every order but drops the third from its book
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		var book []protocol.BookLevel
+		for i := 0; ; i++ {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			var reply []byte
+			switch body[0] {
+			case protocol.MessageTypeSubmitOrder:
+				o, _ := protocol.DecodeSubmitOrder(body)
+				if i != 2 {
+					book = append(book, protocol.BookLevel{Price: o.Price, Quantity: o.Quantity})
+				}
+				reply = protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true})
+			case protocol.MessageTypeDepthRequest:
+				symbol, _ := protocol.DecodeDepthRequest(body)
+				reply = protocol.EncodeDepthResponse(protocol.BookDepth{Symbol: symbol, Bids: book})
+			}
+			if _, err := server.Write(reply); err != nil {
+				return
+			}
+		}
+	}()
+
+	r, err := verifyDepth(context.Background(), client, "user_1", defaultVerifyDepthSymbol, 5, 100, 0)
+	if err != nil {
+		t.Fatalf("verifyDepth: %v", err)
+	}
+	if r.OrdersSubmitted != 5 || r.OrdersAccepted != 5 || r.LevelsExpected != 5 || r.LevelsFound != 4 {
+		t.Errorf("report = %+v, want 5 accepted, 5 levels expected and 4 found", r)
+	}
+	want := []string{"bid 99.97 missing, want quantity 3"}
+	if !reflect.DeepEqual(r.Discrepancies, want) {
+		t.Errorf("discrepancies = %q, want %q", r.Discrepancies, want)
+	}
+
+	// A short level is reported too; extra quantity from other orders is not
+	expected := []protocol.BookLevel{{Price: 99.99, Quantity: 1}, {Price: 99.98, Quantity: 2}}
+	bids := []protocol.BookLevel{{Price: 99.99, Quantity: 5}, {Price: 99.98, Quantity: 1}}
+	if got := compareDepth(expected, bids); len(got) != 1 || got[0] != "bid 99.98 has quantity 1, want at least 2" {
+		t.Errorf("compareDepth = %q", got)
+	}
+}
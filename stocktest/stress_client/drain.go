@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// ordersInFlight counts orders and cancels written to the engine whose
+// response has not been read yet
+var ordersInFlight int64
+
+// drainContext returns a context that outlives ctx by timeout, for the
+// reads of orders already in flight when ctx is cancelled. They may finish
+// during the drain and are abandoned once -drain-timeout expires.
+func drainContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	drainCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(timeout, cancel)
+	})
+	return drainCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// drainWorkers waits up to timeout for every worker to finish its in-flight
+// orders and close its connections. It returns the number of orders still
+// outstanding when the timeout expired, or 0 if the drain completed.
+func drainWorkers(workersDone <-chan bool, timeout time.Duration) int64 {
+	slog.Info("draining in-flight orders", "timeout", timeout, "in_flight", atomic.LoadInt64(&ordersInFlight))
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-workersDone:
+		slog.Info("drain complete, all connections closed")
+		return 0
+	case <-timer.C:
+		abandoned := atomic.LoadInt64(&ordersInFlight)
+		slog.Warn("drain timeout expired", "abandoned", abandoned)
+		return abandoned
+	}
+}
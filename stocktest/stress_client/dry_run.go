@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// dryRun replaces the frontend and the engine with an in-memory sink
+// (-dry-run), so a run measures only the client's encode and decode path
+var dryRun bool
+
+// dryRunToken is the trading token sent in the dry-run login handshake
+const dryRunToken = "dry-run"
+
+var errDryRunNoResponse = errors.New("dry run: no response queued")
+
+// dryRunConn is a net.Conn that answers each complete frame written to it
+// with a synthetic response, the way the engine would: logins succeed,
+// orders, cancels and modifies are accepted, heartbeats are acked and book queries
+// return an empty book. Unknown frames get no reply.
+type dryRunConn struct {
+	mu     sync.Mutex
+	in     bytes.Buffer // written bytes not yet forming a whole frame
+	out    bytes.Buffer // responses waiting to be read
+	closed bool
+}
+
+func newDryRunConn() *dryRunConn {
+	return &dryRunConn{}
+}
+
+func (c *dryRunConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+	c.in.Write(p)
+
+	// Fragmented writes accumulate until a whole frame is buffered
+	for c.in.Len() >= 4 {
+		size := binary.BigEndian.Uint32(c.in.Bytes())
+		if uint32(c.in.Len()) < size {
+			break
+		}
+		body, err := protocol.ReadFrame(&c.in)
+		if err != nil {
+			return len(p), fmt.Errorf("dry run: %w", err)
+		}
+		if err := c.respond(body); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// respond queues the synthetic reply for one frame body
+func (c *dryRunConn) respond(body []byte) error {
+	if len(body) == 0 {
+		return errors.New("dry run: empty frame")
+	}
+	switch body[0] {
+	case protocol.MessageTypeLoginRequest:
+		c.out.Write(protocol.EncodeLoginResponse(protocol.LoginResponse{Success: true, Message: "dry run"}))
+	case protocol.MessageTypeSubmitOrder:
+		o, err := protocol.DecodeSubmitOrder(body)
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		c.out.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true, Message: "Order accepted"}))
+	case protocol.MessageTypeCancelOrder:
+		orderID, err := protocol.DecodeCancelOrder(body)
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		c.out.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: orderID, Accepted: true, Message: "Order cancelled"}))
+	case protocol.MessageTypeModifyOrder:
+		m, err := protocol.DecodeModifyOrder(body)
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		c.out.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: m.OrderID, Accepted: true, Message: "Order modified"}))
+	case protocol.MessageTypeHeartbeat:
+		c.out.Write(protocol.EncodeHeartbeatAck())
+	case protocol.MessageTypeMarketDataRequest:
+		symbol, err := protocol.DecodeMarketDataRequest(body)
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		c.out.Write(protocol.EncodeMarketDataResponse(protocol.TopOfBook{Symbol: symbol}))
+	}
+	return nil
+}
+
+func (c *dryRunConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.out.Len() == 0 {
+		if c.closed {
+			return 0, io.EOF
+		}
+		return 0, errDryRunNoResponse
+	}
+	return c.out.Read(p)
+}
+
+func (c *dryRunConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *dryRunConn) LocalAddr() net.Addr              { return dryRunAddr{} }
+func (c *dryRunConn) RemoteAddr() net.Addr             { return dryRunAddr{} }
+func (c *dryRunConn) SetDeadline(time.Time) error      { return nil }
+func (c *dryRunConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *dryRunConn) SetWriteDeadline(time.Time) error { return nil }
+
+type dryRunAddr struct{}
+
+func (dryRunAddr) Network() string { return "dry-run" }
+func (dryRunAddr) String() string  { return "dry-run" }
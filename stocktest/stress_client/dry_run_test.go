@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDryRunUserWorker(t *testing.T) {
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		OrdersPerUser:    200,
+		OrderConcurrency: 4,
+		FragmentPct:      25,
+		FragmentSize:     4,
+		CancelPct:        10,
+		OrderMix:         "market=25,limit=25,ioc=25,fok=25",
+		PriceModel:       PriceModelUniform,
+		PriceRef:         100,
+		PriceSpread:      10,
+		Symbols:          []string{"AAPL", "MSFT"},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	userWorkerWithContext(context.Background(), config, 1, &wg)
+
+	submitted := atomic.LoadInt64(&stats.OrdersSubmitted)
+	cancels := atomic.LoadInt64(&stats.CancelsSubmitted)
+	if submitted+cancels != int64(config.OrdersPerUser) {
+		t.Errorf("dry run sent %d orders and %d cancels, want %d slots in total", submitted, cancels, config.OrdersPerUser)
+	}
+	if accepted := atomic.LoadInt64(&stats.OrdersAccepted); accepted != submitted {
+		t.Errorf("dry run accepted %d of %d orders, want all", accepted, submitted)
+	}
+	if n := atomic.LoadInt64(&stats.Errors); n != 0 {
+		t.Errorf("dry run recorded %d errors", n)
+	}
+}
+
+func TestDryRunSymbolShardingCounts(t *testing.T) {
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "engine-a:9000,engine-b:9000,engine-c:9000",
+		ShardBy:          ShardSymbol,
+		OrdersPerUser:    300,
+		OrderConcurrency: 4,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	userWorkerWithContext(context.Background(), config, 1, &wg)
+
+	// Each engine answered exactly the orders for the symbols it owns
+	addrs, _ := parseEngineAddrs(config.EngineAddr)
+	snap := stats.snapshot()
+	want := make(map[string]int64)
+	for symbol, ss := range snap.Symbols {
+		want[addrs[symbolShard(symbol, len(addrs))]] += ss.OrdersSubmitted
+	}
+	for _, addr := range addrs {
+		got := int64(0)
+		if es := snap.Engines[addr]; es != nil {
+			got = es.OrdersSubmitted
+		}
+		if got != want[addr] {
+			t.Errorf("engine %s answered %d orders, want %d", addr, got, want[addr])
+		}
+	}
+}
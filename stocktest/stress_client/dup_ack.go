@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+// ackedOrderWindow is how many of the most recently accepted order IDs are
+// remembered, across all stats shards, to spot the engine accepting one
+// twice. At ~100 bytes per ID this bounds the memory to about 25MB.
+const ackedOrderWindow = 1 << 18
+
+// minShardAckedOrders keeps the per-shard window useful on many-core hosts
+const minShardAckedOrders = 1 << 12
+
+// orderIDWindow is a set holding the last size order IDs added to it. The
+// oldest ID is evicted when a new one arrives at capacity, so a duplicate
+// is only detected while the first acceptance is still in the window.
+type orderIDWindow struct {
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+func newOrderIDWindow(size int) *orderIDWindow {
+	return &orderIDWindow{
+		ids:  make(map[string]struct{}, size),
+		ring: make([]string, size),
+	}
+}
+
+// Add records id, reporting whether it was already in the window
+func (w *orderIDWindow) Add(id string) bool {
+	if _, ok := w.ids[id]; ok {
+		return true
+	}
+	if old := w.ring[w.next]; old != "" {
+		delete(w.ids, old)
+	}
+	w.ring[w.next] = id
+	w.ids[id] = struct{}{}
+	w.next = (w.next + 1) % len(w.ring)
+	return false
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"maps"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDuplicateAckDetected(t *testing.T) {
+	resetGlobals(t)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go serveFakeOrders(server, func(int) time.Duration { return 0 })
+
+	// The fake engine accepts whatever it is sent, so resending an order ID
+	// replays the duplicate acceptance a buggy engine would produce
+	for _, id := range []string{"order_a", "order_b", "order_a"} {
+		if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{OrderID: id}); err != nil {
+			t.Fatalf("submit %s: %v", id, err)
+		}
+	}
+
+	statsMutex.Lock()
+	categories := maps.Clone(stats.ErrorCategories)
+	statsMutex.Unlock()
+	if got := categories[ErrCategoryDuplicateAck]; got != 1 || len(categories) != 1 {
+		t.Errorf("categories = %v, want one duplicate_ack", categories)
+	}
+
+	// The window forgets the oldest ID once it is full
+	w := newOrderIDWindow(2)
+	for _, id := range []string{"a", "b", "c"} {
+		if w.Add(id) {
+			t.Errorf("Add(%q) reported a duplicate", id)
+		}
+	}
+	if w.Add("a") {
+		t.Error("evicted ID still reported as a duplicate")
+	}
+	if !w.Add("c") {
+		t.Error("ID in the window not reported as a duplicate")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// The error-rate breaker samples once a second and judges the last
+// errorBreakerWindow samples, once they hold errorBreakerMinAttempts
+// attempts, so a handful of early failures cannot trip it
+const (
+	errorBreakerInterval    = time.Second
+	errorBreakerWindow      = 10
+	errorBreakerMinAttempts = 50
+)
+
+// errorBreaker tracks the error rate over a sliding window of samples
+type errorBreaker struct {
+	maxRate float64
+	// Cumulative attempts and errors at each of the last window+1 samples
+	attempts []int64
+	errors   []int64
+}
+
+func newErrorBreaker(maxRate float64) *errorBreaker {
+	return &errorBreaker{maxRate: maxRate}
+}
+
+// observe adds a sample of the cumulative order attempts (answered orders
+// plus errors) and errors, and returns the error rate over the window and
+// whether it exceeds the limit
+func (b *errorBreaker) observe(attempts, errors int64) (rate float64, trip bool) {
+	// The warmup reset lowers the order count; start a fresh window
+	if n := len(b.attempts); n > 0 && attempts < b.attempts[n-1] {
+		b.attempts, b.errors = b.attempts[:0], b.errors[:0]
+	}
+	b.attempts = append(b.attempts, attempts)
+	b.errors = append(b.errors, errors)
+	if len(b.attempts) > errorBreakerWindow+1 {
+		b.attempts = b.attempts[1:]
+		b.errors = b.errors[1:]
+	}
+
+	dAttempts := attempts - b.attempts[0]
+	dErrors := errors - b.errors[0]
+	if dAttempts < errorBreakerMinAttempts {
+		return 0, false
+	}
+	rate = float64(dErrors) / float64(dAttempts)
+	return rate, rate > b.maxRate
+}
+
+// runErrorBreaker samples the run's error rate every interval and calls trip
+// once, with the rate, if it exceeds maxRate. It returns when ctx is
+// cancelled or after tripping.
+func runErrorBreaker(ctx context.Context, maxRate float64, interval time.Duration, trip func(rate float64)) {
+	sample := func() (attempts, errors int64, resets int) {
+		submitted, _, errors, resets := sampleOrderCounters()
+		return submitted + errors, errors, resets
+	}
+
+	// The first window is measured from the start
+	b := newErrorBreaker(maxRate)
+	attempts, errors, resets := sample()
+	b.observe(attempts, errors)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			attempts, errors, r := sample()
+			// Warmup ended and zeroed the counts, so measure from zero
+			if r != resets {
+				resets = r
+				b = newErrorBreaker(maxRate)
+				b.observe(0, 0)
+			}
+			if rate, tripped := b.observe(attempts, errors); tripped {
+				trip(rate)
+				return
+			}
+		}
+	}
+}
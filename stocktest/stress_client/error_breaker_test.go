@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestErrorBreakerTrips(t *testing.T) {
+	resetGlobals(t)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tripped := make(chan float64, 1)
+	go runErrorBreaker(ctx, 0.5, 10*time.Millisecond, func(rate float64) { tripped <- rate })
+
+	// Every order fails: the engine connection is already closed
+	client, server := net.Pipe()
+	server.Close()
+	failing := make(chan struct{})
+	defer func() { <-failing }()
+	go func() {
+		defer close(failing)
+		for ctx.Err() == nil {
+			submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{})
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+
+	select {
+	case rate := <-tripped:
+		if rate != 1 {
+			t.Errorf("tripped at error rate %v, want 1", rate)
+		}
+	case <-ctx.Done():
+		t.Fatal("breaker did not trip with every order failing")
+	}
+	cancel()
+
+	// A healthy window, or too few attempts to judge, does not trip it
+	b := newErrorBreaker(0.5)
+	b.observe(0, 0)
+	if _, trip := b.observe(errorBreakerMinAttempts-1, errorBreakerMinAttempts-1); trip {
+		t.Error("tripped before the minimum number of attempts")
+	}
+	if _, trip := b.observe(1000, 400); trip {
+		t.Error("tripped at a 40% error rate with a 50% limit")
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync/atomic"
+	"syscall"
+
+	"stress_client/protocol"
+)
+
+// Error categories reported in the final breakdown
+const (
+	ErrCategorySignup            = "signup"
+	ErrCategoryLogin             = "login"
+	ErrCategoryDial              = "dial"
+	ErrCategoryAuth              = "auth"
+	ErrCategoryWrite             = "write"
+	ErrCategoryRead              = "read"
+	ErrCategoryIOTimeout         = "io_timeout"
+	ErrCategoryConnectionClosed  = "connection_closed"
+	ErrCategoryMalformedResponse = "malformed_response"
+	ErrCategoryCorrelation       = "correlation_error"
+	ErrCategoryConfig            = "config"
+	ErrCategoryInvalidOrder      = "invalid_order"
+	ErrCategoryRateLimited       = "rate_limited"
+	ErrCategoryFrontendServer    = "frontend_server_error"
+	ErrCategoryDuplicateAck      = "duplicate_ack"
+	ErrCategoryQuery             = "query"
+	ErrCategoryWorkerPanic       = "worker_panic"
+)
+
+// errMalformedResponse marks a frame that arrived but could not be decoded
+var errMalformedResponse = errors.New("malformed response")
+
+// errCorrelation marks a response carrying a different order ID than the
+// request it answers, meaning the stream is out of step with the engine
+var errCorrelation = errors.New("order response correlation mismatch")
+
+// classifyError maps err to a category, using fallback for failures that
+// are neither timeouts, closed connections nor undecodable responses
+func classifyError(err error, fallback string) string {
+	var netErr net.Error
+	var frontendErr *FrontendError
+	switch {
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrCategoryIOTimeout
+	case isConnClosed(err):
+		return ErrCategoryConnectionClosed
+	case errors.Is(err, errMalformedResponse):
+		return ErrCategoryMalformedResponse
+	case errors.Is(err, errCorrelation):
+		return ErrCategoryCorrelation
+	case errors.Is(err, protocol.ErrInvalidOrder):
+		return ErrCategoryInvalidOrder
+	case errors.As(err, &frontendErr) && frontendErr.Status == http.StatusTooManyRequests:
+		return ErrCategoryRateLimited
+	case errors.As(err, &frontendErr) && frontendErr.Status >= http.StatusInternalServerError:
+		return ErrCategoryFrontendServer
+	default:
+		return fallback
+	}
+}
+
+// isConnClosed reports whether err means the connection was closed or reset
+// by either side
+func isConnClosed(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.ErrClosedPipe) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+// recordError counts one failure in the run stats, under its category, and
+// in Prometheus
+func recordError(category string) {
+	atomic.AddInt64(&stats.Errors, 1)
+	statsMutex.Lock()
+	if stats.ErrorCategories == nil {
+		stats.ErrorCategories = make(map[string]int64)
+	}
+	stats.ErrorCategories[category]++
+	statsMutex.Unlock()
+	errorsTotal.WithLabelValues(category).Inc()
+}
+
+// logErrorCategories prints error counts per category, most frequent first
+func logErrorCategories(categories map[string]int64) {
+	if len(categories) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if categories[names[i]] != categories[names[j]] {
+			return categories[names[i]] > categories[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	log.Printf("Error Breakdown:")
+	for _, name := range names {
+		log.Printf("  %-20s %d", name, categories[name])
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"maps"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestReadTimeoutCategorizedAsIOTimeout(t *testing.T) {
+	resetGlobals(t)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	// Accept the order but never answer it
+	go protocol.ReadFrame(server)
+
+	client.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{}); err == nil {
+		t.Fatal("submit succeeded without a response")
+	}
+
+	statsMutex.Lock()
+	categories := maps.Clone(stats.ErrorCategories)
+	statsMutex.Unlock()
+
+	if got := categories[ErrCategoryIOTimeout]; got != 1 {
+		t.Errorf("io_timeout = %d, want 1 (categories %v)", got, categories)
+	}
+	if len(categories) != 1 {
+		t.Errorf("categories = %v, want only io_timeout", categories)
+	}
+	if got := atomic.LoadInt64(&stats.Errors); got != 1 {
+		t.Errorf("Errors = %d, want 1", got)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+// Exit statuses, so CI can tell how a run ended
+const (
+	exitOK = 0
+	// More errors than -fail-error-rate allows
+	exitErrorRate = 1
+	// Errors, but not a single order answered: the engine or frontend was
+	// unreachable or refused every login
+	exitNoConnection = 2
+	// Stopped early by -max-error-rate
+	exitAborted = 3
+	// -verify-depth found accepted orders missing from the book
+	exitDepthMismatch = 4
+	// Stopped by SIGINT/SIGTERM, following the shell's 128+SIGINT convention
+	exitInterrupted = 130
+)
+
+// exitCode decides the exit status from the final report. An interrupted
+// run exits 130 whatever its results, since they cover only part of the
+// workload.
+func exitCode(r Report, failErrorRate float64) int {
+	switch {
+	case r.Interrupted:
+		return exitInterrupted
+	case r.Aborted != "":
+		return exitAborted
+	case r.DepthVerification != nil && len(r.DepthVerification.Discrepancies) > 0:
+		return exitDepthMismatch
+	case r.OrdersSubmitted == 0 && r.Errors > 0:
+		return exitNoConnection
+	}
+	if attempts := r.OrdersSubmitted + r.Errors; attempts > 0 {
+		if float64(r.Errors)/float64(attempts) > failErrorRate {
+			return exitErrorRate
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		report Report
+		want   int
+	}{
+		{"clean run", Report{OrdersSubmitted: 1000}, exitOK},
+		{"errors within threshold", Report{OrdersSubmitted: 1000, Errors: 10}, exitOK},
+		{"errors over threshold", Report{OrdersSubmitted: 1000, Errors: 11}, exitErrorRate},
+		{"nothing connected", Report{Errors: 10}, exitNoConnection},
+		{"nothing to do", Report{}, exitOK},
+		{"aborted by breaker", Report{OrdersSubmitted: 10, Errors: 90, Aborted: "error rate"}, exitAborted},
+		{"book lost an order", Report{OrdersSubmitted: 1000, DepthVerification: &DepthReport{Discrepancies: []string{"bid 149.99 missing"}}}, exitDepthMismatch},
+		{"depth check incomplete", Report{OrdersSubmitted: 1000, DepthVerification: &DepthReport{Error: "timeout"}}, exitOK},
+		{"interrupted", Report{OrdersSubmitted: 10, Errors: 90, Interrupted: true}, exitInterrupted},
+	}
+	for _, tt := range tests {
+		// 10 errors in 1010 attempts is just under 1%; 11 in 1011 is over
+		if got := exitCode(tt.report, 0.01); got != tt.want {
+			t.Errorf("%s: exit code = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"stress_client/protocol"
+)
+
+const (
+	// maxPendingFills bounds the marketable orders waiting for a fill; when
+	// full, the oldest gives way
+	maxPendingFills = 1 << 16
+	// fillWindow is how long an order waits for its fill before it is
+	// expired; an IOC or FOK order that finds no liquidity is never filled
+	fillWindow = time.Minute
+)
+
+// pendingFill is a marketable order sent and not yet filled
+type pendingFill struct {
+	submitted time.Time
+	shard     int
+	seq       uint64 // matches the order's entry in the queue
+}
+
+// queuedFill is one tracked order in submission order. Entries whose order
+// was filled or forgotten stay queued until they reach the front.
+type queuedFill struct {
+	orderID   string
+	submitted time.Time
+	seq       uint64
+}
+
+// fillTracker correlates execution reports pushed by the engine with the
+// marketable orders that caused them, measuring submit-to-fill latency: the
+// full round-trip through matching, where order latency stops at the
+// engine's acknowledgement. Orders are also queued oldest first in a ring,
+// so expiring them costs O(1) per order instead of a scan of pending.
+type fillTracker struct {
+	mu      sync.Mutex
+	pending map[string]pendingFill
+	queue   []queuedFill // ring of maxPendingFills entries
+	head    int
+	size    int
+	seq     uint64
+}
+
+// pendingFills tracks the orders of every connection; execution reports
+// arrive on reader goroutines that know nothing of the submitting worker.
+// It is nil, and tracks nothing, unless -track-fills is set.
+var pendingFills *fillTracker
+
+// newFillTracker returns a tracker, or nil when fills are not tracked
+func newFillTracker(enabled bool) *fillTracker {
+	if !enabled {
+		return nil
+	}
+	return &fillTracker{
+		pending: make(map[string]pendingFill),
+		queue:   make([]queuedFill, maxPendingFills),
+	}
+}
+
+// marketable reports whether an order of orderType should trade on arrival.
+// Limit orders may rest, so their fills say nothing about matching latency.
+func marketable(orderType int) bool {
+	return orderType != protocol.OrderTypeLimit
+}
+
+// Track starts waiting for the first fill of orderID, sent at submitted and
+// recorded into stats shard shard
+func (t *fillTracker) Track(orderID string, shard int, submitted time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Drop the front while it is settled or past its window, then make room
+	for t.size > 0 {
+		q := t.queue[t.head]
+		if p, ok := t.pending[q.orderID]; ok && p.seq == q.seq && submitted.Sub(q.submitted) <= fillWindow {
+			break
+		}
+		t.pop()
+	}
+	if t.size == len(t.queue) {
+		t.pop()
+	}
+	t.seq++
+	t.pending[orderID] = pendingFill{submitted: submitted, shard: shard, seq: t.seq}
+	t.queue[(t.head+t.size)%len(t.queue)] = queuedFill{orderID: orderID, submitted: submitted, seq: t.seq}
+	t.size++
+}
+
+// pop removes the oldest queued order, and its pending entry unless the
+// order was tracked again since
+func (t *fillTracker) pop() {
+	q := t.queue[t.head]
+	if p, ok := t.pending[q.orderID]; ok && p.seq == q.seq {
+		delete(t.pending, q.orderID)
+	}
+	t.queue[t.head] = queuedFill{}
+	t.head = (t.head + 1) % len(t.queue)
+	t.size--
+}
+
+// Forget stops waiting for orderID, which failed or was rejected
+func (t *fillTracker) Forget(orderID string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	delete(t.pending, orderID)
+	t.mu.Unlock()
+}
+
+// Fill records the submit-to-fill latency of orderID's first execution
+// report, received at at. Later partial fills and orders not tracked are
+// ignored.
+func (t *fillTracker) Fill(orderID string, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	p, ok := t.pending[orderID]
+	delete(t.pending, orderID)
+	t.mu.Unlock()
+	if ok {
+		stats.shard(p.shard).recordFill(at.Sub(p.submitted))
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestFillTrackerExpiresOldestFirst(t *testing.T) {
+	resetGlobals(t)
+
+	fills := func() int64 {
+		snap := stats.snapshot()
+		if snap.SubmitToFillLatencies == nil {
+			return 0
+		}
+		return snap.SubmitToFillLatencies.Count()
+	}
+
+	// A nil tracker, as without -track-fills, records nothing
+	var off *fillTracker
+	off.Track("order_0", 0, time.Now())
+	off.Fill("order_0", time.Now())
+	off.Forget("order_0")
+
+	// An order past the window is expired by the next one tracked; one
+	// just inside it is kept
+	tracker := newFillTracker(true)
+	start := time.Now()
+	tracker.Track("old", 0, start)
+	tracker.Track("kept", 0, start.Add(time.Second))
+	tracker.Track("new", 0, start.Add(time.Second+fillWindow))
+	tracker.Fill("old", start.Add(fillWindow))
+	if got := fills(); got != 0 {
+		t.Fatalf("%d fills recorded for an expired order, want 0", got)
+	}
+	tracker.Fill("kept", start.Add(fillWindow))
+	if got := fills(); got != 1 {
+		t.Fatalf("%d fills recorded for an order inside the window, want 1", got)
+	}
+
+	// At the cap the oldest order gives way
+	tracker = newFillTracker(true)
+	for i := 0; i <= maxPendingFills; i++ {
+		tracker.Track(fmt.Sprintf("order_%d", i), 0, start)
+	}
+	if len(tracker.pending) != maxPendingFills {
+		t.Errorf("%d orders pending, want the cap of %d", len(tracker.pending), maxPendingFills)
+	}
+	if _, ok := tracker.pending["order_0"]; ok {
+		t.Error("oldest order still pending past the cap")
+	}
+	tracker.Fill(fmt.Sprintf("order_%d", maxPendingFills), start.Add(time.Millisecond))
+	if got := fills(); got != 2 {
+		t.Errorf("%d fills recorded, want the newest order's too", got)
+	}
+}
+
+func BenchmarkFillTrackerAtCap(b *testing.B) {
+	tracker := newFillTracker(true)
+	start := time.Now()
+	for i := 0; i < maxPendingFills; i++ {
+		tracker.Track(fmt.Sprintf("warm_%d", i), 0, start)
+	}
+	ids := make([]string, 1024)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("order_%d", i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tracker.Track(ids[i%len(ids)], 0, start)
+	}
+}
+
+func TestSubmitToFillLatency(t *testing.T) {
+	resetGlobals(t)
+	pendingFills = newFillTracker(true)
+
+	raw, server := net.Pipe()
+	client := newPushConn(raw, recordPush)
+	// Stop the reader, which fills through pendingFills, before restoring it
+	t.Cleanup(func() {
+		client.Close()
+		<-client.done
+	})
+
+	// The engine acknowledges each order at once and pushes its fill later.
+	// The limit order's fill is not measured.
+	const fillDelay = 50 * time.Millisecond
+	go func() {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, _ := protocol.DecodeSubmitOrder(body)
+			server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true, Message: "Order accepted"}))
+			time.Sleep(fillDelay)
+			server.Write(protocol.EncodeExecutionReport(protocol.ExecutionReport{OrderID: o.OrderID, Symbol: o.Symbol, Quantity: o.Quantity, Price: 100}))
+		}
+	}()
+
+	for _, orderType := range []int{protocol.OrderTypeMarket, protocol.OrderTypeLimit} {
+		if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, orderType, 1, 100, submitOptions{}); err != nil {
+			t.Fatalf("order type %d: %v", orderType, err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt64(&stats.PushMessages) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	snap := stats.snapshot()
+	fills := snap.SubmitToFillLatencies
+	if fills.Count() != 1 {
+		t.Fatalf("%d fill latencies recorded, want 1 for the market order", fills.Count())
+	}
+	if fills.Min() < fillDelay {
+		t.Errorf("submit-to-fill latency %v, want at least the %v fill delay", fills.Min(), fillDelay)
+	}
+	if order := snap.OrderLatencies.Min(); order >= fillDelay {
+		t.Errorf("order latency %v includes the fill delay", order)
+	}
+	r := buildReport(&snap, StressConfig{}, time.Second, false)
+	if r.SubmitToFillLatency == nil || r.SubmitToFillLatency.Count != 1 {
+		t.Errorf("submit_to_fill_latency = %+v, want 1 fill", r.SubmitToFillLatency)
+	}
+}
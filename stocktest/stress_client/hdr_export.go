@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// HDR histogram range: 1ns up to 60s with 3 significant digits, which
+// covers every order latency we can realistically observe.
+const (
+	hdrLowestTrackable  = 1
+	hdrHighestTrackable = int64(60 * time.Second)
+	hdrSignificantFigs  = 3
+)
+
+// newOrderHistogram returns an empty histogram covering the order latency range
+func newOrderHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(hdrLowestTrackable, hdrHighestTrackable, hdrSignificantFigs)
+}
+
+// recordHistogramValue records d in nanoseconds, clamped to the trackable range
+func recordHistogramValue(h *hdrhistogram.Histogram, d time.Duration) {
+	v := int64(d)
+	if v < hdrLowestTrackable {
+		v = hdrLowestTrackable
+	}
+	if v > hdrHighestTrackable {
+		v = hdrHighestTrackable
+	}
+	h.RecordValue(v)
+}
+
+// stampOrderHistogram sets the interval times and tag written to the log
+func stampOrderHistogram(h *hdrhistogram.Histogram, startTime, endTime time.Time) {
+	h.SetStartTimeMs(startTime.UnixMilli())
+	h.SetEndTimeMs(endTime.UnixMilli())
+	h.SetTag("order_latency")
+}
+
+// writeHDRHistogram writes the order latency histogram as a standard
+// HdrHistogram log (.hlog) containing a single V2-compressed interval, so the
+// file loads directly into HistogramLogProcessor and the HdrHistogram plotter.
+// The interval runs from startTime, the start of the measured phase, to
+// endTime.
+func writeHDRHistogram(path string, latencies LatencyRecorder, startTime, endTime time.Time) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create HDR file: %w", err)
+	}
+	defer f.Close()
+
+	h := latencies.Histogram()
+	stampOrderHistogram(h, startTime, endTime)
+
+	lw := hdrhistogram.NewHistogramLogWriter(f)
+	lw.SetBaseTime(startTime.UnixMilli())
+	if err := lw.OutputLogFormatVersion(); err != nil {
+		return fmt.Errorf("failed to write HDR header: %w", err)
+	}
+	if err := lw.OutputStartTime(startTime.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to write HDR header: %w", err)
+	}
+	if err := lw.OutputBaseTime(startTime.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to write HDR header: %w", err)
+	}
+	if err := lw.OutputLegend(); err != nil {
+		return fmt.Errorf("failed to write HDR header: %w", err)
+	}
+	if err := lw.OutputIntervalHistogram(h); err != nil {
+		return fmt.Errorf("failed to write HDR histogram: %w", err)
+	}
+
+	return f.Close()
+}
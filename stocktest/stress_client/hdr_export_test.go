@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// readHDRLog decodes the single interval histogram in an HdrHistogram log
+func readHDRLog(t *testing.T, path string) (header []byte, h *hdrhistogram.Histogram) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err = hdrhistogram.NewHistogramLogReader(bytes.NewReader(data)).NextIntervalHistogram()
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if h == nil {
+		t.Fatalf("no interval histogram in %s", path)
+	}
+	// Comment lines and the legend, everything before the first interval
+	if i := bytes.Index(data, []byte("\nTag=")); i >= 0 {
+		header = data[:i]
+	}
+	return header, h
+}
+
+// TestWriteHDRHistogram checks the export against testdata/order_latency.hlog,
+// written for the same samples by hdrhistogram-go's histogram and log writer
+// (see testdata/gen_order_latency_hlog.go), not by the code under test
+func TestWriteHDRHistogram(t *testing.T) {
+	recorder := newHDRRecorder()
+	for i := 1; i <= 1000; i++ {
+		recorder.Record(time.Duration(i) * time.Microsecond)
+	}
+	start := time.Unix(1700000000, 0)
+	end := start.Add(10 * time.Second)
+
+	path := filepath.Join(t.TempDir(), "latency.hlog")
+	if err := writeHDRHistogram(path, recorder, start, end); err != nil {
+		t.Fatalf("writeHDRHistogram: %v", err)
+	}
+
+	gotHeader, got := readHDRLog(t, path)
+	wantHeader, want := readHDRLog(t, filepath.Join("testdata", "order_latency.hlog"))
+	if !bytes.Equal(gotHeader, wantHeader) {
+		t.Errorf("log header:\n%s\nwant:\n%s", gotHeader, wantHeader)
+	}
+	if got.TotalCount() != want.TotalCount() {
+		t.Errorf("TotalCount = %d, want %d", got.TotalCount(), want.TotalCount())
+	}
+	for _, q := range []float64{0, 50, 90, 99, 99.9, 100} {
+		if g, w := got.ValueAtQuantile(q), want.ValueAtQuantile(q); g != w {
+			t.Errorf("p%v = %d, want %d", q, g, w)
+		}
+	}
+	if got.StartTimeMs() != want.StartTimeMs() || got.EndTimeMs() != want.EndTimeMs() {
+		t.Errorf("interval %d-%d, want %d-%d", got.StartTimeMs(), got.EndTimeMs(), want.StartTimeMs(), want.EndTimeMs())
+	}
+	if got.Tag() != want.Tag() {
+		t.Errorf("tag %q, want %q", got.Tag(), want.Tag())
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// Maximum time to wait for a heartbeat ack before counting it as missed
+const heartbeatAckTimeout = 5 * time.Second
+
+// connHealth tracks heartbeat state for one engine connection
+type connHealth struct {
+	missed    int32 // consecutive missed acks
+	unhealthy int32
+}
+
+func (h *connHealth) Unhealthy() bool {
+	return atomic.LoadInt32(&h.unhealthy) == 1
+}
+
+// runHeartbeat sends a heartbeat every interval on a pooled connection and
+// waits for the ack. Heartbeats share connections with orders, so each
+// exchange checks a connection out of the pool; one that misses its ack is
+// replaced. After maxMisses consecutive missed acks the connection is marked
+// unhealthy and the loop exits.
+func runHeartbeat(ctx context.Context, pool *ConnPool, interval time.Duration, maxMisses int, health *connHealth) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	timeout := heartbeatAckTimeout
+	if interval < timeout {
+		timeout = interval
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		conn, err := pool.Get(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+		} else {
+			err = sendHeartbeat(conn, timeout)
+			releaseConn(pool, conn, err)
+		}
+
+		if err == nil {
+			atomic.AddInt64(&stats.HeartbeatAcks, 1)
+			atomic.StoreInt32(&health.missed, 0)
+			continue
+		}
+
+		atomic.AddInt64(&stats.HeartbeatsMissed, 1)
+		missed := atomic.AddInt32(&health.missed, 1)
+		if int(missed) >= maxMisses {
+			atomic.StoreInt32(&health.unhealthy, 1)
+			atomic.AddInt64(&stats.UnhealthyConns, 1)
+			slog.Warn("connection marked unhealthy", "missed_heartbeats", missed, "err", err)
+			return
+		}
+	}
+}
+
+// sendHeartbeat performs one heartbeat round trip bounded by timeout
+func sendHeartbeat(conn net.Conn, timeout time.Duration) error {
+	conn.SetDeadline(time.Now().Add(timeout))
+	defer conn.SetDeadline(time.Time{})
+
+	atomic.AddInt64(&stats.HeartbeatsSent, 1)
+	if _, err := conn.Write(protocol.EncodeHeartbeat()); err != nil {
+		return err
+	}
+	return protocol.DecodeHeartbeatAck(conn)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestHeartbeatAcked(t *testing.T) {
+	pool := pipePool(func(server net.Conn) {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			if body[0] == protocol.MessageTypeHeartbeat {
+				server.Write(protocol.EncodeHeartbeatAck())
+			}
+		}
+	})
+	defer pool.Close()
+
+	before := atomic.LoadInt64(&stats.HeartbeatAcks)
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+
+	health := &connHealth{}
+	runHeartbeat(ctx, pool, 20*time.Millisecond, 2, health)
+
+	if health.Unhealthy() {
+		t.Fatal("connection marked unhealthy despite acks")
+	}
+	if acks := atomic.LoadInt64(&stats.HeartbeatAcks) - before; acks < 2 {
+		t.Errorf("got %d acks, want at least 2", acks)
+	}
+}
+
+func TestHeartbeatMissesMarkUnhealthy(t *testing.T) {
+	// Drain requests but never ack
+	pool := pipePool(func(server net.Conn) {
+		defer server.Close()
+		io.Copy(io.Discard, server)
+	})
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	health := &connHealth{}
+	runHeartbeat(ctx, pool, 20*time.Millisecond, 3, health)
+
+	if !health.Unhealthy() {
+		t.Fatal("connection not marked unhealthy after missed acks")
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// resetGlobals gives the test fresh run stats, no fill tracker and no last
+// trade prices. The tracker and trade prices in place before are put back
+// when the test ends, so tests that push fills or trades cannot leak them
+// into later tests or a second -count run.
+func resetGlobals(t testing.TB) {
+	t.Helper()
+	statsMutex.Lock()
+	stats = newStressStats()
+	statsMutex.Unlock()
+
+	savedFills, savedTrades := pendingFills, lastTrades
+	pendingFills = nil
+	lastTrades = &tradePrices{last: make(map[string]float64)}
+	t.Cleanup(func() {
+		pendingFills, lastTrades = savedFills, savedTrades
+	})
+}
+
+// serveFakeOrders answers every submitted order on conn with an acceptance,
+// sleeping delay(i) before the i-th response.
+func serveFakeOrders(conn net.Conn, delay func(i int) time.Duration) {
+	defer conn.Close()
+	for i := 0; ; i++ {
+		body, err := protocol.ReadFrame(conn)
+		if err != nil {
+			return
+		}
+		o, err := protocol.DecodeSubmitOrder(body)
+		if err != nil {
+			return
+		}
+		time.Sleep(delay(i))
+		resp := protocol.OrderResponse{OrderID: o.OrderID, Accepted: true, Message: "Order accepted"}
+		if _, err := conn.Write(protocol.EncodeOrderResponse(resp)); err != nil {
+			return
+		}
+	}
+}
+
+// pipePool returns a pool whose connections are net.Pipe clients, each with
+// serve running on the server end
+func pipePool(serve func(server net.Conn)) *ConnPool {
+	return NewConnPool(1, func() (net.Conn, error) {
+		client, server := net.Pipe()
+		go serve(server)
+		return client, nil
+	})
+}
+
+// countingWriter counts log lines containing a marker
+type countingWriter struct {
+	mu     sync.Mutex
+	marker string
+	n      int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.n += strings.Count(string(p), w.marker)
+	return len(p), nil
+}
+
+func (w *countingWriter) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.n
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// frontendIdleTimeout is how long an unused keep-alive connection to the
+// frontend stays open
+const frontendIdleTimeout = 90 * time.Second
+
+// frontendClient is shared by every signup, login and portfolio request so
+// connections to the frontend are reused instead of reopened per call
+var frontendClient = newFrontendClient(30*time.Second, 50)
+
+// newFrontendClient returns a client that keeps up to maxIdle connections to
+// the frontend alive. The default transport keeps only two per host, so with
+// more concurrent users most connections would be closed after one request.
+func newFrontendClient(timeout time.Duration, maxIdle int) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdle
+	transport.MaxIdleConnsPerHost = maxIdle
+	transport.IdleConnTimeout = frontendIdleTimeout
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// Frontend 429 handling: a rate-limited signup or login is retried up to
+// rateLimitRetries times, after the response's Retry-After or, without one,
+// a backoff doubling from rateLimitBaseDelay. Either wait is capped at
+// rateLimitMaxDelay so a long Retry-After cannot stall a worker.
+const (
+	rateLimitRetries   = 5
+	rateLimitBaseDelay = 250 * time.Millisecond
+	rateLimitMaxDelay  = 10 * time.Second
+)
+
+// postFrontend POSTs a JSON body to url, retrying while the frontend
+// answers 429 Too Many Requests. latency is that of the last attempt. The
+// last 429 is returned like any other response once the retries run out.
+func postFrontend(url string, body []byte) (resp *http.Response, latency time.Duration, err error) {
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err = frontendClient.Post(url, "application/json", bytes.NewReader(body))
+		latency = time.Since(start)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= rateLimitRetries {
+			return resp, latency, err
+		}
+
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			wait = rateLimitBackoff(attempt)
+		}
+		wait = min(wait, rateLimitMaxDelay)
+		io.Copy(io.Discard, io.LimitReader(resp.Body, frontendErrorBodyLimit))
+		resp.Body.Close()
+
+		atomic.AddInt64(&stats.RateLimitedRetries, 1)
+		slog.Debug("frontend rate limited, retrying", "url", url, "attempt", attempt+1, "wait", wait)
+		time.Sleep(wait)
+	}
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP
+// date, into the wait from now. ok is false when the header is missing or
+// malformed.
+func retryAfter(header string, now time.Time) (wait time.Duration, ok bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// rateLimitBackoff returns the jittered wait before rate-limit retry
+// attempt (0-based) when the frontend gave no Retry-After
+func rateLimitBackoff(attempt int) time.Duration {
+	d := rateLimitMaxDelay
+	if attempt < 16 {
+		d = min(rateLimitBaseDelay<<attempt, rateLimitMaxDelay)
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// frontendErrorBodyLimit caps how much of an error response body is read
+const frontendErrorBodyLimit = 4 << 10
+
+// FrontendError is a non-2xx response from the frontend. Code and Message
+// come from the frontend's {"message", "code"} error body; a body that is
+// not that JSON is kept whole in Message.
+type FrontendError struct {
+	Op      string // signup, login or portfolio
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *FrontendError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s failed with status %d (%s): %s", e.Op, e.Status, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s failed with status %d: %s", e.Op, e.Status, e.Message)
+}
+
+// newFrontendError reads the error body of resp, the failed response to op
+func newFrontendError(op string, resp *http.Response) *FrontendError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, frontendErrorBodyLimit))
+	e := &FrontendError{Op: op, Status: resp.StatusCode}
+
+	var payload struct {
+		Message string          `json:"message"`
+		Code    json.RawMessage `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) != nil || (payload.Message == "" && len(payload.Code) == 0) {
+		e.Message = strings.TrimSpace(string(body))
+		return e
+	}
+	e.Message = payload.Message
+	// Codes are usually strings, but numeric codes are kept as written
+	if err := json.Unmarshal(payload.Code, &e.Code); err != nil {
+		e.Code = string(payload.Code)
+	}
+	return e
+}
+
+// userExists reports whether a signup failed because the account is already
+// registered: a 409 Conflict, or an error message saying so
+func (e *FrontendError) userExists() bool {
+	if e.Status == http.StatusConflict {
+		return true
+	}
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "already exists") || strings.Contains(msg, "already registered")
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFrontendClientReusesConnections(t *testing.T) {
+	var newConns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		io.WriteString(w, `{"tokens":{"tradingToken":"token"}}`)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	saved := frontendClient
+	frontendClient = newFrontendClient(5*time.Second, 4)
+	defer func() { frontendClient = saved }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	const calls = 50
+	for i := 0; i < calls; i++ {
+		if _, err := loginUser(srv.URL, "user@example.com", "pw"); err != nil {
+			t.Fatalf("login %d: %v", i, err)
+		}
+	}
+
+	if n := newConns.Load(); n != 1 {
+		t.Errorf("%d sequential logins opened %d connections, want 1", calls, n)
+	}
+}
+
+func TestFrontendErrorBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        FrontendError
+		category    string
+	}{
+		{
+			name:        "json",
+			status:      http.StatusConflict,
+			contentType: "application/json",
+			body:        `{"message":"Email already registered","code":"EMAIL_TAKEN"}`,
+			want:        FrontendError{Op: "login", Status: http.StatusConflict, Code: "EMAIL_TAKEN", Message: "Email already registered"},
+			category:    ErrCategoryLogin,
+		},
+		{
+			name:        "json numeric code",
+			status:      http.StatusTooManyRequests,
+			contentType: "application/json",
+			body:        `{"message":"Slow down","code":4291}`,
+			want:        FrontendError{Op: "login", Status: http.StatusTooManyRequests, Code: "4291", Message: "Slow down"},
+			category:    ErrCategoryRateLimited,
+		},
+		{
+			name:        "plain text",
+			status:      http.StatusBadGateway,
+			contentType: "text/plain",
+			body:        "upstream connect error\n",
+			want:        FrontendError{Op: "login", Status: http.StatusBadGateway, Message: "upstream connect error"},
+			category:    ErrCategoryFrontendServer,
+		},
+		{
+			name:        "unrelated json",
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+			body:        `{"error":"bad"}`,
+			want:        FrontendError{Op: "login", Status: http.StatusBadRequest, Message: `{"error":"bad"}`},
+			category:    ErrCategoryLogin,
+		},
+	}
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", tt.contentType)
+			// A 429 is retried; fail it again at once
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(tt.status)
+			io.WriteString(w, tt.body)
+		}))
+
+		resetGlobals(t)
+
+		_, err := loginUser(srv.URL, "user@example.com", "pw")
+		srv.Close()
+
+		var got *FrontendError
+		if !errors.As(err, &got) {
+			t.Errorf("%s: err = %v, want a *FrontendError", tt.name, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("%s: error = %+v, want %+v", tt.name, *got, tt.want)
+		}
+		if n := stats.ErrorCategories[tt.category]; n != 1 || len(stats.ErrorCategories) != 1 {
+			t.Errorf("%s: categories = %v, want one %s", tt.name, stats.ErrorCategories, tt.category)
+		}
+	}
+}
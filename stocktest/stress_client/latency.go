@@ -0,0 +1,289 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// latencySampleCap bounds every latency reservoir (set by -latency-samples)
+var latencySampleCap = 100000
+
+// Latency recorder implementations selectable with -histogram
+const (
+	HistogramReservoir = "reservoir"
+	HistogramHDR       = "hdr"
+)
+
+// latencyHistogram selects the implementation returned by newLatencyRecorder
+var latencyHistogram = HistogramReservoir
+
+// LatencyRecorder accumulates latency observations for one metric. Count,
+// Min, Max and Mean are exact; percentiles are estimates whose accuracy
+// depends on the implementation. Implementations are not safe for
+// concurrent use; callers hold the lock of the stats shard that owns them.
+type LatencyRecorder interface {
+	Record(d time.Duration)
+	// Merge folds in the observations of other, which must be of the same
+	// implementation
+	Merge(other LatencyRecorder)
+	Count() int64
+	Min() time.Duration
+	Max() time.Duration
+	Mean() time.Duration
+	Percentile(q float64) time.Duration
+	Percentiles(qs ...float64) []time.Duration
+	// Histogram returns the observations as a new HdrHistogram for export
+	Histogram() *hdrhistogram.Histogram
+}
+
+// newLatencyRecorder returns an empty recorder of the -histogram kind
+func newLatencyRecorder() LatencyRecorder {
+	if latencyHistogram == HistogramHDR {
+		return newHDRRecorder()
+	}
+	return &latencyReservoir{}
+}
+
+// validateHistogram reports whether kind names a latency recorder
+func validateHistogram(kind string) error {
+	switch kind {
+	case HistogramReservoir, HistogramHDR:
+		return nil
+	}
+	return fmt.Errorf("unknown histogram %q (want %s or %s)", kind, HistogramReservoir, HistogramHDR)
+}
+
+// latencyReservoir keeps a fixed-size uniform sample of latencies using
+// Vitter's Algorithm R, so memory stays bounded on long runs. Count, sum,
+// min and max are tracked exactly. The zero value is ready to use; callers
+// synchronize access.
+type latencyReservoir struct {
+	samples  []time.Duration
+	capacity int
+	count    int64
+	sum      time.Duration
+	min      time.Duration
+	max      time.Duration
+}
+
+// Record adds one latency observation
+func (r *latencyReservoir) Record(d time.Duration) {
+	if r.capacity == 0 {
+		r.capacity = latencySampleCap
+	}
+
+	r.count++
+	r.sum += d
+	if r.count == 1 || d < r.min {
+		r.min = d
+	}
+	if d > r.max {
+		r.max = d
+	}
+
+	if len(r.samples) < r.capacity {
+		r.samples = append(r.samples, d)
+		return
+	}
+	// Replace a random slot with probability capacity/count
+	if j := rand.Int63n(r.count); j < int64(r.capacity) {
+		r.samples[j] = d
+	}
+}
+
+// Merge folds other's observations into r. Count, sum, min and max stay
+// exact. When the combined samples exceed capacity, each side keeps a random
+// share of the slots proportional to its count, so the result is still a
+// uniform sample of every observation.
+func (r *latencyReservoir) Merge(other LatencyRecorder) {
+	o := other.(*latencyReservoir)
+	if o.count == 0 {
+		return
+	}
+	if r.capacity == 0 {
+		r.capacity = latencySampleCap
+	}
+
+	if r.count == 0 || o.min < r.min {
+		r.min = o.min
+	}
+	if o.max > r.max {
+		r.max = o.max
+	}
+	// Both sides still hold every observation and fit together
+	if int64(len(r.samples)) == r.count && int64(len(o.samples)) == o.count &&
+		len(r.samples)+len(o.samples) <= r.capacity {
+		r.samples = append(r.samples, o.samples...)
+		r.count += o.count
+		r.sum += o.sum
+		return
+	}
+
+	total := r.count + o.count
+	k := min(r.capacity, len(r.samples)+len(o.samples))
+	keepR := min(int(float64(k)*float64(r.count)/float64(total)+0.5), len(r.samples))
+	keepO := min(k-keepR, len(o.samples))
+	keepR = min(k-keepO, len(r.samples))
+
+	merged := make([]time.Duration, 0, k)
+	merged = append(merged, sampleWithoutReplacement(r.samples, keepR)...)
+	merged = append(merged, sampleWithoutReplacement(o.samples, keepO)...)
+	r.samples = merged
+	r.count = total
+	r.sum += o.sum
+}
+
+// sampleWithoutReplacement returns k distinct elements of s chosen uniformly
+// at random, leaving s unchanged
+func sampleWithoutReplacement(s []time.Duration, k int) []time.Duration {
+	out := make([]time.Duration, len(s))
+	copy(out, s)
+	for i := 0; i < k; i++ {
+		j := i + rand.Intn(len(out)-i)
+		out[i], out[j] = out[j], out[i]
+	}
+	return out[:k]
+}
+
+// Count returns the total number of observations recorded
+func (r *latencyReservoir) Count() int64 { return r.count }
+
+// Min returns the exact minimum observation
+func (r *latencyReservoir) Min() time.Duration { return r.min }
+
+// Max returns the exact maximum observation
+func (r *latencyReservoir) Max() time.Duration { return r.max }
+
+// Mean returns the exact mean of all observations
+func (r *latencyReservoir) Mean() time.Duration {
+	if r.count == 0 {
+		return 0
+	}
+	return r.sum / time.Duration(r.count)
+}
+
+// Samples returns a copy of the retained samples
+func (r *latencyReservoir) Samples() []time.Duration {
+	out := make([]time.Duration, len(r.samples))
+	copy(out, r.samples)
+	return out
+}
+
+// Percentile estimates the q-quantile (0..1) from the retained samples
+func (r *latencyReservoir) Percentile(q float64) time.Duration {
+	return percentile(r.samples, q)
+}
+
+// Percentiles estimates several quantiles with a single sort
+func (r *latencyReservoir) Percentiles(qs ...float64) []time.Duration {
+	sorted := r.Samples()
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	out := make([]time.Duration, len(qs))
+	for i, q := range qs {
+		out[i] = percentileSorted(sorted, q)
+	}
+	return out
+}
+
+// Histogram builds an HdrHistogram from the retained samples
+func (r *latencyReservoir) Histogram() *hdrhistogram.Histogram {
+	h := newOrderHistogram()
+	for _, d := range r.samples {
+		recordHistogramValue(h, d)
+	}
+	return h
+}
+
+// hdrRecorder records every observation into an HdrHistogram, so memory is
+// fixed and percentiles stay within the histogram's precision (3
+// significant digits) no matter how many orders are sent.
+type hdrRecorder struct {
+	h   *hdrhistogram.Histogram
+	sum time.Duration
+	min time.Duration
+	max time.Duration
+}
+
+func newHDRRecorder() *hdrRecorder {
+	return &hdrRecorder{h: newOrderHistogram()}
+}
+
+// Record adds one latency observation
+func (r *hdrRecorder) Record(d time.Duration) {
+	if r.h.TotalCount() == 0 || d < r.min {
+		r.min = d
+	}
+	if d > r.max {
+		r.max = d
+	}
+	r.sum += d
+	recordHistogramValue(r.h, d)
+}
+
+// Merge folds other's histogram into r
+func (r *hdrRecorder) Merge(other LatencyRecorder) {
+	o := other.(*hdrRecorder)
+	if o.h.TotalCount() == 0 {
+		return
+	}
+	if r.h.TotalCount() == 0 || o.min < r.min {
+		r.min = o.min
+	}
+	if o.max > r.max {
+		r.max = o.max
+	}
+	r.sum += o.sum
+	r.h.Merge(o.h)
+}
+
+// Count returns the total number of observations recorded
+func (r *hdrRecorder) Count() int64 { return r.h.TotalCount() }
+
+// Min returns the exact minimum observation
+func (r *hdrRecorder) Min() time.Duration { return r.min }
+
+// Max returns the exact maximum observation
+func (r *hdrRecorder) Max() time.Duration { return r.max }
+
+// Mean returns the exact mean of all observations
+func (r *hdrRecorder) Mean() time.Duration {
+	if n := r.h.TotalCount(); n > 0 {
+		return r.sum / time.Duration(n)
+	}
+	return 0
+}
+
+// Percentile returns the q-quantile (0..1) from the histogram
+func (r *hdrRecorder) Percentile(q float64) time.Duration {
+	if r.h.TotalCount() == 0 {
+		return 0
+	}
+	return time.Duration(r.h.ValueAtQuantile(q * 100))
+}
+
+// Percentiles returns several quantiles from the histogram
+func (r *hdrRecorder) Percentiles(qs ...float64) []time.Duration {
+	out := make([]time.Duration, len(qs))
+	for i, q := range qs {
+		out[i] = r.Percentile(q)
+	}
+	return out
+}
+
+// Histogram returns a copy of the underlying histogram
+func (r *hdrRecorder) Histogram() *hdrhistogram.Histogram {
+	return hdrhistogram.Import(r.h.Export())
+}
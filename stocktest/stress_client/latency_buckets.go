@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LatencyBucket is one row of the -hist-buckets distribution table. A
+// bucket holds latencies from FromMs up to, but not including, ToMs; the
+// first bucket has no lower bound and the last no upper bound (ToMs 0).
+type LatencyBucket struct {
+	Label  string  `json:"label"`
+	FromMs float64 `json:"from_ms"`
+	ToMs   float64 `json:"to_ms,omitempty"`
+	Count  int64   `json:"count"`
+	Pct    float64 `json:"pct"`
+}
+
+// parseHistBuckets parses -hist-buckets, a comma-separated list of strictly
+// increasing positive bucket boundaries such as "1ms,5ms,10ms". An empty
+// spec disables the table.
+func parseHistBuckets(spec string) ([]time.Duration, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var bounds []time.Duration
+	for _, field := range strings.Split(spec, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hist-buckets %q: %w", spec, err)
+		}
+		if d <= 0 || (len(bounds) > 0 && d <= bounds[len(bounds)-1]) {
+			return nil, fmt.Errorf("invalid hist-buckets %q: boundaries must be positive and increasing", spec)
+		}
+		bounds = append(bounds, d)
+	}
+	return bounds, nil
+}
+
+// bucketLatencies splits r's observations at bounds into len(bounds)+1
+// buckets. With the reservoir recorder the retained samples are counted and
+// scaled up to r.Count(), so counts are exact until the reservoir fills and
+// estimates after; with -histogram hdr every order is counted, placed to the
+// histogram's precision of 3 significant digits.
+func bucketLatencies(r LatencyRecorder, bounds []time.Duration) []LatencyBucket {
+	buckets := make([]LatencyBucket, len(bounds)+1)
+	for i := range buckets {
+		b := &buckets[i]
+		switch {
+		case i == 0:
+			b.Label = "<" + bounds[0].String()
+			b.ToMs = toMs(bounds[0])
+		case i == len(bounds):
+			b.Label = ">=" + bounds[i-1].String()
+			b.FromMs = toMs(bounds[i-1])
+		default:
+			b.Label = bounds[i-1].String() + "-" + bounds[i].String()
+			b.FromMs, b.ToMs = toMs(bounds[i-1]), toMs(bounds[i])
+		}
+	}
+
+	// Count the retained samples, or the histogram's bars, per bucket
+	bucketOf := func(d time.Duration) int {
+		return sort.Search(len(bounds), func(i int) bool { return d < bounds[i] })
+	}
+	counts := make([]int64, len(buckets))
+	var total int64
+	if res, ok := r.(*latencyReservoir); ok {
+		for _, d := range res.samples {
+			counts[bucketOf(d)]++
+		}
+		total = int64(len(res.samples))
+	} else {
+		h := r.Histogram()
+		for _, bar := range h.Distribution() {
+			// Values in a bar are equal to the histogram's precision, so
+			// its highest value places them all
+			counts[bucketOf(time.Duration(bar.To))] += bar.Count
+		}
+		total = h.TotalCount()
+	}
+	if total == 0 {
+		return buckets
+	}
+
+	scale := float64(r.Count()) / float64(total)
+	for i := range buckets {
+		buckets[i].Count = int64(math.Round(float64(counts[i]) * scale))
+		buckets[i].Pct = float64(counts[i]) / float64(total) * 100
+	}
+	return buckets
+}
+
+// logLatencyBuckets prints the distribution table
+func logLatencyBuckets(buckets []LatencyBucket) {
+	log.Printf("Order Latency Distribution:")
+	for _, b := range buckets {
+		log.Printf("  %-14s %10d  %5.1f%%", b.Label, b.Count, b.Pct)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyBuckets(t *testing.T) {
+	bounds, err := parseHistBuckets("1ms, 5ms,10ms,50ms,100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10 samples per listed latency; 5ms sits on a boundary and belongs to
+	// the bucket it opens
+	samples := map[time.Duration]int64{
+		500 * time.Microsecond: 10,
+		3 * time.Millisecond:   10,
+		5 * time.Millisecond:   10,
+		7 * time.Millisecond:   10,
+		20 * time.Millisecond:  10,
+		200 * time.Millisecond: 10,
+	}
+	want := []LatencyBucket{
+		{Label: "<1ms", ToMs: 1, Count: 10},
+		{Label: "1ms-5ms", FromMs: 1, ToMs: 5, Count: 10},
+		{Label: "5ms-10ms", FromMs: 5, ToMs: 10, Count: 20},
+		{Label: "10ms-50ms", FromMs: 10, ToMs: 50, Count: 10},
+		{Label: "50ms-100ms", FromMs: 50, ToMs: 100, Count: 0},
+		{Label: ">=100ms", FromMs: 100, Count: 10},
+	}
+	recorders := map[string]LatencyRecorder{
+		HistogramReservoir: &latencyReservoir{},
+		HistogramHDR:       newHDRRecorder(),
+	}
+	for name, r := range recorders {
+		for d, n := range samples {
+			for range n {
+				r.Record(d)
+			}
+		}
+		got := bucketLatencies(r, bounds)
+		if len(got) != len(want) {
+			t.Fatalf("%s: %d buckets, want %d", name, len(got), len(want))
+		}
+		for i, w := range want {
+			w.Pct = float64(w.Count) / 60 * 100
+			if got[i] != w {
+				t.Errorf("%s: bucket %d = %+v, want %+v", name, i, got[i], w)
+			}
+		}
+	}
+
+	// A full reservoir scales its sample counts up to the recorded total
+	r := &latencyReservoir{capacity: 100}
+	for i := range 1000 {
+		r.Record(time.Duration(i%2+1) * 3 * time.Millisecond)
+	}
+	if got := bucketLatencies(r, bounds); got[1].Count+got[2].Count != 1000 {
+		t.Errorf("scaled counts %d + %d, want 1000 in total", got[1].Count, got[2].Count)
+	}
+
+	for _, spec := range []string{"5ms,1ms", "0,1ms", "1ms,1ms", "fast"} {
+		if _, err := parseHistBuckets(spec); err == nil {
+			t.Errorf("parseHistBuckets(%q) accepted", spec)
+		}
+	}
+}
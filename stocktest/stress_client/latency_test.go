@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		q         float64
+		want      time.Duration
+	}{
+		{"empty", nil, 0.99, 0},
+		{"single", []time.Duration{ms(7)}, 0.5, ms(7)},
+		{"single p99", []time.Duration{ms(7)}, 0.99, ms(7)},
+		{"min", []time.Duration{ms(3), ms(1), ms(2)}, 0, ms(1)},
+		{"max", []time.Duration{ms(3), ms(1), ms(2)}, 1, ms(3)},
+		{"median odd", []time.Duration{ms(3), ms(1), ms(2)}, 0.5, ms(2)},
+		{"interpolated", []time.Duration{ms(10), ms(20)}, 0.5, ms(15)},
+		{"p95 of 1..101", seqMillis(101), 0.95, ms(96)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.latencies, tt.q); got != tt.want {
+				t.Errorf("percentile(%v) = %v, want %v", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentileDoesNotMutateInput(t *testing.T) {
+	in := []time.Duration{5, 3, 9, 1}
+	percentile(in, 0.5)
+	want := []time.Duration{5, 3, 9, 1}
+	for i := range in {
+		if in[i] != want[i] {
+			t.Fatalf("input reordered: %v", in)
+		}
+	}
+}
+
+func seqMillis(n int) []time.Duration {
+	out := make([]time.Duration, n)
+	for i := range out {
+		out[i] = time.Duration(i+1) * time.Millisecond
+	}
+	return out
+}
+
+func TestLatencyReservoirBounded(t *testing.T) {
+	const capacity = 10000
+	const n = 1000000
+
+	r := latencyReservoir{capacity: capacity}
+	for i := 1; i <= n; i++ {
+		r.Record(time.Duration(i) * time.Microsecond)
+	}
+
+	if len(r.samples) != capacity || cap(r.samples) > 2*capacity {
+		t.Fatalf("reservoir holds %d samples (cap %d), want %d", len(r.samples), cap(r.samples), capacity)
+	}
+	if r.Count() != n {
+		t.Errorf("Count = %d, want %d", r.Count(), n)
+	}
+	if r.Min() != time.Microsecond || r.Max() != n*time.Microsecond {
+		t.Errorf("Min/Max = %v/%v, want exact 1µs/%v", r.Min(), r.Max(), n*time.Microsecond)
+	}
+
+	// Uniform input: the q-quantile should be close to q*n
+	for _, q := range []float64{0.50, 0.95, 0.99} {
+		want := q * n
+		got := float64(r.Percentile(q) / time.Microsecond)
+		if diff := got - want; diff > 0.02*n || diff < -0.02*n {
+			t.Errorf("p%v = %.0fµs, want %.0fµs ±2%%", q*100, got, want)
+		}
+	}
+}
+
+func TestLatencyRecordersAgree(t *testing.T) {
+	const n = 100000
+	recorders := map[string]LatencyRecorder{
+		HistogramReservoir: &latencyReservoir{capacity: n},
+		HistogramHDR:       newHDRRecorder(),
+	}
+
+	// Uniform 1µs..100ms, shuffled so order does not matter
+	rng := rand.New(rand.NewSource(1))
+	for _, i := range rng.Perm(n) {
+		d := time.Duration(i+1) * time.Microsecond
+		for _, r := range recorders {
+			r.Record(d)
+		}
+	}
+
+	wantMean := time.Duration(n+1) * time.Microsecond / 2
+	for name, r := range recorders {
+		if r.Count() != n {
+			t.Errorf("%s: Count = %d, want %d", name, r.Count(), n)
+		}
+		if r.Min() != time.Microsecond || r.Max() != n*time.Microsecond {
+			t.Errorf("%s: Min/Max = %v/%v, want 1µs/%v", name, r.Min(), r.Max(), n*time.Microsecond)
+		}
+		if r.Mean() != wantMean {
+			t.Errorf("%s: Mean = %v, want %v", name, r.Mean(), wantMean)
+		}
+		for _, q := range []float64{0.50, 0.95, 0.99, 0.999} {
+			want := q * n
+			got := float64(r.Percentile(q)) / float64(time.Microsecond)
+			if math.Abs(got-want) > 0.001*want+1 {
+				t.Errorf("%s: p%v = %.1fµs, want %.0fµs ±0.1%%", name, q*100, got, want)
+			}
+		}
+		if h := r.Histogram(); h.TotalCount() != n {
+			t.Errorf("%s: Histogram().TotalCount = %d, want %d", name, h.TotalCount(), n)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// parseLogLevel maps a -log-level name to a slog level. An empty name is
+// info.
+func parseLogLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", name)
+}
+
+// newLogHandler returns the key=value handler used for diagnostics
+func newLogHandler(w io.Writer, level slog.Level) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+}
+
+// setupLogging sends leveled diagnostics (per-user and per-order events,
+// the live status) through slog at level. The standard logger stays
+// unleveled on w: it prints the run configuration and final results, which
+// are the tool's output rather than diagnostics and must not be filtered.
+func setupLogging(w io.Writer, level slog.Level) {
+	slog.SetDefault(slog.New(newLogHandler(w, level)))
+	// SetDefault routes the standard logger through the handler; undo that
+	log.SetOutput(w)
+	log.SetFlags(log.LstdFlags)
+}
+
+// round1 rounds x to one decimal place for log fields
+func round1(x float64) float64 {
+	return math.Round(x*10) / 10
+}
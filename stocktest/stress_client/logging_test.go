@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+func TestLogLevelFiltering(t *testing.T) {
+	prev := slog.Default()
+	defer func() {
+		slog.SetDefault(prev)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	// Rejects every order, which is logged per order at debug level
+	submitRejected := func() {
+		client, server := net.Pipe()
+		defer client.Close()
+		go func() {
+			defer server.Close()
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, _ := protocol.DecodeSubmitOrder(body)
+			server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Message: "Insufficient buying power"}))
+		}()
+		if _, err := submitOrderTCP(context.Background(), client, "user_9", "TSLA", 0, 1, 1, 100, submitOptions{}); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		level     string
+		wantDebug bool
+		wantInfo  bool
+	}{
+		{"debug", true, true},
+		{"info", false, true},
+		{"warn", false, false},
+	} {
+		level, err := parseLogLevel(tc.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf strings.Builder
+		setupLogging(&buf, level)
+
+		submitRejected()
+		slog.Info("live status", "orders_submitted", 1)
+		log.Printf("=== FINAL RESULTS ===")
+
+		out := buf.String()
+		gotDebug := strings.Contains(out, `level=DEBUG msg="order rejected" user_id=user_9 symbol=TSLA latency=`)
+		if gotDebug != tc.wantDebug {
+			t.Errorf("%s: order rejected debug line logged = %v, want %v\n%s", tc.level, gotDebug, tc.wantDebug, out)
+		}
+		if got := strings.Contains(out, `level=INFO msg="live status" orders_submitted=1`); got != tc.wantInfo {
+			t.Errorf("%s: live status logged = %v, want %v\n%s", tc.level, got, tc.wantInfo, out)
+		}
+		// Results on the standard logger are never filtered
+		if !strings.Contains(out, "=== FINAL RESULTS ===") {
+			t.Errorf("%s: final results missing\n%s", tc.level, out)
+		}
+	}
+
+	if _, err := parseLogLevel("verbose"); err == nil {
+		t.Error("parseLogLevel accepted an unknown level")
+	}
+}
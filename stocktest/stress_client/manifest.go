@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Manifest describes how a run was started: together with the -output-json
+// results it records everything needed to reproduce or audit the run
+type Manifest struct {
+	StartTime       time.Time    `json:"start_time"`
+	ClientVersion   string       `json:"client_version"`
+	GoVersion       string       `json:"go_version"`
+	Hostname        string       `json:"hostname"`
+	Args            []string     `json:"args"`
+	Seed            int64        `json:"seed"`
+	ProtocolVersion int          `json:"protocol_version"`
+	Frontend        string       `json:"frontend"`
+	Engines         []string     `json:"engines"`
+	Config          StressConfig `json:"config"`
+}
+
+// newManifest captures the resolved config and the environment at start
+func newManifest(config StressConfig, start time.Time) Manifest {
+	hostname, _ := os.Hostname()
+	// validate has already checked the engine list
+	engines, _ := parseEngineAddrs(config.EngineAddr)
+	return Manifest{
+		StartTime:       start,
+		ClientVersion:   clientVersion(),
+		GoVersion:       runtime.Version(),
+		Hostname:        hostname,
+		Args:            os.Args[1:],
+		Seed:            runSeed,
+		ProtocolVersion: protocolVersion,
+		Frontend:        config.FrontendURL,
+		Engines:         engines,
+		Config:          config,
+	}
+}
+
+// clientVersion identifies the build from the VCS stamp the go command
+// embeds: the commit, suffixed "-dirty" when the tree had local changes.
+// Builds without a stamp, such as go test or go run, report the module
+// version, usually "(devel)".
+func clientVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision == "" {
+		return info.Main.Version
+	}
+	if modified == "true" {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// writeManifest writes m to path as indented JSON
+func writeManifest(path string, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
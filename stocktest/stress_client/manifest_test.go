@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	defer func(seed int64) { runSeed = seed }(runSeed)
+	runSeed = 42
+
+	config := StressConfig{
+		FrontendURL:   "http://frontend:3000",
+		EngineAddr:    "engine-a:8080,engine-b:8080",
+		NumUsers:      25,
+		OrdersPerUser: 400,
+		TestDuration:  90 * time.Second,
+		Symbols:       []string{"AAPL", "MSFT"},
+		OrderMix:      "market=50,limit=50",
+		PriceRef:      150,
+		Manifest:      "manifest.json",
+	}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := writeManifest(path, newManifest(config, start)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Manifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got.Config, config) {
+		t.Errorf("config = %+v, want %+v", got.Config, config)
+	}
+	if got.Seed != 42 || !got.StartTime.Equal(start) || got.Frontend != config.FrontendURL {
+		t.Errorf("seed %d, start %v, frontend %q; want 42, %v, %q", got.Seed, got.StartTime, got.Frontend, start, config.FrontendURL)
+	}
+	if want := []string{"engine-a:8080", "engine-b:8080"}; !slices.Equal(got.Engines, want) {
+		t.Errorf("engines = %v, want %v", got.Engines, want)
+	}
+	if got.ClientVersion == "" || got.GoVersion == "" || got.Hostname == "" {
+		t.Errorf("environment missing: version %q, go %q, host %q", got.ClientVersion, got.GoVersion, got.Hostname)
+	}
+}
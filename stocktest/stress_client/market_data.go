@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// marketDataTimeout bounds the wait for a top-of-book reply, or -io-timeout
+// if shorter. The engine's TCP server does not route market data queries yet
+// and drops them.
+const marketDataTimeout = 5 * time.Second
+
+// queryTopOfBook asks the engine for symbol's best bid and ask
+func queryTopOfBook(conn net.Conn, symbol string) (bid, ask float64, bidQty, askQty int64, err error) {
+	if _, err := conn.Write(protocol.EncodeMarketDataRequest(symbol)); err != nil {
+		recordError(classifyError(err, ErrCategoryWrite))
+		return 0, 0, 0, 0, fmt.Errorf("TCP write market data request failed: %w", err)
+	}
+
+	timeout := marketDataTimeout
+	if ioTimeout > 0 && ioTimeout < timeout {
+		timeout = ioTimeout
+	}
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	for {
+		body, err := protocol.ReadFrame(conn)
+		if err != nil {
+			recordError(classifyError(err, ErrCategoryRead))
+			return 0, 0, 0, 0, fmt.Errorf("TCP read market data failed: %w", err)
+		}
+		if len(body) > 0 && body[0] == protocol.MessageTypeHeartbeatAck {
+			continue
+		}
+		tob, err := protocol.ParseMarketDataResponse(body)
+		if err != nil {
+			recordError(ErrCategoryMalformedResponse)
+			return 0, 0, 0, 0, fmt.Errorf("%w: %v", errMalformedResponse, err)
+		}
+		return tob.Bid, tob.Ask, tob.BidQty, tob.AskQty, nil
+	}
+}
+
+// runBookVerifier queries the top of book for a random symbol every interval
+// on a pooled connection to the engine that owns it and logs the spread, to
+// confirm submitted orders are resting in the book. The pools should be the
+// verifier's own, so a query waiting on its reply never holds up orders. An
+// engine that leaves a query unanswered is not queried again; later ticks
+// for its symbols are counted as skipped.
+func runBookVerifier(ctx context.Context, engines *enginePools, symbols []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	unanswered := make(map[*ConnPool]bool)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		symbol := symbols[rand.Intn(len(symbols))]
+		pool := engines.For(symbol)
+		if unanswered[pool] {
+			atomic.AddInt64(&stats.BookQueriesSkipped, 1)
+			continue
+		}
+		conn, err := pool.Get(ctx)
+		if err != nil {
+			continue
+		}
+		bid, ask, bidQty, askQty, err := queryTopOfBook(conn, symbol)
+		releaseConn(pool, conn, err)
+
+		atomic.AddInt64(&stats.BookQueries, 1)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			unanswered[pool] = true
+			slog.Warn("book query unanswered, no longer querying this engine", "symbol", symbol, "err", err)
+			continue
+		}
+		if err != nil {
+			slog.Warn("book query failed", "symbol", symbol, "err", err)
+			continue
+		}
+		switch {
+		case bidQty == 0 && askQty == 0:
+			atomic.AddInt64(&stats.BookEmpty, 1)
+			slog.Info("book empty", "symbol", symbol)
+		case bidQty == 0 || askQty == 0:
+			slog.Info("book one-sided", "symbol", symbol, "bid", bid, "bid_qty", bidQty, "ask", ask, "ask_qty", askQty)
+		default:
+			slog.Info("book", "symbol", symbol, "bid", bid, "bid_qty", bidQty, "ask", ask, "ask_qty", askQty, "spread", ask-bid)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestBookVerifierStopsAfterUnansweredQuery(t *testing.T) {
+	resetGlobals(t)
+
+	// Cut the 5s reply wait short
+	ioTimeout = 50 * time.Millisecond
+	defer func() { ioTimeout = 0 }()
+
+	// The engine drops book queries without replying, like its TCP server
+	// today
+	raw, server := net.Pipe()
+	var queries atomic.Int64
+	go func() {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			if body[0] == protocol.MessageTypeMarketDataRequest {
+				queries.Add(1)
+			}
+		}
+	}()
+	var dials atomic.Int64
+	engines := newEnginePools([]string{"engine:0"}, ShardSymbol, 1, 1, func(string) (net.Conn, error) {
+		dials.Add(1)
+		return newPushConn(raw, recordPush), nil
+	})
+	defer engines.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	runBookVerifier(ctx, engines, []string{"AAPL"}, 10*time.Millisecond)
+
+	if got := queries.Load(); got != 1 {
+		t.Errorf("engine received %d book queries, want only the first", got)
+	}
+	if got := dials.Load(); got != 1 {
+		t.Errorf("%d connections dialed, want 1", got)
+	}
+	if got := atomic.LoadInt64(&stats.BookQueriesSkipped); got == 0 {
+		t.Error("no book queries skipped after the first went unanswered")
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus metrics, served on -metrics-addr when set
+var (
+	ordersSubmittedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orders_submitted_total",
+		Help: "Orders that received a response from the engine.",
+	})
+	ordersAcceptedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "orders_accepted_total",
+		Help: "Orders accepted by the engine.",
+	})
+	errorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "errors_total",
+		Help: "Failed signups, logins, connections and order submissions, by category.",
+	}, []string{"category"})
+	usersCreatedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "users_created_total",
+		Help: "Users created through the frontend signup endpoint.",
+	})
+	usersLoggedInTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "users_logged_in_total",
+		Help: "Users logged in through the frontend login endpoint.",
+	})
+	orderLatencySeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "order_latency_seconds",
+		Help:    "Order submit-to-response latency.",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 2, 16), // 100µs .. ~3.3s
+	})
+)
+
+// startMetricsServer serves /metrics on addr in the background
+func startMetricsServer(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		slog.Info("serving Prometheus metrics", "url", "http://"+addr+"/metrics")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server failed", "err", err)
+		}
+	}()
+}
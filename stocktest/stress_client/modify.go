@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// modifyAckTimeout bounds the wait for a modify ack. Like cancels, modifies
+// are dropped without a reply by engines that do not route them.
+const modifyAckTimeout = 5 * time.Second
+
+// modifyOrderTCP amends the quantity and price of a previously accepted order
+// and reports whether the engine accepted the change. As with cancels, once
+// a modify times out on a connection, later modifies on it return
+// errUnanswered without being sent.
+func modifyOrderTCP(conn net.Conn, orderID string, newQty int64, newPrice float64) (bool, error) {
+	pc := unwrapPushConn(conn)
+	if pc != nil && pc.modifiesUnanswered.Load() {
+		atomic.AddInt64(&stats.ModifiesSkipped, 1)
+		return false, fmt.Errorf("modify %s: %w", orderID, errUnanswered)
+	}
+
+	m := protocol.ModifyOrder{OrderID: orderID, Quantity: newQty, Price: newPrice}
+	route, routed := routeOrderResponse(conn, orderID)
+	if routed {
+		defer route.Close()
+	}
+	if _, err := conn.Write(protocol.EncodeModifyOrder(m)); err != nil {
+		recordError(classifyError(err, ErrCategoryWrite))
+		return false, fmt.Errorf("TCP write modify failed: %w", err)
+	}
+
+	var resp protocol.OrderResponse
+	var err error
+	if routed {
+		resp, err = route.Await(context.Background(), modifyAckTimeout)
+	} else {
+		conn.SetReadDeadline(time.Now().Add(modifyAckTimeout))
+		resp, err = readOrderResponse(conn, orderID)
+		conn.SetReadDeadline(time.Time{})
+	}
+	if err != nil {
+		if pc != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+			pc.modifiesUnanswered.Store(true)
+		}
+		recordError(classifyError(err, ErrCategoryRead))
+		return false, fmt.Errorf("TCP read modify ack failed: %w", err)
+	}
+
+	atomic.AddInt64(&stats.ModifiesSubmitted, 1)
+	if resp.Accepted {
+		atomic.AddInt64(&stats.ModifiesAccepted, 1)
+	}
+	return resp.Accepted, nil
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestModifyOrderTCP(t *testing.T) {
+	resetGlobals(t)
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	// Accept the first modify, reject the second
+	go func() {
+		defer server.Close()
+		for i := 0; i < 2; i++ {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			m, err := protocol.DecodeModifyOrder(body)
+			if err != nil {
+				return
+			}
+			resp := protocol.OrderResponse{OrderID: m.OrderID, Accepted: i == 0, Message: "modify " + strconv.FormatInt(m.Quantity, 10)}
+			server.Write(protocol.EncodeOrderResponse(resp))
+		}
+	}()
+
+	for i, want := range []bool{true, false} {
+		accepted, err := modifyOrderTCP(client, "order_1", 50, 101.5)
+		if err != nil {
+			t.Fatalf("modify %d: %v", i, err)
+		}
+		if accepted != want {
+			t.Errorf("modify %d accepted = %v, want %v", i, accepted, want)
+		}
+	}
+	if got := atomic.LoadInt64(&stats.ModifiesSubmitted); got != 2 {
+		t.Errorf("ModifiesSubmitted = %d, want 2", got)
+	}
+	if got := atomic.LoadInt64(&stats.ModifiesAccepted); got != 1 {
+		t.Errorf("ModifiesAccepted = %d, want 1", got)
+	}
+}
+
+func TestModifiesSkippedAfterUnansweredModify(t *testing.T) {
+	resetGlobals(t)
+
+	// Cut the 5s ack wait short
+	ioTimeout = 50 * time.Millisecond
+	defer func() { ioTimeout = 0 }()
+
+	// The engine drops modifies without replying but still acks cancels,
+	// so a skipped modify leaves cancels on the connection alone
+	raw, server := net.Pipe()
+	var modifies atomic.Int64
+	go func() {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			switch body[0] {
+			case protocol.MessageTypeModifyOrder:
+				modifies.Add(1)
+			case protocol.MessageTypeCancelOrder:
+				orderID, _ := protocol.DecodeCancelOrder(body)
+				server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: orderID, Accepted: true}))
+			}
+		}
+	}()
+	dials := 0
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		dials++
+		return newPushConn(raw, recordPush), nil
+	})
+	defer pool.Close()
+
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		err := withRetry(ctx, pool, 0, func(conn net.Conn) error {
+			_, err := modifyOrderTCP(conn, "order_1", int64(i), 100)
+			return err
+		})
+		if i == 1 && (err == nil || errors.Is(err, errUnanswered)) {
+			t.Errorf("first modify: err = %v, want an ack timeout", err)
+		}
+		if i > 1 && !errors.Is(err, errUnanswered) {
+			t.Errorf("modify %d: err = %v, want errUnanswered", i, err)
+		}
+	}
+	err := withRetry(ctx, pool, 0, func(conn net.Conn) error {
+		_, err := submitCancelTCP(conn, "order_1")
+		return err
+	})
+	if err != nil {
+		t.Errorf("cancel after skipped modifies: %v", err)
+	}
+
+	if got := modifies.Load(); got != 1 {
+		t.Errorf("engine received %d modifies, want only the first", got)
+	}
+	if got := atomic.LoadInt64(&stats.ModifiesSkipped); got != 2 {
+		t.Errorf("%d modifies skipped, want 2", got)
+	}
+	if dials != 1 {
+		t.Errorf("%d connections dialed, want the first kept", dials)
+	}
+}
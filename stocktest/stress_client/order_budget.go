@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import "sync/atomic"
+
+// totalBudget caps the order slots of the whole run (-total-orders).
+// Nil means unlimited.
+var totalBudget *orderBudget
+
+// orderBudget is a run-wide count of order slots shared by every user
+type orderBudget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+// newOrderBudget returns a budget of limit slots, or nil for unlimited
+func newOrderBudget(limit int64) *orderBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &orderBudget{limit: limit}
+}
+
+// Take claims one order slot and reports false once the budget is spent.
+// Slots are claimed with compare-and-swap, so Used never overshoots Limit.
+func (b *orderBudget) Take() bool {
+	if b == nil {
+		return true
+	}
+	for {
+		n := b.used.Load()
+		if n >= b.limit {
+			return false
+		}
+		if b.used.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// Used returns the number of slots claimed so far
+func (b *orderBudget) Used() int64 { return b.used.Load() }
+
+// Limit returns the size of the budget
+func (b *orderBudget) Limit() int64 { return b.limit }
+
+// ordersForUser is the number of order slots user userID (1-based) runs.
+// With -total-orders the budget is split evenly across -users and the first
+// users take one extra slot each for the remainder, so the shares add up to
+// the total; otherwise it is -orders.
+func ordersForUser(config StressConfig, userID int) int {
+	if config.TotalOrders <= 0 {
+		return config.OrdersPerUser
+	}
+	users := int64(config.NumUsers)
+	share := config.TotalOrders / users
+	if int64(userID) <= config.TotalOrders%users {
+		share++
+	}
+	return int(share)
+}
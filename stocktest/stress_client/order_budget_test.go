@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTotalOrdersLandsOnBudget(t *testing.T) {
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         7,
+		OrdersPerUser:    5,
+		TotalOrders:      1000,
+		Concurrency:      7,
+		OrderConcurrency: 4,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+		CPUThreshold:     100,
+		DrainTimeout:     time.Second,
+	}
+	var shares int
+	for userID := 1; userID <= config.NumUsers; userID++ {
+		shares += ordersForUser(config, userID)
+	}
+	if shares != 1000 {
+		t.Errorf("per-user shares add up to %d, want 1000", shares)
+	}
+
+	// Soak users run until the shared budget is spent, well before -duration
+	for _, soak := range []bool{false, true} {
+		resetGlobals(t)
+		totalBudget = newOrderBudget(config.TotalOrders)
+
+		config.Soak = soak
+		config.TestDuration = time.Minute
+		report := runStressTest(context.Background(), config, func() bool { return false })
+		if report.OrdersSubmitted != 1000 || totalBudget.Used() != 1000 {
+			t.Errorf("soak=%v: %d orders submitted, %d budget used, want exactly 1000", soak, report.OrdersSubmitted, totalBudget.Used())
+		}
+	}
+	totalBudget = nil
+}
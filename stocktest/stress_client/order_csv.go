@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var orderCSVHeader = []string{
+	"timestamp", "user_id", "symbol", "side", "type", "quantity", "price",
+	"accepted", "latency_us", "error",
+}
+
+// orderRecord is one order's outcome, as written to the -csv file
+type orderRecord struct {
+	Time     time.Time
+	UserID   string
+	Symbol   string
+	Side     int
+	Type     int
+	Quantity int64
+	Price    float64
+	Accepted bool
+	Latency  time.Duration
+	Err      error
+}
+
+// orderLog receives every order outcome when -csv is set
+var orderLog *orderCSVWriter
+
+// orderCSVWriter appends order records from a single goroutine fed by a
+// channel, so submitters never contend on the file.
+type orderCSVWriter struct {
+	file    *os.File
+	w       *csv.Writer
+	records chan orderRecord
+	stopped chan struct{}
+
+	mu     sync.RWMutex // guards closed against sends racing Close
+	closed bool
+	err    error
+}
+
+// startOrderCSVWriter creates path, writes the header and starts the writer
+func startOrderCSVWriter(path string) (*orderCSVWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order CSV file: %w", err)
+	}
+
+	o := &orderCSVWriter{
+		file:    f,
+		w:       csv.NewWriter(f),
+		records: make(chan orderRecord, 4096),
+		stopped: make(chan struct{}),
+	}
+	if err := o.w.Write(orderCSVHeader); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write order CSV header: %w", err)
+	}
+
+	go o.run()
+	return o, nil
+}
+
+func (o *orderCSVWriter) run() {
+	defer close(o.stopped)
+	for rec := range o.records {
+		errText := ""
+		if rec.Err != nil {
+			errText = rec.Err.Error()
+		}
+		if err := o.w.Write([]string{
+			rec.Time.UTC().Format(time.RFC3339Nano),
+			rec.UserID,
+			rec.Symbol,
+			strconv.Itoa(rec.Side),
+			strconv.Itoa(rec.Type),
+			strconv.FormatInt(rec.Quantity, 10),
+			strconv.FormatFloat(rec.Price, 'f', 4, 64),
+			strconv.FormatBool(rec.Accepted),
+			strconv.FormatInt(rec.Latency.Microseconds(), 10),
+			errText,
+		}); err != nil && o.err == nil {
+			o.err = err
+		}
+	}
+}
+
+// Record queues rec for writing. Records arriving after Close are dropped.
+func (o *orderCSVWriter) Record(rec orderRecord) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if o.closed {
+		return
+	}
+	o.records <- rec
+}
+
+// Close drains queued records, flushes and closes the file
+func (o *orderCSVWriter) Close() error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	close(o.records)
+	o.mu.Unlock()
+
+	<-o.stopped
+	o.w.Flush()
+	err := o.err
+	if err == nil {
+		err = o.w.Error()
+	}
+	if cerr := o.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestOrderCSVRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	w, err := startOrderCSVWriter(path)
+	if err != nil {
+		t.Fatalf("startOrderCSVWriter: %v", err)
+	}
+	orderLog = w
+	defer func() { orderLog = nil }()
+
+	client, server := net.Pipe()
+	go serveFakeOrders(server, func(int) time.Duration { return 0 })
+	for i := 0; i < 3; i++ {
+		if _, err := submitOrderTCP(context.Background(), client, "user_7", "MSFT", 1, 1, int64(10+i), 250.5, submitOptions{}); err != nil {
+			t.Fatalf("submit %d: %v", i, err)
+		}
+	}
+	client.Close()
+	if _, err := submitOrderTCP(context.Background(), client, "user_7", "MSFT", 0, 0, 5, 0, submitOptions{}); err == nil {
+		t.Fatal("submit on closed connection succeeded")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want header + 4 orders", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], orderCSVHeader) {
+		t.Errorf("header = %v, want %v", rows[0], orderCSVHeader)
+	}
+	for i, row := range rows[1:4] {
+		want := []string{"user_7", "MSFT", "1", "1", strconv.Itoa(10 + i), "250.5000", "true"}
+		if !reflect.DeepEqual(row[1:8], want) {
+			t.Errorf("row %d = %v, want fields %v", i+1, row, want)
+		}
+		if row[9] != "" {
+			t.Errorf("row %d has error %q", i+1, row[9])
+		}
+	}
+	if failed := rows[4]; failed[7] != "false" || failed[9] == "" {
+		t.Errorf("failed order row = %v, want accepted=false and an error", failed)
+	}
+}
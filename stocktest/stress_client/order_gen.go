@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"log"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// runSeed is the base seed for every worker's random source. -seed sets it;
+// otherwise it is derived from the clock at startup and logged so a run can
+// be replayed.
+var runSeed int64
+
+// resolveSeed returns seed, or a clock-derived seed when seed is zero
+func resolveSeed(seed int64) int64 {
+	if seed != 0 {
+		return seed
+	}
+	return time.Now().UnixNano()
+}
+
+// seedRun sets runSeed from -seed and logs it. main calls it before anything
+// talks to the frontend, since runNamespace names every account by the seed.
+func seedRun(seed int64) {
+	runSeed = resolveSeed(seed)
+	log.Printf("Random seed: %d (rerun with -seed %d to reproduce the order stream)", runSeed, runSeed)
+}
+
+// workerRand returns the deterministic random source for one worker
+func workerRand(workerID int) *rand.Rand {
+	return rand.New(rand.NewSource(runSeed + int64(workerID)))
+}
+
+// runNamespace returns a short token naming the run, derived from its seed.
+// Distinct seeds give distinct tokens, so accounts a run creates never
+// collide with another run's, while a rerun with the same -seed reuses them.
+func runNamespace() string {
+	return strconv.FormatUint(uint64(runSeed), 36)
+}
+
+// orderParams is one generated order, plus the workload decisions made for it
+type orderParams struct {
+	Symbol   string
+	Side     int
+	Type     int
+	Quantity int64
+	Price    float64
+	Fragment bool
+	Cancel   bool
+	Modify   bool
+	// Query the portfolio instead of sending an order (-query-pct)
+	Query bool
+	// Pause before sending: the archetype's pace plus any -think-time
+	Pause time.Duration
+	// Offset subtracted from the order timestamp (-timestamp-skew)
+	Skew        time.Duration
+	SkewTracked bool
+}
+
+// orderGenerator produces a user's order stream from a single random source,
+// so the same seed always yields the same sequence. It is not safe for
+// concurrent use; draw orders in the dispatch loop, not in submit goroutines.
+type orderGenerator struct {
+	rng       *rand.Rand
+	config    StressConfig
+	mix       orderMix
+	prices    PriceModel
+	qtys      QuantityModel
+	symbols   SymbolPicker
+	think     ThinkTime
+	skews     []time.Duration
+	archetype string
+	// Aggressive traders concentrate on one symbol
+	favorite string
+	// Passive traders alternate sides
+	passiveSide int
+}
+
+// newOrderGenerator builds a generator for config drawing from rng
+func newOrderGenerator(config StressConfig, rng *rand.Rand) (*orderGenerator, error) {
+	mix, err := parseOrderMix(config.OrderMix)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := newPriceModel(config)
+	if err != nil {
+		return nil, err
+	}
+	qtys, err := newQuantityModel(config)
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := newSymbolPicker(config)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := parseProfile(config.Profile)
+	if err != nil {
+		return nil, err
+	}
+	think, err := parseThinkTime(config.ThinkTime)
+	if err != nil {
+		return nil, err
+	}
+	skews, err := parseTimestampSkews(config.TimestampSkew)
+	if err != nil {
+		return nil, err
+	}
+	g := &orderGenerator{
+		rng:       rng,
+		config:    config,
+		mix:       mix,
+		prices:    prices,
+		qtys:      qtys,
+		symbols:   symbols,
+		think:     think,
+		skews:     skews,
+		archetype: profile.Pick(rng),
+	}
+	if g.archetype == ArchetypeAggressive {
+		g.favorite = symbols.Pick(rng)
+	}
+	return g, nil
+}
+
+// Archetype returns the trader archetype assigned to this generator's user
+func (g *orderGenerator) Archetype() string { return g.archetype }
+
+// Next draws the next order according to the user's archetype
+func (g *orderGenerator) Next() orderParams {
+	var p orderParams
+	switch g.archetype {
+	case ArchetypeAggressive:
+		p = g.nextAggressive()
+	case ArchetypePassive:
+		p = g.nextPassive()
+	default:
+		p = g.nextNoise()
+	}
+	// Drawn last and only when set, so existing seeded streams are unchanged
+	if g.think != nil {
+		p.Pause += g.think.Sample(g.rng)
+	}
+	if g.config.QueryPct > 0 {
+		p.Query = g.rng.Intn(100) < g.config.QueryPct
+	}
+	if len(g.skews) > 0 {
+		p.Skew = g.skews[g.rng.Intn(len(g.skews))]
+		p.SkewTracked = true
+	}
+	if g.config.CrossPct > 0 && g.rng.Intn(100) < g.config.CrossPct {
+		g.crossSpread(&p)
+	}
+	return p
+}
+
+// nextNoise draws every field independently at random
+func (g *orderGenerator) nextNoise() orderParams {
+	symbol := g.symbols.Pick(g.rng)
+	return orderParams{
+		Symbol:   symbol,
+		Side:     g.rng.Intn(2), // Buy or Sell
+		Type:     g.mix.Pick(g.rng),
+		Quantity: g.qtys.Quantity(g.rng),
+		Price:    g.prices.Price(g.rng, symbol),
+		Fragment: g.config.FragmentPct > 0 && g.rng.Intn(100) < g.config.FragmentPct,
+		Cancel:   g.config.CancelPct > 0 && g.rng.Intn(100) < g.config.CancelPct,
+		Modify:   g.drawModify(),
+	}
+}
+
+// drawModify decides whether an order slot amends an earlier order. No value
+// is drawn when -modify-pct is off, so existing seeded streams are unchanged.
+func (g *orderGenerator) drawModify() bool {
+	return g.config.ModifyPct > 0 && g.rng.Intn(100) < g.config.ModifyPct
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSeededOrderStreamReproducible(t *testing.T) {
+	config := StressConfig{
+		Symbols:     defaultSymbols,
+		OrderMix:    "market=20,limit=60,ioc=15,fok=5",
+		PriceModel:  PriceModelWalk,
+		PriceRef:    150,
+		PriceSpread: 50,
+		FragmentPct: 10,
+		CancelPct:   10,
+	}
+	stream := func(seed int64) []orderParams {
+		gen, err := newOrderGenerator(config, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("newOrderGenerator: %v", err)
+		}
+		orders := make([]orderParams, 1000)
+		for i := range orders {
+			orders[i] = gen.Next()
+		}
+		return orders
+	}
+
+	a, b := stream(42), stream(42)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed produced different order streams")
+	}
+	if reflect.DeepEqual(a, stream(43)) {
+		t.Error("different seeds produced identical order streams")
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"stress_client/protocol"
+)
+
+// orderIDPrefix starts every generated order ID (-order-prefix), so one
+// run's orders can be grepped out of client and engine logs
+var orderIDPrefix string
+
+// orderIDLen and userIDLen pad generated IDs to at least this many bytes
+// (-order-id-len, -user-id-len) to exercise the engine's string handling
+var orderIDLen, userIDLen int
+
+// maxPaddedIDLen bounds -order-id-len and -user-id-len so an order carrying
+// both IDs, and the response echoing the order ID, fit in one frame
+const maxPaddedIDLen = protocol.MaxFrameLen / 4
+
+// idPadding fills padded IDs. It is not a hex digit, so padding is easy to
+// tell apart from the UUID in engine logs.
+const idPadding = 'x'
+
+// newOrderID returns a unique client order ID: orderIDPrefix, any padding
+// up to orderIDLen, then a random (version 4) UUID. Its only allocation is
+// the returned string.
+func newOrderID() string {
+	var u [16]byte
+	rand.Read(u[:])         // never fails
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+
+	var s [36]byte
+	hex.Encode(s[0:8], u[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], u[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], u[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], u[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], u[10:])
+
+	pad := max(orderIDLen-len(orderIDPrefix)-len(s), 0)
+	var b strings.Builder
+	b.Grow(len(orderIDPrefix) + pad + len(s))
+	b.WriteString(orderIDPrefix)
+	for range pad {
+		b.WriteByte(idPadding)
+	}
+	b.Write(s[:])
+	return b.String()
+}
+
+// userIDString returns the protocol user ID for userID, "user_<n>", padded
+// before the number up to userIDLen
+func userIDString(userID int) string {
+	n := strconv.Itoa(userID)
+	pad := max(userIDLen-len("user_")-len(n), 0)
+	return "user_" + strings.Repeat(string(idPadding), pad) + n
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+func TestOrderIDsUnique(t *testing.T) {
+	saved := orderIDPrefix
+	orderIDPrefix = "ci42-"
+	defer func() { orderIDPrefix = saved }()
+
+	const workers, perWorker = 16, 5000
+	ids := make(chan string, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				ids <- newOrderID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool, workers*perWorker)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate order ID %s", id)
+		}
+		seen[id] = true
+		uuid, ok := strings.CutPrefix(id, "ci42-")
+		if !ok || len(uuid) != 36 || uuid[14] != '4' || !strings.ContainsRune("89ab", rune(uuid[19])) {
+			t.Fatalf("order ID %q is not the prefix followed by a v4 UUID", id)
+		}
+	}
+}
+
+func TestLongIDsRoundTrip(t *testing.T) {
+	resetGlobals(t)
+
+	defer func() { orderIDLen, userIDLen = 0, 0 }()
+	orderIDLen, userIDLen = 200000, 5000
+
+	orderID := newOrderID()
+	if len(orderID) != orderIDLen || !strings.HasPrefix(orderID, "xxx") {
+		t.Fatalf("padded order ID is %d bytes, want %d", len(orderID), orderIDLen)
+	}
+	userID := userIDString(42)
+	if len(userID) != userIDLen || !strings.HasPrefix(userID, "user_x") || !strings.HasSuffix(userID, "x42") {
+		t.Fatalf("padded user ID %.12q... is %d bytes, want %d", userID, len(userID), userIDLen)
+	}
+
+	// One engine echoes the order ID intact, the other truncates it
+	for _, truncate := range []bool{false, true} {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, _ := protocol.DecodeSubmitOrder(body)
+			if o.UserID != userID {
+				t.Errorf("engine received a %d-byte user ID, want %d", len(o.UserID), len(userID))
+			}
+			if truncate {
+				o.OrderID = o.OrderID[:4096]
+			}
+			server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true}))
+		}()
+		resp, err := submitOrderTCP(context.Background(), client, userID, "AAPL", protocol.OrderSideBuy,
+			protocol.OrderTypeLimit, 1, 100, submitOptions{OrderID: orderID})
+		client.Close()
+		switch {
+		case truncate && !errors.Is(err, errCorrelation):
+			t.Errorf("truncated echo: err = %v, want a correlation error", err)
+		case !truncate && (err != nil || resp.OrderID != orderID):
+			t.Errorf("long order ID did not round-trip: %d-byte echo, err %v", len(resp.OrderID), err)
+		}
+	}
+}
+
+// BenchmarkNewOrderID reports the hot-path cost of an order ID; outside the
+// race detector it is one allocation, the returned string
+func BenchmarkNewOrderID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		newOrderID()
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"stress_client/protocol"
+)
+
+// defaultOrderMix keeps the original even market/limit split
+const defaultOrderMix = "market=50,limit=50"
+
+// orderTypeNames maps -order-mix keys to wire order types
+var orderTypeNames = map[string]int{
+	"market": protocol.OrderTypeMarket,
+	"limit":  protocol.OrderTypeLimit,
+	"ioc":    protocol.OrderTypeIOC,
+	"fok":    protocol.OrderTypeFOK,
+}
+
+// orderMix is a weighted distribution over order types
+type orderMix struct {
+	types   []int
+	weights []int
+	total   int
+}
+
+// parseOrderMix parses a mix such as "market=20,limit=60,ioc=15,fok=5".
+// Weights are relative and need not sum to 100; an empty spec yields the
+// default 50/50 market/limit mix.
+func parseOrderMix(spec string) (orderMix, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = defaultOrderMix
+	}
+
+	var m orderMix
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return orderMix{}, fmt.Errorf("order mix entry %q is not type=weight", part)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		orderType, known := orderTypeNames[name]
+		if !known {
+			return orderMix{}, fmt.Errorf("unknown order type %q (want market, limit, ioc or fok)", name)
+		}
+		if seen[name] {
+			return orderMix{}, fmt.Errorf("order type %q listed twice", name)
+		}
+		seen[name] = true
+
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return orderMix{}, fmt.Errorf("invalid weight %q for %s", value, name)
+		}
+		if weight == 0 {
+			continue
+		}
+		m.types = append(m.types, orderType)
+		m.weights = append(m.weights, weight)
+		m.total += weight
+	}
+
+	if m.total == 0 {
+		return orderMix{}, fmt.Errorf("order mix %q has no positive weights", spec)
+	}
+	return m, nil
+}
+
+// Pick draws an order type according to the mix weights
+func (m orderMix) Pick(r *rand.Rand) int {
+	n := r.Intn(m.total)
+	for i, w := range m.weights {
+		if n < w {
+			return m.types[i]
+		}
+		n -= w
+	}
+	return m.types[len(m.types)-1]
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+func TestOrderMixDistribution(t *testing.T) {
+	mix, err := parseOrderMix("market=20,limit=60,ioc=15,fok=5")
+	if err != nil {
+		t.Fatalf("parseOrderMix: %v", err)
+	}
+
+	const draws = 200000
+	r := rand.New(rand.NewSource(1))
+	counts := make(map[int]int)
+	for i := 0; i < draws; i++ {
+		counts[mix.Pick(r)]++
+	}
+
+	want := map[int]float64{
+		protocol.OrderTypeMarket: 0.20,
+		protocol.OrderTypeLimit:  0.60,
+		protocol.OrderTypeIOC:    0.15,
+		protocol.OrderTypeFOK:    0.05,
+	}
+	for orderType, p := range want {
+		got := float64(counts[orderType]) / draws
+		if diff := got - p; diff > 0.01 || diff < -0.01 {
+			t.Errorf("order type %d drawn %.3f of the time, want %.2f", orderType, got, p)
+		}
+	}
+
+	for _, bad := range []string{"market=0,limit=0", "limit=-5", "stop=10", "market=10,market=20", "market"} {
+		if _, err := parseOrderMix(bad); err == nil {
+			t.Errorf("parseOrderMix(%q) succeeded, want error", bad)
+		}
+	}
+}
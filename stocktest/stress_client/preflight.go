@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// preflightHealthPath is the frontend route the pre-flight check requests
+const preflightHealthPath = "/api/health"
+
+// preflightTimeout bounds the whole pre-flight check
+const preflightTimeout = 15 * time.Second
+
+// runPreflight checks, before any user starts, that the frontend answers
+// and that every engine accepts and authenticates a connection, so a run
+// against an unreachable deployment stops at once instead of spending its
+// duration producing errors. The engine check logs in the shared user, or
+// takes user 1's token from -tokens-file.
+func runPreflight(ctx context.Context, config StressConfig) error {
+	addrs, err := parseEngineAddrs(config.EngineAddr)
+	if err != nil {
+		return err
+	}
+	if config.TokensFile == "" {
+		if err := checkFrontendHealth(ctx, config.FrontendURL); err != nil {
+			return err
+		}
+	}
+
+	tokens, err := loginSharedUser(config)
+	if err != nil {
+		return fmt.Errorf("frontend %s: could not log in a user: %w", config.FrontendURL, err)
+	}
+	for _, addr := range addrs {
+		conn, err := dialEngine(ctx, addr, tokens.TradingToken)
+		if err != nil {
+			return fmt.Errorf("engine %s: %w", addr, err)
+		}
+		conn.Close()
+	}
+	return nil
+}
+
+// checkFrontendHealth requests the frontend's health route. Any answer
+// below 500 passes, since a frontend without the route still proves it is
+// up; a 5xx or no answer fails.
+func checkFrontendHealth(ctx context.Context, frontendURL string) error {
+	url := strings.TrimSuffix(frontendURL, "/") + preflightHealthPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("frontend %s: %w", frontendURL, err)
+	}
+	resp, err := frontendClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("frontend %s unreachable: %w", frontendURL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, frontendErrorBodyLimit))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("frontend %s unhealthy: GET %s returned %s", frontendURL, preflightHealthPath, resp.Status)
+	}
+	return nil
+}
+
+// preflightOrExit runs the pre-flight check and exits with exitNoConnection
+// if it fails
+func preflightOrExit(config StressConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+	start := time.Now()
+	if err := runPreflight(ctx, config); err != nil {
+		log.Printf("PRE-FLIGHT FAIL: %v", err)
+		log.Printf("Nothing was started; fix the deployment or rerun with -skip-preflight")
+		os.Exit(exitNoConnection)
+	}
+	log.Printf("Pre-flight check passed in %v", time.Since(start).Round(time.Millisecond))
+}
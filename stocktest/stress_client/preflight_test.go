@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPreflightEngineDown(t *testing.T) {
+	var healthChecks atomic.Int64
+	frontend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		switch r.URL.Path {
+		case preflightHealthPath:
+			healthChecks.Add(1)
+		case "/api/auth/stress-signup":
+			w.WriteHeader(http.StatusCreated)
+		case "/api/auth/login":
+			io.WriteString(w, `{"tokens":{"tradingToken":"token"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer frontend.Close()
+
+	// An address nothing listens on
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := ln.Addr().String()
+	ln.Close()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	up := startFakeTLSEngine(t, true)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := runPreflight(ctx, StressConfig{FrontendURL: frontend.URL, EngineAddr: up}); err != nil {
+		t.Errorf("pre-flight against a working deployment: %v", err)
+	}
+
+	err = runPreflight(ctx, StressConfig{FrontendURL: frontend.URL, EngineAddr: up + "," + down})
+	if err == nil || !strings.Contains(err.Error(), "engine "+down) {
+		t.Errorf("pre-flight with engine %s down: err = %v, want it named", down, err)
+	}
+	if n := healthChecks.Load(); n != 2 {
+		t.Errorf("frontend health checked %d times, want 2", n)
+	}
+
+	frontend.Close()
+	if err := runPreflight(ctx, StressConfig{FrontendURL: frontend.URL, EngineAddr: up}); err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("pre-flight with the frontend down: err = %v", err)
+	}
+}
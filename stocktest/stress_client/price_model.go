@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Price models selectable with -price-model
+const (
+	PriceModelUniform = "uniform"
+	PriceModelNormal  = "normal"
+	PriceModelWalk    = "walk"
+)
+
+// minPrice keeps generated limit prices positive
+const minPrice = 0.01
+
+// PriceModel generates limit prices for orders on a symbol
+type PriceModel interface {
+	Price(r *rand.Rand, symbol string) float64
+}
+
+// newPriceModel builds the model named by config.PriceModel around
+// config.PriceRef. Each worker gets its own model so a seeded run is
+// reproducible regardless of goroutine scheduling. PriceSpread is the uniform half-width, three standard
+// deviations for the normal model, and the bound on how far a random walk may
+// drift from the reference.
+func newPriceModel(config StressConfig) (PriceModel, error) {
+	switch config.PriceModel {
+	case "", PriceModelUniform:
+		return UniformPrice{Min: config.PriceRef - config.PriceSpread, Max: config.PriceRef + config.PriceSpread}, nil
+	case PriceModelNormal:
+		return NormalPrice{Mean: config.PriceRef, StdDev: config.PriceSpread / 3}, nil
+	case PriceModelWalk:
+		return NewRandomWalkPrice(config.PriceRef, config.PriceSpread/100, config.PriceSpread), nil
+	default:
+		return nil, fmt.Errorf("unknown price model %q (want uniform, normal or walk)", config.PriceModel)
+	}
+}
+
+// UniformPrice draws prices uniformly from [Min, Max)
+type UniformPrice struct {
+	Min, Max float64
+}
+
+// Price returns a uniformly distributed price
+func (u UniformPrice) Price(r *rand.Rand, _ string) float64 {
+	return math.Max(minPrice, u.Min+r.Float64()*(u.Max-u.Min))
+}
+
+// NormalPrice draws prices from a gaussian around Mean
+type NormalPrice struct {
+	Mean, StdDev float64
+}
+
+// Price returns a normally distributed price
+func (n NormalPrice) Price(r *rand.Rand, _ string) float64 {
+	return math.Max(minPrice, n.Mean+r.NormFloat64()*n.StdDev)
+}
+
+// RandomWalkPrice keeps a per-symbol mid price that moves by a gaussian step
+// on every order, so consecutive orders cluster and build book depth near the
+// mid. The walk is reflected to stay within MaxDrift of the start price.
+type RandomWalkPrice struct {
+	Start    float64
+	Step     float64
+	MaxDrift float64
+
+	mu   sync.Mutex
+	mids map[string]float64
+}
+
+// NewRandomWalkPrice creates a walk starting every symbol at start
+func NewRandomWalkPrice(start, step, maxDrift float64) *RandomWalkPrice {
+	return &RandomWalkPrice{Start: start, Step: step, MaxDrift: maxDrift, mids: make(map[string]float64)}
+}
+
+// Price advances the symbol's walk by one step and returns the new mid
+func (w *RandomWalkPrice) Price(r *rand.Rand, symbol string) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	mid, ok := w.mids[symbol]
+	if !ok {
+		mid = w.Start
+	}
+	mid += r.NormFloat64() * w.Step
+
+	lo, hi := math.Max(minPrice, w.Start-w.MaxDrift), w.Start+w.MaxDrift
+	if mid < lo {
+		mid = math.Min(hi, 2*lo-mid)
+	}
+	if mid > hi {
+		mid = math.Max(lo, 2*hi-mid)
+	}
+
+	w.mids[symbol] = mid
+	return mid
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPriceModelRanges(t *testing.T) {
+	uniform := UniformPrice{Min: 100, Max: 200}
+	normal := NormalPrice{Mean: 150, StdDev: 5}
+	var normalSum float64
+	const draws = 10000
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < draws; i++ {
+		if p := uniform.Price(r, "AAPL"); p < 100 || p >= 200 {
+			t.Fatalf("uniform price %.2f outside [100, 200)", p)
+		}
+		p := normal.Price(r, "AAPL")
+		if p < 150-8*5 || p > 150+8*5 {
+			t.Fatalf("normal price %.2f implausibly far from the mean", p)
+		}
+		normalSum += p
+	}
+	if mean := normalSum / draws; mean < 149.5 || mean > 150.5 {
+		t.Errorf("normal mean %.2f, want ~150", mean)
+	}
+}
+
+func TestRandomWalkContinuity(t *testing.T) {
+	walk := NewRandomWalkPrice(150, 0.5, 20)
+	r := rand.New(rand.NewSource(1))
+
+	prev := map[string]float64{"AAPL": 150, "MSFT": 150}
+	for i := 0; i < 10000; i++ {
+		for symbol, last := range prev {
+			p := walk.Price(r, symbol)
+			if p < 130 || p > 170 {
+				t.Fatalf("%s walked to %.2f, outside start +/- max drift", symbol, p)
+			}
+			// A step is one gaussian draw; 10 sigma never happens in practice
+			if d := math.Abs(p - last); d > 10*0.5 {
+				t.Fatalf("%s jumped %.2f in one step", symbol, d)
+			}
+			prev[symbol] = p
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// probeResult is the outcome of one -probe
+type probeResult struct {
+	Addr string
+	// Dial, TLS handshake and engine login
+	Connect time.Duration
+	// One heartbeat round trip on the authenticated connection
+	RTT time.Duration
+}
+
+// String formats the result as the single line -probe prints
+func (r probeResult) String() string {
+	return fmt.Sprintf("engine %s connect %v rtt %v", r.Addr,
+		r.Connect.Round(time.Microsecond), r.RTT.Round(time.Microsecond))
+}
+
+// runProbe is a liveness check for -probe that sends no orders: it logs in
+// the shared user (or takes the first -tokens-file token), connects and
+// authenticates to the first engine, and times one heartbeat round trip.
+// ctx bounds the whole check.
+func runProbe(ctx context.Context, config StressConfig) (probeResult, error) {
+	addrs, err := parseEngineAddrs(config.EngineAddr)
+	if err != nil {
+		return probeResult{}, err
+	}
+	result := probeResult{Addr: addrs[0]}
+
+	tokens, err := loginSharedUser(config)
+	if err != nil {
+		return result, err
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	start := time.Now()
+	conn, err := dialEngine(ctx, result.Addr, tokens.TradingToken)
+	if err != nil {
+		return result, fmt.Errorf("engine %s: %w", result.Addr, err)
+	}
+	defer conn.Close()
+	result.Connect = time.Since(start)
+
+	timeout := heartbeatAckTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline))
+	}
+	// Unblock the heartbeat if ctx is cancelled first
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	start = time.Now()
+	if err := sendHeartbeat(conn, timeout); err != nil {
+		return result, fmt.Errorf("heartbeat: %w", err)
+	}
+	result.RTT = time.Since(start)
+	return result, nil
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestProbe(t *testing.T) {
+	// As with -tokens-file, so the probe skips the frontend
+	tokenList = &preissuedTokens{tokens: []AuthTokens{{TradingToken: "token"}}, reuse: true}
+	defer func() { tokenList = nil }()
+
+	// An address nothing listens on
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := ln.Addr().String()
+	ln.Close()
+
+	for _, tc := range []struct {
+		addr string
+		ok   bool
+	}{
+		{startFakeTLSEngine(t, false), true},
+		{down, false},
+	} {
+		config := StressConfig{EngineAddr: tc.addr, Symbols: defaultSymbols}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		result, err := runProbe(ctx, config)
+		cancel()
+		if tc.ok && (err != nil || result.RTT <= 0) {
+			t.Errorf("probe of a live engine = %v, %v", result, err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("probe of %s passed with nothing listening", tc.addr)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// Trader archetypes selectable with -profile
+const (
+	// Aggressive traders take liquidity: market orders and limit orders
+	// priced through the mid, concentrated on a favourite symbol
+	ArchetypeAggressive = "aggressive"
+	// Passive traders make liquidity: limit orders resting away from the
+	// mid on alternating sides, paced and frequently cancelled
+	ArchetypePassive = "passive"
+	// Noise traders draw every field at random (the default behavior)
+	ArchetypeNoise = "noise"
+)
+
+// defaultProfile makes every user a noise trader
+const defaultProfile = "noise=100"
+
+// Archetype tuning
+const (
+	aggressiveLimitPct    = 50   // share of aggressive orders sent as limit rather than market
+	aggressiveCross       = 0.01 // aggressive limits cross the mid by this fraction
+	aggressiveFavoritePct = 80   // share of aggressive orders on the favourite symbol
+	passiveMinOffset      = 0.005
+	passiveMaxOffset      = 0.02
+	passiveCancelPct      = 30
+	passiveThinkTime      = 20 * time.Millisecond
+)
+
+// traderProfile is a weighted distribution over archetypes
+type traderProfile struct {
+	names   []string
+	weights []int
+	total   int
+}
+
+// parseProfile parses a profile such as "aggressive=20,passive=30,noise=50".
+// Weights are relative; an empty spec makes every user a noise trader.
+func parseProfile(spec string) (traderProfile, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = defaultProfile
+	}
+
+	var p traderProfile
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return traderProfile{}, fmt.Errorf("profile entry %q is not archetype=weight", part)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case ArchetypeAggressive, ArchetypePassive, ArchetypeNoise:
+		default:
+			return traderProfile{}, fmt.Errorf("unknown archetype %q (want aggressive, passive or noise)", name)
+		}
+		if seen[name] {
+			return traderProfile{}, fmt.Errorf("archetype %q listed twice", name)
+		}
+		seen[name] = true
+
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return traderProfile{}, fmt.Errorf("invalid weight %q for %s", value, name)
+		}
+		if weight == 0 {
+			continue
+		}
+		p.names = append(p.names, name)
+		p.weights = append(p.weights, weight)
+		p.total += weight
+	}
+
+	if p.total == 0 {
+		return traderProfile{}, fmt.Errorf("profile %q has no positive weights", spec)
+	}
+	return p, nil
+}
+
+// Pick draws an archetype according to the profile weights. A profile with
+// a single archetype draws nothing, so the default profile leaves a seeded
+// order stream unchanged.
+func (p traderProfile) Pick(r *rand.Rand) string {
+	if len(p.names) == 1 {
+		return p.names[0]
+	}
+	n := r.Intn(p.total)
+	for i, w := range p.weights {
+		if n < w {
+			return p.names[i]
+		}
+		n -= w
+	}
+	return p.names[len(p.names)-1]
+}
+
+// nextAggressive draws a liquidity-taking order
+func (g *orderGenerator) nextAggressive() orderParams {
+	symbol := g.favorite
+	if g.rng.Intn(100) >= aggressiveFavoritePct {
+		symbol = g.symbols.Pick(g.rng)
+	}
+	side := g.rng.Intn(2)
+	mid := g.prices.Price(g.rng, symbol)
+
+	orderType := protocol.OrderTypeMarket
+	price := mid
+	if g.rng.Intn(100) < aggressiveLimitPct {
+		orderType = protocol.OrderTypeLimit
+		if side == protocol.OrderSideBuy {
+			price = mid * (1 + aggressiveCross)
+		} else {
+			price = mid * (1 - aggressiveCross)
+		}
+	}
+	return orderParams{
+		Symbol:   symbol,
+		Side:     side,
+		Type:     orderType,
+		Quantity: g.qtys.Quantity(g.rng),
+		Price:    price,
+		Fragment: g.config.FragmentPct > 0 && g.rng.Intn(100) < g.config.FragmentPct,
+		Cancel:   g.config.CancelPct > 0 && g.rng.Intn(100) < g.config.CancelPct,
+		Modify:   g.drawModify(),
+	}
+}
+
+// nextPassive draws a resting limit order on the opposite side to the last
+func (g *orderGenerator) nextPassive() orderParams {
+	symbol := g.symbols.Pick(g.rng)
+	side := g.passiveSide
+	g.passiveSide = 1 - side
+	mid := g.prices.Price(g.rng, symbol)
+
+	offset := passiveMinOffset + g.rng.Float64()*(passiveMaxOffset-passiveMinOffset)
+	price := mid * (1 + offset)
+	if side == protocol.OrderSideBuy {
+		price = mid * (1 - offset)
+	}
+	return orderParams{
+		Symbol:   symbol,
+		Side:     side,
+		Type:     protocol.OrderTypeLimit,
+		Quantity: g.qtys.Quantity(g.rng),
+		Price:    price,
+		Fragment: g.config.FragmentPct > 0 && g.rng.Intn(100) < g.config.FragmentPct,
+		Cancel:   g.rng.Intn(100) < max(g.config.CancelPct, passiveCancelPct),
+		Modify:   g.drawModify(),
+		Pause:    passiveThinkTime,
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+func TestTraderArchetypes(t *testing.T) {
+	const mid = 100.0
+	const n = 2000
+	generate := func(profile string) (*orderGenerator, []orderParams) {
+		config := StressConfig{
+			Symbols:     defaultSymbols,
+			OrderMix:    "market=20,limit=60,ioc=15,fok=5",
+			PriceModel:  PriceModelUniform,
+			PriceRef:    mid,
+			PriceSpread: 0, // every draw is exactly the mid
+			Profile:     profile,
+		}
+		gen, err := newOrderGenerator(config, rand.New(rand.NewSource(7)))
+		if err != nil {
+			t.Fatalf("newOrderGenerator(%q): %v", profile, err)
+		}
+		orders := make([]orderParams, n)
+		for i := range orders {
+			orders[i] = gen.Next()
+		}
+		return gen, orders
+	}
+
+	t.Run("aggressive", func(t *testing.T) {
+		gen, orders := generate("aggressive=1")
+		if gen.Archetype() != ArchetypeAggressive {
+			t.Fatalf("archetype = %q", gen.Archetype())
+		}
+		favorite := 0
+		for _, o := range orders {
+			switch {
+			case o.Type == protocol.OrderTypeMarket:
+			case o.Type != protocol.OrderTypeLimit:
+				t.Fatalf("aggressive order type %d, want market or limit", o.Type)
+			case o.Side == protocol.OrderSideBuy && o.Price <= mid,
+				o.Side == protocol.OrderSideSell && o.Price >= mid:
+				t.Fatalf("aggressive limit side %d at %.2f does not cross mid %.2f", o.Side, o.Price, mid)
+			}
+			if o.Pause != 0 || o.Cancel {
+				t.Fatalf("aggressive order paused or cancelled: %+v", o)
+			}
+			if o.Symbol == gen.favorite {
+				favorite++
+			}
+		}
+		if favorite < n*aggressiveFavoritePct/100-n/20 {
+			t.Errorf("%d of %d orders on the favourite symbol, want about %d%%", favorite, n, aggressiveFavoritePct)
+		}
+	})
+
+	t.Run("passive", func(t *testing.T) {
+		gen, orders := generate("passive=1")
+		if gen.Archetype() != ArchetypePassive {
+			t.Fatalf("archetype = %q", gen.Archetype())
+		}
+		cancels := 0
+		for i, o := range orders {
+			if o.Type != protocol.OrderTypeLimit {
+				t.Fatalf("passive order type %d, want limit", o.Type)
+			}
+			if o.Side != i%2 {
+				t.Fatalf("order %d side %d, want sides to alternate", i, o.Side)
+			}
+			away := (mid - o.Price) / mid
+			if o.Side == protocol.OrderSideSell {
+				away = -away
+			}
+			if away < passiveMinOffset || away > passiveMaxOffset {
+				t.Fatalf("passive side %d at %.2f is %.4f from mid, want %v..%v", o.Side, o.Price, away, passiveMinOffset, passiveMaxOffset)
+			}
+			if o.Pause != passiveThinkTime {
+				t.Fatalf("passive pause = %v, want %v", o.Pause, passiveThinkTime)
+			}
+			if o.Cancel {
+				cancels++
+			}
+		}
+		if pct := cancels * 100 / n; pct < passiveCancelPct-5 || pct > passiveCancelPct+5 {
+			t.Errorf("passive cancel rate %d%%, want about %d%%", pct, passiveCancelPct)
+		}
+	})
+
+	t.Run("noise", func(t *testing.T) {
+		gen, orders := generate("")
+		if gen.Archetype() != ArchetypeNoise {
+			t.Fatalf("archetype = %q", gen.Archetype())
+		}
+		types := make(map[int]int)
+		sides := make(map[int]int)
+		for _, o := range orders {
+			types[o.Type]++
+			sides[o.Side]++
+			if o.Price != mid || o.Pause != 0 {
+				t.Fatalf("noise order %+v, want price at mid and no pause", o)
+			}
+		}
+		if len(types) != 4 || len(sides) != 2 {
+			t.Errorf("noise orders used types %v and sides %v, want the full mix on both sides", types, sides)
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		config := StressConfig{Symbols: defaultSymbols, OrderMix: defaultOrderMix, PriceRef: mid, Profile: "aggressive=1,passive=1,noise=1"}
+		seen := make(map[string]int)
+		for seed := int64(0); seed < 300; seed++ {
+			gen, err := newOrderGenerator(config, rand.New(rand.NewSource(seed)))
+			if err != nil {
+				t.Fatalf("newOrderGenerator: %v", err)
+			}
+			seen[gen.Archetype()]++
+		}
+		for _, a := range []string{ArchetypeAggressive, ArchetypePassive, ArchetypeNoise} {
+			if seen[a] < 70 {
+				t.Errorf("%s assigned to %d of 300 users, want about 100", a, seen[a])
+			}
+		}
+	})
+
+	if _, err := parseProfile("aggressive=1,lurker=2"); err == nil {
+		t.Error("parseProfile accepted an unknown archetype")
+	}
+}
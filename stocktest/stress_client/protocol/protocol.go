@@ -0,0 +1,668 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+// Package protocol implements the engine's binary TCP wire format
+// (see src/api/TCPServer.h). Every frame is a big-endian uint32 total length,
+// which counts itself, followed by a one-byte message type and the body.
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Message types (matching TCPServer.h)
+const (
+	MessageTypeLoginRequest  = 1
+	MessageTypeLoginResponse = 2
+	MessageTypeSubmitOrder   = 3
+	MessageTypeOrderResponse = 4
+	MessageTypeHeartbeat     = 5
+	MessageTypeHeartbeatAck  = 6
+	// Cancels a resting order; acked with an order response. Not yet
+	// routed by the engine's TCP server.
+	MessageTypeCancelOrder = 7
+	// Top-of-book query; not yet routed by the engine's TCP server
+	MessageTypeMarketDataRequest  = 8
+	MessageTypeMarketDataResponse = 9
+	// Amends quantity and price of a resting order; acked with an order
+	// response. Not yet routed by the engine's TCP server.
+	MessageTypeModifyOrder = 10
+	// Full book depth query; not yet routed by the engine's TCP server
+	MessageTypeDepthRequest  = 11
+	MessageTypeDepthResponse = 12
+	// Fill notice the engine pushes unsolicited, between responses; not yet
+	// sent by the engine's TCP server
+	MessageTypeExecutionReport = 13
+)
+
+// IsPush reports whether frames of msgType are pushed by the engine on its
+// own rather than sent in answer to a request
+func IsPush(msgType byte) bool {
+	return msgType == MessageTypeExecutionReport
+}
+
+// ProtocolVersion is the newest frame layout this package speaks. The
+// client sends the version it wants in its login request and the engine
+// answers with the version it will speak; an engine that predates
+// negotiation sends none, which LoginResponse reports as Version 0.
+const ProtocolVersion = 3
+
+// Order sides and types
+const (
+	OrderSideBuy    = 0
+	OrderSideSell   = 1
+	OrderTypeMarket = 0
+	OrderTypeLimit  = 1
+	OrderTypeIOC    = 2
+	OrderTypeFOK    = 3
+)
+
+// Fixed header sizes, excluding variable-length strings
+const (
+	lengthPrefixSize       = 4
+	loginResponseHeaderLen = 1 + 1 + 4                         // type + success + message_len
+	orderResponseHeaderLen = 1 + 4 + 1 + 4                     // type + order_id_len + accepted + message_len
+	submitOrderHeaderLen   = 1 + 4 + 4 + 4 + 1 + 1 + 8 + 8 + 8 // type + 3 lens + side + type + qty + price + ts
+	marketDataHeaderLen    = 1 + 4 + 8 + 8 + 8 + 8             // type + symbol_len + bid + bid_qty + ask + ask_qty
+	modifyOrderHeaderLen   = 1 + 4 + 8 + 8                     // type + order_id_len + qty + price
+	depthHeaderLen         = 1 + 4 + 4 + 4                     // type + symbol_len + bid_levels + ask_levels
+	depthLevelLen          = 8 + 8                             // price + qty
+	executionReportLen     = 1 + 4 + 4 + 8 + 8                 // type + order_id_len + symbol_len + qty + price
+)
+
+// ModifyOrder amends a previously accepted order
+type ModifyOrder struct {
+	OrderID  string
+	Quantity int64
+	Price    float64
+}
+
+// Order is a submit-order request
+type Order struct {
+	OrderID     string
+	UserID      string
+	Symbol      string
+	Side        uint8
+	Type        uint8
+	Quantity    int64
+	Price       float64
+	TimestampMs int64
+}
+
+// ErrInvalidOrder marks an order EncodeSubmitOrder refuses to encode because
+// the engine could only reject it
+var ErrInvalidOrder = errors.New("invalid order")
+
+// Validate checks that the quantity is positive and that the price is
+// positive for every priced order type. Market orders may carry price 0.
+func (o Order) Validate() error {
+	if o.Quantity <= 0 {
+		return fmt.Errorf("%w: quantity %d must be positive", ErrInvalidOrder, o.Quantity)
+	}
+	if o.Type == OrderTypeMarket {
+		if !(o.Price >= 0) || math.IsInf(o.Price, 0) {
+			return fmt.Errorf("%w: market order price %v must be 0 or positive", ErrInvalidOrder, o.Price)
+		}
+		return nil
+	}
+	if !(o.Price > 0) || math.IsInf(o.Price, 0) {
+		return fmt.Errorf("%w: price %v must be positive for order type %d", ErrInvalidOrder, o.Price, o.Type)
+	}
+	return nil
+}
+
+// LoginResponse is the engine's reply to a login request
+type LoginResponse struct {
+	Success bool
+	Message string
+	// Version is the protocol version the engine will speak on this
+	// connection, or 0 if the response carried none
+	Version uint8
+}
+
+// OrderResponse is the engine's reply to a submitted order
+type OrderResponse struct {
+	OrderID  string
+	Accepted bool
+	Message  string
+	// Extra holds any bytes after the message (optional fields in newer
+	// protocol versions)
+	Extra []byte
+}
+
+// RejectCode returns the uint16 reject code carried after the message in
+// protocol version 2 responses, if present.
+func (r OrderResponse) RejectCode() (uint16, bool) {
+	if len(r.Extra) < 2 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(r.Extra[:2]), true
+}
+
+// ServerTime returns the engine's clock when it answered, carried after the
+// reject code in protocol version 3 responses as uint64 Unix microseconds,
+// if present.
+func (r OrderResponse) ServerTime() (time.Time, bool) {
+	if len(r.Extra) < 10 {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(int64(binary.BigEndian.Uint64(r.Extra[2:10]))), true
+}
+
+// TopOfBook is the best bid and ask for a symbol
+type TopOfBook struct {
+	Symbol string
+	Bid    float64
+	BidQty int64
+	Ask    float64
+	AskQty int64
+}
+
+// ExecutionReport is a fill the engine pushes for one of the user's orders
+type ExecutionReport struct {
+	OrderID  string
+	Symbol   string
+	Quantity int64
+	Price    float64
+}
+
+// BookLevel is the total resting quantity at one price
+type BookLevel struct {
+	Price    float64
+	Quantity int64
+}
+
+// BookDepth is every price level of a symbol's book, best price first on
+// each side
+type BookDepth struct {
+	Symbol string
+	Bids   []BookLevel
+	Asks   []BookLevel
+}
+
+// MaxFrameLen bounds the length prefix ReadFrame accepts, so a corrupt or
+// hostile prefix cannot make it allocate gigabytes. Engine frames are tiny.
+const MaxFrameLen = 1 << 20
+
+// frame prefixes body with the total message length
+func frame(body []byte) []byte {
+	out := make([]byte, lengthPrefixSize+len(body))
+	binary.BigEndian.PutUint32(out, uint32(len(out)))
+	copy(out[lengthPrefixSize:], body)
+	return out
+}
+
+// ReadFrame reads one length-prefixed frame and returns its body (starting
+// with the message type byte).
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var messageLength uint32
+	if err := binary.Read(r, binary.BigEndian, &messageLength); err != nil {
+		return nil, fmt.Errorf("read frame length: %w", err)
+	}
+	if messageLength < lengthPrefixSize {
+		return nil, fmt.Errorf("invalid frame length: %d", messageLength)
+	}
+	if messageLength > MaxFrameLen {
+		return nil, fmt.Errorf("frame length %d exceeds limit of %d bytes", messageLength, MaxFrameLen)
+	}
+
+	body := make([]byte, messageLength-lengthPrefixSize)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("read frame body: %w", err)
+	}
+	return body, nil
+}
+
+// readString returns the n-byte string starting at body[offset] and the
+// offset just past it. A length that runs past the end of body is an error
+// rather than a slice panic.
+func readString(body []byte, offset int, n uint32, field string) (string, int, error) {
+	remaining := len(body) - offset
+	if remaining < 0 || uint64(n) > uint64(remaining) {
+		return "", offset, fmt.Errorf("%s length %d exceeds the %d bytes remaining", field, n, max(remaining, 0))
+	}
+	end := offset + int(n)
+	return string(body[offset:end]), end, nil
+}
+
+// EncodeLoginRequest builds a login frame: type(1) + token_len(4) + token +
+// version(1). The version follows the token so that engines which predate
+// negotiation, and read only the token, still accept the frame.
+func EncodeLoginRequest(token string, version uint8) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeLoginRequest)
+	binary.Write(buf, binary.BigEndian, uint32(len(token)))
+	buf.WriteString(token)
+	buf.WriteByte(version)
+	return frame(buf.Bytes())
+}
+
+// EncodeSubmitOrder builds a submit-order frame: type(1) + order_id_len(4) +
+// user_id_len(4) + symbol_len(4) + side(1) + order_type(1) + quantity(8) +
+// price(8) + timestamp_ms(8) + order_id + user_id + symbol. Orders that fail
+// Validate are not encoded.
+func EncodeSubmitOrder(o Order) ([]byte, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeSubmitOrder)
+	binary.Write(buf, binary.BigEndian, uint32(len(o.OrderID)))
+	binary.Write(buf, binary.BigEndian, uint32(len(o.UserID)))
+	binary.Write(buf, binary.BigEndian, uint32(len(o.Symbol)))
+	buf.WriteByte(o.Side)
+	buf.WriteByte(o.Type)
+	binary.Write(buf, binary.BigEndian, uint64(o.Quantity))
+
+	// Price is an IEEE-754 double in network byte order
+	binary.Write(buf, binary.BigEndian, math.Float64bits(o.Price))
+
+	binary.Write(buf, binary.BigEndian, uint64(o.TimestampMs))
+	buf.WriteString(o.OrderID)
+	buf.WriteString(o.UserID)
+	buf.WriteString(o.Symbol)
+	return frame(buf.Bytes()), nil
+}
+
+// EncodeCancelOrder builds a cancel-order frame: type(1) + order_id_len(4) +
+// order_id. The engine answers in the order response layout.
+func EncodeCancelOrder(orderID string) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeCancelOrder)
+	binary.Write(buf, binary.BigEndian, uint32(len(orderID)))
+	buf.WriteString(orderID)
+	return frame(buf.Bytes())
+}
+
+// EncodeModifyOrder builds a modify-order frame: type(1) + order_id_len(4) +
+// new_quantity(8) + new_price(8) + order_id. The engine answers in the order
+// response layout.
+func EncodeModifyOrder(m ModifyOrder) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeModifyOrder)
+	binary.Write(buf, binary.BigEndian, uint32(len(m.OrderID)))
+	binary.Write(buf, binary.BigEndian, uint64(m.Quantity))
+	binary.Write(buf, binary.BigEndian, math.Float64bits(m.Price))
+	buf.WriteString(m.OrderID)
+	return frame(buf.Bytes())
+}
+
+// EncodeMarketDataRequest builds a top-of-book query frame: type(1) +
+// symbol_len(4) + symbol
+func EncodeMarketDataRequest(symbol string) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeMarketDataRequest)
+	binary.Write(buf, binary.BigEndian, uint32(len(symbol)))
+	buf.WriteString(symbol)
+	return frame(buf.Bytes())
+}
+
+// EncodeDepthRequest builds a book depth query frame: type(1) +
+// symbol_len(4) + symbol
+func EncodeDepthRequest(symbol string) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeDepthRequest)
+	binary.Write(buf, binary.BigEndian, uint32(len(symbol)))
+	buf.WriteString(symbol)
+	return frame(buf.Bytes())
+}
+
+// DecodeLoginResponse reads a login response frame from r
+func DecodeLoginResponse(r io.Reader) (LoginResponse, error) {
+	body, err := ReadFrame(r)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	// type(1) + success(1) + message_len(4) + message [+ version(1)]
+	if len(body) < loginResponseHeaderLen {
+		return LoginResponse{}, fmt.Errorf("login response too short: %d bytes", len(body))
+	}
+	if body[0] != MessageTypeLoginResponse {
+		return LoginResponse{}, fmt.Errorf("unexpected response type: %d", body[0])
+	}
+
+	resp := LoginResponse{Success: body[1] == 1}
+	var end int
+	resp.Message, end, err = readString(body, loginResponseHeaderLen, binary.BigEndian.Uint32(body[2:6]), "login response message")
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	if end < len(body) {
+		resp.Version = body[end]
+	}
+	return resp, nil
+}
+
+// DecodeOrderResponse reads an order response frame from r
+func DecodeOrderResponse(r io.Reader) (OrderResponse, error) {
+	body, err := ReadFrame(r)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	return ParseOrderResponse(body)
+}
+
+// ParseOrderResponse parses an order response frame body (as returned by
+// ReadFrame)
+func ParseOrderResponse(body []byte) (OrderResponse, error) {
+	// type(1) + order_id_len(4) + accepted(1) + message_len(4) + order_id + message
+	if len(body) < orderResponseHeaderLen {
+		return OrderResponse{}, fmt.Errorf("order response too short: %d bytes", len(body))
+	}
+	if body[0] != MessageTypeOrderResponse {
+		return OrderResponse{}, fmt.Errorf("unexpected response type: %d", body[0])
+	}
+
+	resp := OrderResponse{Accepted: body[5] == 1}
+	var err error
+	offset := orderResponseHeaderLen
+	resp.OrderID, offset, err = readString(body, offset, binary.BigEndian.Uint32(body[1:5]), "order response order_id")
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	resp.Message, offset, err = readString(body, offset, binary.BigEndian.Uint32(body[6:10]), "order response message")
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	if rest := body[offset:]; len(rest) > 0 {
+		resp.Extra = rest
+	}
+	return resp, nil
+}
+
+// EncodeHeartbeat builds a heartbeat frame: type(1)
+func EncodeHeartbeat() []byte {
+	return frame([]byte{MessageTypeHeartbeat})
+}
+
+// DecodeHeartbeatAck reads a frame from r and checks it is a heartbeat ack.
+// The engine sends acks in the order response layout; only the type matters.
+func DecodeHeartbeatAck(r io.Reader) error {
+	body, err := ReadFrame(r)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return fmt.Errorf("empty heartbeat response")
+	}
+	if body[0] != MessageTypeHeartbeatAck {
+		return fmt.Errorf("unexpected response type: %d", body[0])
+	}
+	return nil
+}
+
+// EncodeHeartbeatAck builds a heartbeat ack frame the way the engine does:
+// the order response layout with a one-byte "P" payload.
+func EncodeHeartbeatAck() []byte {
+	return frame([]byte{MessageTypeHeartbeatAck, 0, 0, 0, 1, 1, 0, 0, 0, 1, 'P'})
+}
+
+// EncodeLoginResponse builds a login response frame, as sent by the engine
+func EncodeLoginResponse(resp LoginResponse) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeLoginResponse)
+	if resp.Success {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	binary.Write(buf, binary.BigEndian, uint32(len(resp.Message)))
+	buf.WriteString(resp.Message)
+	if resp.Version != 0 {
+		buf.WriteByte(resp.Version)
+	}
+	return frame(buf.Bytes())
+}
+
+// EncodeOrderResponse builds an order response frame, as sent by the engine
+func EncodeOrderResponse(resp OrderResponse) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeOrderResponse)
+	binary.Write(buf, binary.BigEndian, uint32(len(resp.OrderID)))
+	if resp.Accepted {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	binary.Write(buf, binary.BigEndian, uint32(len(resp.Message)))
+	buf.WriteString(resp.OrderID)
+	buf.WriteString(resp.Message)
+	buf.Write(resp.Extra)
+	return frame(buf.Bytes())
+}
+
+// DecodeLoginRequest parses a login frame body (as returned by ReadFrame)
+// into the token and the requested protocol version, 0 if none was sent
+func DecodeLoginRequest(body []byte) (string, uint8, error) {
+	if len(body) < 5 || body[0] != MessageTypeLoginRequest {
+		return "", 0, fmt.Errorf("malformed login request")
+	}
+	token, offset, err := readString(body, 5, binary.BigEndian.Uint32(body[1:5]), "login request token")
+	if err != nil {
+		return "", 0, err
+	}
+	var version uint8
+	if len(body) > offset {
+		version = body[offset]
+	}
+	return token, version, nil
+}
+
+// DecodeCancelOrder parses a cancel-order frame body (as returned by ReadFrame)
+func DecodeCancelOrder(body []byte) (string, error) {
+	if len(body) < 5 || body[0] != MessageTypeCancelOrder {
+		return "", fmt.Errorf("malformed cancel order")
+	}
+	orderID, _, err := readString(body, 5, binary.BigEndian.Uint32(body[1:5]), "cancel order id")
+	return orderID, err
+}
+
+// DecodeModifyOrder parses a modify-order frame body (as returned by ReadFrame)
+func DecodeModifyOrder(body []byte) (ModifyOrder, error) {
+	if len(body) < modifyOrderHeaderLen || body[0] != MessageTypeModifyOrder {
+		return ModifyOrder{}, fmt.Errorf("malformed modify order")
+	}
+	orderID, _, err := readString(body, modifyOrderHeaderLen, binary.BigEndian.Uint32(body[1:5]), "modify order id")
+	if err != nil {
+		return ModifyOrder{}, err
+	}
+	return ModifyOrder{
+		OrderID:  orderID,
+		Quantity: int64(binary.BigEndian.Uint64(body[5:13])),
+		Price:    math.Float64frombits(binary.BigEndian.Uint64(body[13:21])),
+	}, nil
+}
+
+// DecodeSubmitOrder parses a submit-order frame body (as returned by ReadFrame)
+func DecodeSubmitOrder(body []byte) (Order, error) {
+	if len(body) < submitOrderHeaderLen || body[0] != MessageTypeSubmitOrder {
+		return Order{}, fmt.Errorf("malformed submit order")
+	}
+
+	o := Order{
+		Side:        body[13],
+		Type:        body[14],
+		Quantity:    int64(binary.BigEndian.Uint64(body[15:23])),
+		Price:       math.Float64frombits(binary.BigEndian.Uint64(body[23:31])),
+		TimestampMs: int64(binary.BigEndian.Uint64(body[31:39])),
+	}
+	var err error
+	offset := submitOrderHeaderLen
+	if o.OrderID, offset, err = readString(body, offset, binary.BigEndian.Uint32(body[1:5]), "submit order id"); err != nil {
+		return Order{}, err
+	}
+	if o.UserID, offset, err = readString(body, offset, binary.BigEndian.Uint32(body[5:9]), "submit order user id"); err != nil {
+		return Order{}, err
+	}
+	if o.Symbol, _, err = readString(body, offset, binary.BigEndian.Uint32(body[9:13]), "submit order symbol"); err != nil {
+		return Order{}, err
+	}
+	return o, nil
+}
+
+// ParseMarketDataResponse parses a top-of-book frame body (as returned by
+// ReadFrame): type(1) + symbol_len(4) + bid(8) + bid_qty(8) + ask(8) +
+// ask_qty(8) + symbol. Prices are IEEE-754 doubles.
+func ParseMarketDataResponse(body []byte) (TopOfBook, error) {
+	if len(body) < marketDataHeaderLen {
+		return TopOfBook{}, fmt.Errorf("market data response too short: %d bytes", len(body))
+	}
+	if body[0] != MessageTypeMarketDataResponse {
+		return TopOfBook{}, fmt.Errorf("unexpected response type: %d", body[0])
+	}
+
+	symbol, _, err := readString(body, marketDataHeaderLen, binary.BigEndian.Uint32(body[1:5]), "market data symbol")
+	if err != nil {
+		return TopOfBook{}, err
+	}
+	return TopOfBook{
+		Bid:    math.Float64frombits(binary.BigEndian.Uint64(body[5:13])),
+		BidQty: int64(binary.BigEndian.Uint64(body[13:21])),
+		Ask:    math.Float64frombits(binary.BigEndian.Uint64(body[21:29])),
+		AskQty: int64(binary.BigEndian.Uint64(body[29:37])),
+		Symbol: symbol,
+	}, nil
+}
+
+// EncodeMarketDataResponse builds a top-of-book frame, as an engine would
+func EncodeMarketDataResponse(tob TopOfBook) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeMarketDataResponse)
+	binary.Write(buf, binary.BigEndian, uint32(len(tob.Symbol)))
+	binary.Write(buf, binary.BigEndian, math.Float64bits(tob.Bid))
+	binary.Write(buf, binary.BigEndian, uint64(tob.BidQty))
+	binary.Write(buf, binary.BigEndian, math.Float64bits(tob.Ask))
+	binary.Write(buf, binary.BigEndian, uint64(tob.AskQty))
+	buf.WriteString(tob.Symbol)
+	return frame(buf.Bytes())
+}
+
+// ParseDepthResponse parses a book depth frame body (as returned by
+// ReadFrame): type(1) + symbol_len(4) + bid_levels(4) + ask_levels(4) +
+// symbol, then each bid and then each ask level as price(8) + qty(8).
+// Prices are IEEE-754 doubles.
+func ParseDepthResponse(body []byte) (BookDepth, error) {
+	if len(body) < depthHeaderLen {
+		return BookDepth{}, fmt.Errorf("depth response too short: %d bytes", len(body))
+	}
+	if body[0] != MessageTypeDepthResponse {
+		return BookDepth{}, fmt.Errorf("unexpected response type: %d", body[0])
+	}
+
+	symbol, offset, err := readString(body, depthHeaderLen, binary.BigEndian.Uint32(body[1:5]), "depth symbol")
+	if err != nil {
+		return BookDepth{}, err
+	}
+	bidLevels := uint64(binary.BigEndian.Uint32(body[5:9]))
+	askLevels := uint64(binary.BigEndian.Uint32(body[9:13]))
+	if want := (bidLevels + askLevels) * depthLevelLen; want != uint64(len(body)-offset) {
+		return BookDepth{}, fmt.Errorf("depth levels need %d bytes, %d remain", want, len(body)-offset)
+	}
+
+	readLevels := func(n uint64) []BookLevel {
+		levels := make([]BookLevel, n)
+		for i := range levels {
+			levels[i] = BookLevel{
+				Price:    math.Float64frombits(binary.BigEndian.Uint64(body[offset : offset+8])),
+				Quantity: int64(binary.BigEndian.Uint64(body[offset+8 : offset+16])),
+			}
+			offset += depthLevelLen
+		}
+		return levels
+	}
+	d := BookDepth{Symbol: symbol}
+	d.Bids = readLevels(bidLevels)
+	d.Asks = readLevels(askLevels)
+	return d, nil
+}
+
+// EncodeDepthResponse builds a book depth frame, as an engine would
+func EncodeDepthResponse(d BookDepth) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeDepthResponse)
+	binary.Write(buf, binary.BigEndian, uint32(len(d.Symbol)))
+	binary.Write(buf, binary.BigEndian, uint32(len(d.Bids)))
+	binary.Write(buf, binary.BigEndian, uint32(len(d.Asks)))
+	buf.WriteString(d.Symbol)
+	for _, side := range [][]BookLevel{d.Bids, d.Asks} {
+		for _, l := range side {
+			binary.Write(buf, binary.BigEndian, math.Float64bits(l.Price))
+			binary.Write(buf, binary.BigEndian, uint64(l.Quantity))
+		}
+	}
+	return frame(buf.Bytes())
+}
+
+// DecodeDepthRequest parses a book depth query body (as returned by
+// ReadFrame)
+func DecodeDepthRequest(body []byte) (string, error) {
+	if len(body) < 5 || body[0] != MessageTypeDepthRequest {
+		return "", fmt.Errorf("malformed depth request")
+	}
+	symbol, _, err := readString(body, 5, binary.BigEndian.Uint32(body[1:5]), "depth request symbol")
+	return symbol, err
+}
+
+// DecodeMarketDataRequest parses a top-of-book query body (as returned by
+// ReadFrame)
+func DecodeMarketDataRequest(body []byte) (string, error) {
+	if len(body) < 5 || body[0] != MessageTypeMarketDataRequest {
+		return "", fmt.Errorf("malformed market data request")
+	}
+	symbol, _, err := readString(body, 5, binary.BigEndian.Uint32(body[1:5]), "market data request symbol")
+	return symbol, err
+}
+
+// ParseExecutionReport parses an execution report frame body (as returned
+// by ReadFrame): type(1) + order_id_len(4) + symbol_len(4) + quantity(8) +
+// price(8) + order_id + symbol. The price is an IEEE-754 double.
+func ParseExecutionReport(body []byte) (ExecutionReport, error) {
+	if len(body) < executionReportLen {
+		return ExecutionReport{}, fmt.Errorf("execution report too short: %d bytes", len(body))
+	}
+	if body[0] != MessageTypeExecutionReport {
+		return ExecutionReport{}, fmt.Errorf("unexpected message type: %d", body[0])
+	}
+
+	orderID, offset, err := readString(body, executionReportLen, binary.BigEndian.Uint32(body[1:5]), "execution report order_id")
+	if err != nil {
+		return ExecutionReport{}, err
+	}
+	symbol, _, err := readString(body, offset, binary.BigEndian.Uint32(body[5:9]), "execution report symbol")
+	if err != nil {
+		return ExecutionReport{}, err
+	}
+	return ExecutionReport{
+		OrderID:  orderID,
+		Symbol:   symbol,
+		Quantity: int64(binary.BigEndian.Uint64(body[9:17])),
+		Price:    math.Float64frombits(binary.BigEndian.Uint64(body[17:25])),
+	}, nil
+}
+
+// EncodeExecutionReport builds an execution report frame, as an engine
+// would push it
+func EncodeExecutionReport(r ExecutionReport) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(MessageTypeExecutionReport)
+	binary.Write(buf, binary.BigEndian, uint32(len(r.OrderID)))
+	binary.Write(buf, binary.BigEndian, uint32(len(r.Symbol)))
+	binary.Write(buf, binary.BigEndian, uint64(r.Quantity))
+	binary.Write(buf, binary.BigEndian, math.Float64bits(r.Price))
+	buf.WriteString(r.OrderID)
+	buf.WriteString(r.Symbol)
+	return frame(buf.Bytes())
+}
@@ -0,0 +1,478 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustEncodeSubmitOrder(t *testing.T, o Order) []byte {
+	t.Helper()
+	f, err := EncodeSubmitOrder(o)
+	if err != nil {
+		t.Fatalf("EncodeSubmitOrder: %v", err)
+	}
+	return f
+}
+
+func TestSubmitOrderValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   uint8
+		qty   int64
+		price float64
+		valid bool
+	}{
+		{"limit minimal", OrderTypeLimit, 1, 0.01, true},
+		{"limit zero quantity", OrderTypeLimit, 0, 100, false},
+		{"limit negative quantity", OrderTypeLimit, -1, 100, false},
+		{"limit zero price", OrderTypeLimit, 1, 0, false},
+		{"limit negative price", OrderTypeLimit, 1, -0.01, false},
+		{"limit NaN price", OrderTypeLimit, 1, math.NaN(), false},
+		{"limit infinite price", OrderTypeLimit, 1, math.Inf(1), false},
+		{"ioc zero price", OrderTypeIOC, 1, 0, false},
+		{"fok zero price", OrderTypeFOK, 1, 0, false},
+		{"market zero price", OrderTypeMarket, 1, 0, true},
+		{"market with price", OrderTypeMarket, 1, 100, true},
+		{"market negative price", OrderTypeMarket, 1, -1, false},
+		{"market zero quantity", OrderTypeMarket, 0, 0, false},
+	}
+	for _, tt := range tests {
+		f, err := EncodeSubmitOrder(Order{Symbol: "AAPL", Type: tt.typ, Quantity: tt.qty, Price: tt.price})
+		if tt.valid {
+			if err != nil || len(f) == 0 {
+				t.Errorf("%s: EncodeSubmitOrder = %d bytes, %v; want a frame", tt.name, len(f), err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidOrder) || f != nil {
+			t.Errorf("%s: EncodeSubmitOrder = %d bytes, %v; want ErrInvalidOrder", tt.name, len(f), err)
+		}
+	}
+}
+
+func TestLengthPrefixMatchesFrameSize(t *testing.T) {
+	frames := map[string][]byte{
+		"login request":  EncodeLoginRequest("token-abc", ProtocolVersion),
+		"submit order":   mustEncodeSubmitOrder(t, Order{OrderID: "o1", UserID: "u1", Symbol: "AAPL", Quantity: 1, Price: 1}),
+		"cancel order":   EncodeCancelOrder("o1"),
+		"login response": EncodeLoginResponse(LoginResponse{Success: true, Message: "ok"}),
+		"order response": EncodeOrderResponse(OrderResponse{OrderID: "o1", Accepted: true, Message: "Order accepted"}),
+	}
+	for name, f := range frames {
+		if got := binary.BigEndian.Uint32(f[:4]); int(got) != len(f) {
+			t.Errorf("%s: length prefix %d, frame is %d bytes", name, got, len(f))
+		}
+	}
+}
+
+func TestLoginRequestRoundTrip(t *testing.T) {
+	for _, token := range []string{"", "t", "a-much-longer-trading-token-value"} {
+		body, err := ReadFrame(bytes.NewReader(EncodeLoginRequest(token, ProtocolVersion)))
+		if err != nil {
+			t.Fatalf("ReadFrame: %v", err)
+		}
+		got, version, err := DecodeLoginRequest(body)
+		if err != nil {
+			t.Fatalf("DecodeLoginRequest: %v", err)
+		}
+		if got != token || version != ProtocolVersion {
+			t.Errorf("token = %q, version %d; want %q, version %d", got, version, token, ProtocolVersion)
+		}
+	}
+
+	// A frame from a client that predates negotiation ends at the token
+	legacy := []byte{MessageTypeLoginRequest, 0, 0, 0, 1, 't'}
+	if _, version, err := DecodeLoginRequest(legacy); err != nil || version != 0 {
+		t.Errorf("legacy login request: version %d, err %v; want 0, nil", version, err)
+	}
+}
+
+func TestSubmitOrderRoundTrip(t *testing.T) {
+	tests := []Order{
+		{OrderID: "order_1", UserID: "user_1", Symbol: "AAPL", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: 100, Price: 150.25, TimestampMs: 1700000000000},
+		{OrderID: "o", UserID: "u", Symbol: "TSLA", Side: OrderSideSell, Type: OrderTypeMarket, Quantity: 1, Price: 0, TimestampMs: 1},
+		{Symbol: "X", Quantity: 1 << 40, Price: 99999.99},
+	}
+	for _, want := range tests {
+		body, err := ReadFrame(bytes.NewReader(mustEncodeSubmitOrder(t, want)))
+		if err != nil {
+			t.Fatalf("ReadFrame: %v", err)
+		}
+		got, err := DecodeSubmitOrder(body)
+		if err != nil {
+			t.Fatalf("DecodeSubmitOrder: %v", err)
+		}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestCancelOrderRoundTrip(t *testing.T) {
+	for _, orderID := range []string{"", "o", "order_1700000000000000000_42"} {
+		body, err := ReadFrame(bytes.NewReader(EncodeCancelOrder(orderID)))
+		if err != nil {
+			t.Fatalf("ReadFrame: %v", err)
+		}
+		got, err := DecodeCancelOrder(body)
+		if err != nil {
+			t.Fatalf("DecodeCancelOrder: %v", err)
+		}
+		if got != orderID {
+			t.Errorf("order id = %q, want %q", got, orderID)
+		}
+	}
+}
+
+func TestModifyOrderRoundTrip(t *testing.T) {
+	want := ModifyOrder{OrderID: "order_1700000000000000000_42", Quantity: 75, Price: 151.25}
+	body, err := ReadFrame(bytes.NewReader(EncodeModifyOrder(want)))
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	got, err := DecodeModifyOrder(body)
+	if err != nil {
+		t.Fatalf("DecodeModifyOrder: %v", err)
+	}
+	if got != want {
+		t.Errorf("DecodeModifyOrder = %+v, want %+v", got, want)
+	}
+
+	if _, err := DecodeModifyOrder(body[:len(body)-1]); err == nil {
+		t.Error("DecodeModifyOrder accepted a truncated order id")
+	}
+}
+
+func TestParseMarketDataResponse(t *testing.T) {
+	// Hand-built MARKET_DATA_RESPONSE body for AAPL 150.25 x 300 / 150.5 x 200
+	body := []byte{MessageTypeMarketDataResponse, 0, 0, 0, 4}
+	body = binary.BigEndian.AppendUint64(body, math.Float64bits(150.25))
+	body = binary.BigEndian.AppendUint64(body, 300)
+	body = binary.BigEndian.AppendUint64(body, math.Float64bits(150.5))
+	body = binary.BigEndian.AppendUint64(body, 200)
+	body = append(body, "AAPL"...)
+
+	got, err := ParseMarketDataResponse(body)
+	if err != nil {
+		t.Fatalf("ParseMarketDataResponse: %v", err)
+	}
+	want := TopOfBook{Symbol: "AAPL", Bid: 150.25, BidQty: 300, Ask: 150.5, AskQty: 200}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	frameBody, err := ReadFrame(bytes.NewReader(EncodeMarketDataResponse(want)))
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	if !bytes.Equal(frameBody, body) {
+		t.Errorf("EncodeMarketDataResponse body = % x, want % x", frameBody, body)
+	}
+
+	if _, err := ParseMarketDataResponse(body[:20]); err == nil {
+		t.Error("truncated response parsed without error")
+	}
+}
+
+func TestDepthRoundTrip(t *testing.T) {
+	body, err := ReadFrame(bytes.NewReader(EncodeDepthRequest("DEPTHCHK")))
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	symbol, err := DecodeDepthRequest(body)
+	if err != nil || symbol != "DEPTHCHK" {
+		t.Fatalf("DecodeDepthRequest = %q, %v", symbol, err)
+	}
+
+	want := BookDepth{
+		Symbol: "AAPL",
+		Bids:   []BookLevel{{Price: 150.25, Quantity: 300}, {Price: 150, Quantity: 10}},
+		Asks:   []BookLevel{{Price: 150.5, Quantity: 200}},
+	}
+	body, err = ReadFrame(bytes.NewReader(EncodeDepthResponse(want)))
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	got, err := ParseDepthResponse(body)
+	if err != nil {
+		t.Fatalf("ParseDepthResponse: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseDepthResponse(body[:len(body)-1]); err == nil {
+		t.Error("truncated level parsed without error")
+	}
+	if _, err := ParseDepthResponse(append(body, 0)); err == nil {
+		t.Error("trailing bytes parsed without error")
+	}
+}
+
+func TestExecutionReportRoundTrip(t *testing.T) {
+	want := ExecutionReport{OrderID: "order_1", Symbol: "AAPL", Quantity: 40, Price: 150.25}
+	body, err := ReadFrame(bytes.NewReader(EncodeExecutionReport(want)))
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	if !IsPush(body[0]) {
+		t.Errorf("type %d is not a push", body[0])
+	}
+	got, err := ParseExecutionReport(body)
+	if err != nil {
+		t.Fatalf("ParseExecutionReport: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if _, err := ParseExecutionReport(body[:len(body)-1]); err == nil {
+		t.Error("truncated symbol parsed without error")
+	}
+}
+
+func TestLoginResponseRoundTrip(t *testing.T) {
+	tests := []LoginResponse{
+		{Success: true, Message: "Authentication successful"},
+		{Success: false, Message: "Invalid or expired token"},
+		{Success: true},
+		{Success: true, Message: "ok", Version: ProtocolVersion},
+	}
+	for _, want := range tests {
+		got, err := DecodeLoginResponse(bytes.NewReader(EncodeLoginResponse(want)))
+		if err != nil {
+			t.Fatalf("DecodeLoginResponse: %v", err)
+		}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestParseOrderResponseOrderID(t *testing.T) {
+	// Hand-built ORDER_RESPONSE body: order_id starts at offset 10
+	body := []byte{
+		MessageTypeOrderResponse,
+		0, 0, 0, 9, // order_id_len
+		1,          // accepted
+		0, 0, 0, 2, // message_len
+		'o', 'r', 'd', 'e', 'r', '_', '4', '2', '7',
+		'o', 'k',
+	}
+	resp, err := ParseOrderResponse(body)
+	if err != nil {
+		t.Fatalf("ParseOrderResponse: %v", err)
+	}
+	if resp.OrderID != "order_427" || !resp.Accepted || resp.Message != "ok" {
+		t.Errorf("got %+v, want order_427 accepted with message ok", resp)
+	}
+}
+
+func TestOrderResponseRoundTrip(t *testing.T) {
+	tests := []OrderResponse{
+		{OrderID: "order_123", Accepted: true, Message: "Order accepted"},
+		{OrderID: "order_456", Accepted: false, Message: "Insufficient buying power"},
+		{Accepted: false, Message: "Not authenticated"},
+		{OrderID: "order_789", Accepted: false, Message: "rejected", Extra: []byte{0x00, 0x2a}},
+	}
+	for _, want := range tests {
+		got, err := DecodeOrderResponse(bytes.NewReader(EncodeOrderResponse(want)))
+		if err != nil {
+			t.Fatalf("DecodeOrderResponse: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+
+	resp := tests[3]
+	if code, ok := resp.RejectCode(); !ok || code != 42 {
+		t.Errorf("RejectCode() = %d, %v; want 42, true", code, ok)
+	}
+	if _, ok := resp.ServerTime(); ok {
+		t.Error("version 2 response reported a server time")
+	}
+
+	at := time.UnixMicro(1760000000123456)
+	resp.Extra = binary.BigEndian.AppendUint64([]byte{0, 0}, uint64(at.UnixMicro()))
+	if got, ok := resp.ServerTime(); !ok || !got.Equal(at) {
+		t.Errorf("ServerTime() = %v, %v; want %v, true", got, ok, at)
+	}
+}
+
+func TestDecodeRejectsWrongType(t *testing.T) {
+	if _, err := DecodeOrderResponse(bytes.NewReader(EncodeLoginResponse(LoginResponse{Success: true}))); err == nil {
+		t.Error("DecodeOrderResponse accepted a login response frame")
+	}
+	if _, err := DecodeLoginResponse(bytes.NewReader(EncodeOrderResponse(OrderResponse{Accepted: true, Message: "x"}))); err == nil {
+		t.Error("DecodeLoginResponse accepted an order response frame")
+	}
+}
+
+func TestDecodeRequestBadLengths(t *testing.T) {
+	order, err := EncodeSubmitOrder(Order{OrderID: "order_1", UserID: "user_1", Symbol: "AAPL", Side: OrderSideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoders := []struct {
+		name    string
+		body    []byte
+		lengths []int // offsets of the length fields
+		decode  func(body []byte) error
+	}{
+		{"login", EncodeLoginRequest("token", 3)[lengthPrefixSize:], []int{1}, func(b []byte) error { _, _, err := DecodeLoginRequest(b); return err }},
+		{"cancel", EncodeCancelOrder("order_1")[lengthPrefixSize:], []int{1}, func(b []byte) error { _, err := DecodeCancelOrder(b); return err }},
+		{"modify", EncodeModifyOrder(ModifyOrder{OrderID: "order_1", Quantity: 1, Price: 100})[lengthPrefixSize:], []int{1}, func(b []byte) error { _, err := DecodeModifyOrder(b); return err }},
+		{"submit", order[lengthPrefixSize:], []int{1, 5, 9}, func(b []byte) error { _, err := DecodeSubmitOrder(b); return err }},
+		{"market data", EncodeMarketDataRequest("AAPL")[lengthPrefixSize:], []int{1}, func(b []byte) error { _, err := DecodeMarketDataRequest(b); return err }},
+	}
+	// Lengths past the body, including values that would overflow an int
+	// offset on 32-bit platforms, are reported rather than sliced
+	for _, d := range decoders {
+		if err := d.decode(d.body); err != nil {
+			t.Errorf("%s: valid body: %v", d.name, err)
+		}
+		for _, at := range d.lengths {
+			for _, bad := range []uint32{uint32(len(d.body)), math.MaxInt32, math.MaxUint32} {
+				body := bytes.Clone(d.body)
+				binary.BigEndian.PutUint32(body[at:at+4], bad)
+				if err := d.decode(body); err == nil {
+					t.Errorf("%s: length %d at offset %d decoded without error", d.name, bad, at)
+				}
+			}
+		}
+	}
+}
+
+func TestParseOrderResponseBadLengths(t *testing.T) {
+	valid := EncodeOrderResponse(OrderResponse{OrderID: "order_1", Accepted: true, Message: "Order accepted"})[lengthPrefixSize:]
+
+	// Every truncation of a valid body must fail cleanly
+	for n := 0; n < len(valid); n++ {
+		if _, err := ParseOrderResponse(valid[:n]); err == nil {
+			t.Errorf("ParseOrderResponse accepted a body truncated to %d of %d bytes", n, len(valid))
+		}
+	}
+
+	// Oversized order_id_len and message_len, including values that would
+	// overflow an int offset, must be reported rather than sliced
+	for _, bad := range []uint32{uint32(len(valid)), 1 << 20, math.MaxInt32, math.MaxUint32} {
+		body := bytes.Clone(valid)
+		binary.BigEndian.PutUint32(body[1:5], bad)
+		if _, err := ParseOrderResponse(body); err == nil {
+			t.Errorf("order_id_len %d: ParseOrderResponse returned no error", bad)
+		}
+
+		body = bytes.Clone(valid)
+		binary.BigEndian.PutUint32(body[6:10], bad)
+		if _, err := ParseOrderResponse(body); err == nil {
+			t.Errorf("message_len %d: ParseOrderResponse returned no error", bad)
+		}
+	}
+
+	// Random garbage after a valid type byte never panics
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		body := make([]byte, rng.Intn(64))
+		rng.Read(body)
+		if len(body) > 0 {
+			body[0] = MessageTypeOrderResponse
+		}
+		ParseOrderResponse(body)
+		ParseMarketDataResponse(append([]byte{MessageTypeMarketDataResponse}, body...))
+		DecodeLoginResponse(bytes.NewReader(frame(append([]byte{MessageTypeLoginResponse}, body...))))
+	}
+}
+
+// FuzzParseOrderResponse checks that no body makes the parser panic; run
+// with go test -fuzz=FuzzParseOrderResponse ./protocol for a longer search.
+func FuzzParseOrderResponse(f *testing.F) {
+	f.Add(EncodeOrderResponse(OrderResponse{OrderID: "order_1", Accepted: true, Message: "ok"})[lengthPrefixSize:])
+	f.Add([]byte{MessageTypeOrderResponse, 0xff, 0xff, 0xff, 0xff, 1, 0, 0, 0, 0})
+	f.Fuzz(func(t *testing.T, body []byte) {
+		resp, err := ParseOrderResponse(body)
+		if err == nil && len(resp.OrderID)+len(resp.Message) > len(body) {
+			t.Fatalf("parsed %d bytes of strings from a %d-byte body", len(resp.OrderID)+len(resp.Message), len(body))
+		}
+	})
+}
+
+// FuzzDecodeOrderResponse feeds arbitrary streams, length prefix included,
+// to DecodeOrderResponse. A decoded response must account for exactly the
+// bytes its frame declared: re-encoding it gives a frame of the same length.
+func FuzzDecodeOrderResponse(f *testing.F) {
+	f.Add(EncodeOrderResponse(OrderResponse{OrderID: "order_1", Accepted: true, Message: "Order accepted"}))
+	f.Add(EncodeOrderResponse(OrderResponse{OrderID: "order_2", Message: "Insufficient buying power", Extra: []byte{0, 7}}))
+	f.Add(EncodeHeartbeatAck())
+	f.Add(frame([]byte{MessageTypeOrderResponse, 0, 0, 0, 2, 1, 0xff, 0xff, 0xff, 0xff, 'o', '1'}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		resp, err := DecodeOrderResponse(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		declared := binary.BigEndian.Uint32(data[:lengthPrefixSize])
+		if got := len(EncodeOrderResponse(resp)); uint32(got) != declared {
+			t.Fatalf("frame declared %d bytes, decoded response re-encodes to %d: %+v", declared, got, resp)
+		}
+	})
+}
+
+// FuzzDecodeLoginResponse feeds arbitrary streams, length prefix included,
+// to DecodeLoginResponse. A decoded response must fit in its frame.
+func FuzzDecodeLoginResponse(f *testing.F) {
+	f.Add(EncodeLoginResponse(LoginResponse{Success: true, Message: "Login successful"}))
+	f.Add(EncodeLoginResponse(LoginResponse{Message: "Invalid or expired token"}))
+	f.Add(EncodeLoginResponse(LoginResponse{Success: true, Message: "ok", Version: ProtocolVersion}))
+	f.Add(frame([]byte{MessageTypeLoginResponse, 1, 0xff, 0xff, 0xff, 0xff, 'o', 'k'}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		resp, err := DecodeLoginResponse(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		declared := binary.BigEndian.Uint32(data[:lengthPrefixSize])
+		if got := len(EncodeLoginResponse(resp)); uint32(got) > declared {
+			t.Fatalf("frame declared %d bytes, decoded response re-encodes to %d: %+v", declared, got, resp)
+		}
+	})
+}
+
+func TestReadFrameRejectsOversizedLength(t *testing.T) {
+	prefix := binary.BigEndian.AppendUint32(nil, MaxFrameLen+1)
+	if _, err := ReadFrame(bytes.NewReader(prefix)); err == nil {
+		t.Error("ReadFrame accepted a length prefix over MaxFrameLen")
+	}
+	if _, err := DecodeLoginResponse(bytes.NewReader(frame([]byte{MessageTypeLoginResponse, 1, 0, 0, 0, 9, 'o', 'k'}))); err == nil {
+		t.Error("DecodeLoginResponse accepted a message_len past the end of the frame")
+	}
+}
+
+func TestPriceWireEncoding(t *testing.T) {
+	f := mustEncodeSubmitOrder(t, Order{Symbol: "AAPL", Quantity: 1, Price: 150.25})
+
+	// length(4) + type(1) + 3 string lens(12) + side(1) + type(1) + quantity(8)
+	const priceOffset = 4 + 1 + 12 + 1 + 1 + 8
+	got := f[priceOffset : priceOffset+8]
+
+	// 150.25 as a big-endian IEEE-754 double, which the engine reads with ntohll
+	want := []byte{0x40, 0x62, 0xc8, 0x00, 0x00, 0x00, 0x00, 0x00}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("price bytes = % x, want % x", got, want)
+	}
+	if p := math.Float64frombits(binary.BigEndian.Uint64(got)); p != 150.25 {
+		t.Errorf("decoded price = %v, want 150.25", p)
+	}
+}
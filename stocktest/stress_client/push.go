@@ -0,0 +1,328 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// pushQueueLen is how many response frames a connection's reader may hold
+// ahead of the caller before it stops reading
+const pushQueueLen = 16
+
+// pushConn demultiplexes an engine connection. A reader goroutine reads
+// every frame as it arrives: frames the engine pushes on its own go to
+// onPush, order responses go to the caller routed to their order ID (see
+// routeOrderResponse), and all others are queued in arrival order for Read.
+// Code that writes a request and reads its response therefore never sees a
+// push, however the two interleave on the wire, and several orders can wait
+// on one connection at once. Read deadlines apply to waiting on the queue.
+type pushConn struct {
+	net.Conn
+	onPush func(body []byte)
+
+	frames chan []byte   // whole response frames, length prefix included
+	err    error         // why the reader stopped; set before frames closes
+	closed chan struct{} // closed by Close to stop the reader
+	done   chan struct{} // closed when the reader stops
+	once   sync.Once
+	buf    []byte // rest of the frame being read
+
+	mu       sync.Mutex
+	deadline time.Time
+	wake     chan struct{} // closed when the read deadline changes
+
+	// routes are the callers waiting for an order response, oldest first.
+	// Once any caller has been routed, order responses never reach Read.
+	routeMu sync.Mutex
+	routes  []*orderRoute
+	routed  bool
+
+	// cancelsUnanswered and modifiesUnanswered are set once a cancel or
+	// modify on this connection went unanswered; later ones are skipped
+	// rather than waited out
+	cancelsUnanswered  atomic.Bool
+	modifiesUnanswered atomic.Bool
+}
+
+func newPushConn(conn net.Conn, onPush func(body []byte)) *pushConn {
+	c := &pushConn{
+		Conn:   conn,
+		onPush: onPush,
+		frames: make(chan []byte, pushQueueLen),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+		wake:   make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+func (c *pushConn) readLoop() {
+	defer close(c.done)
+	defer close(c.frames)
+	for {
+		body, err := protocol.ReadFrame(c.Conn)
+		if err != nil {
+			// Hand Read the connection's own error, such as io.EOF, for the
+			// caller's ReadFrame to wrap
+			if inner := errors.Unwrap(err); inner != nil {
+				err = inner
+			}
+			c.err = err
+			return
+		}
+		if len(body) > 0 && protocol.IsPush(body[0]) {
+			// Never read through the countingConn wrapping c
+			atomic.AddInt64(&bytesReceived, int64(4+len(body)))
+			c.onPush(body)
+			continue
+		}
+		if len(body) > 0 && body[0] == protocol.MessageTypeOrderResponse && c.route(body) {
+			continue
+		}
+		frame := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(body)), uint32(4+len(body)))
+		select {
+		case c.frames <- append(frame, body...):
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+func (c *pushConn) Read(p []byte) (int, error) {
+	for len(c.buf) == 0 {
+		c.mu.Lock()
+		deadline, wake := c.deadline, c.wake
+		c.mu.Unlock()
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if !deadline.IsZero() {
+			wait := time.Until(deadline)
+			if wait <= 0 {
+				return 0, os.ErrDeadlineExceeded
+			}
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+
+		select {
+		case frame, ok := <-c.frames:
+			if !ok {
+				return 0, c.err
+			}
+			c.buf = frame
+		case <-expired:
+			return 0, os.ErrDeadlineExceeded
+		case <-wake:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+func (c *pushConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.deadline = t
+	close(c.wake)
+	c.wake = make(chan struct{})
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *pushConn) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t)
+	return c.Conn.SetWriteDeadline(t)
+}
+
+func (c *pushConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return c.Conn.Close()
+}
+
+// broken reports whether the reader has stopped, so no further response
+// can arrive on c
+func (c *pushConn) broken() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// route hands an order response to the caller waiting for its order ID and
+// reports whether it was taken off the stream. A response with no order ID,
+// such as the engine's "Not authenticated" reject, goes to the oldest
+// caller, since the engine answers in order. One nobody waits for any
+// longer, because its caller timed out, is dropped.
+func (c *pushConn) route(body []byte) bool {
+	c.routeMu.Lock()
+	defer c.routeMu.Unlock()
+	if !c.routed {
+		return false
+	}
+	// Counted before the caller wakes, as the countingConn would have
+	atomic.AddInt64(&bytesReceived, int64(4+len(body)))
+	i := -1
+	if resp, err := protocol.ParseOrderResponse(body); err != nil || resp.OrderID == "" {
+		// The oldest caller reports a malformed response
+		if len(c.routes) > 0 {
+			i = 0
+		}
+	} else {
+		i = slices.IndexFunc(c.routes, func(r *orderRoute) bool { return r.orderID == resp.OrderID })
+	}
+	if i < 0 {
+		slog.Debug("dropping order response no caller is waiting for", "remote", c.RemoteAddr().String())
+		return true
+	}
+	r := c.routes[i]
+	c.routes = slices.Delete(c.routes, i, i+1)
+	r.body <- body
+	return true
+}
+
+// orderRoute is one caller waiting on a pushConn for the order response
+// echoing orderID
+type orderRoute struct {
+	c       *pushConn
+	conn    net.Conn // as the caller was handed it
+	orderID string
+	body    chan []byte
+}
+
+// routeOrderResponse has the order response echoing orderID delivered to
+// the caller, if conn is an engine connection with a reader goroutine.
+// Call it before writing the request; ok is false for other connections,
+// whose callers read the response from the stream.
+func routeOrderResponse(conn net.Conn, orderID string) (r *orderRoute, ok bool) {
+	c := unwrapPushConn(conn)
+	if c == nil {
+		return nil, false
+	}
+	r = &orderRoute{c: c, conn: conn, orderID: orderID, body: make(chan []byte, 1)}
+	c.routeMu.Lock()
+	c.routed = true
+	c.routes = append(c.routes, r)
+	c.routeMu.Unlock()
+	return r, true
+}
+
+// Close stops routing r's response; one arriving later is dropped
+func (r *orderRoute) Close() {
+	r.c.routeMu.Lock()
+	defer r.c.routeMu.Unlock()
+	if i := slices.Index(r.c.routes, r); i >= 0 {
+		r.c.routes = slices.Delete(r.c.routes, i, i+1)
+	}
+}
+
+// Await returns the routed order response. A connection lent by a pool goes
+// back to it first, so other requests can be written on it while this one
+// waits. The wait ends at the earliest of ctx's deadline, -io-timeout and
+// timeout (0 for none), with os.ErrDeadlineExceeded, or with the
+// connection's error if it fails.
+func (r *orderRoute) Await(ctx context.Context, timeout time.Duration) (protocol.OrderResponse, error) {
+	if h, ok := r.conn.(*heldConn); ok {
+		h.Release()
+	}
+
+	var deadline time.Time
+	if ioTimeout > 0 {
+		deadline = time.Now().Add(ioTimeout)
+	}
+	if timeout > 0 && (deadline.IsZero() || time.Now().Add(timeout).Before(deadline)) {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	var expired <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var body []byte
+	select {
+	case body = <-r.body:
+	case <-r.c.done:
+		// A response routed just before the reader stopped still counts
+		select {
+		case body = <-r.body:
+		default:
+			return protocol.OrderResponse{}, r.c.err
+		}
+	case <-expired:
+		if ctx.Err() != nil {
+			return protocol.OrderResponse{}, ctx.Err()
+		}
+		return protocol.OrderResponse{}, os.ErrDeadlineExceeded
+	case <-ctx.Done():
+		return protocol.OrderResponse{}, ctx.Err()
+	}
+	resp, err := protocol.ParseOrderResponse(body)
+	if err != nil {
+		return resp, fmt.Errorf("%w: %v", errMalformedResponse, err)
+	}
+	return resp, nil
+}
+
+// unwrapPushConn returns the pushConn beneath conn's wrappers, or nil
+func unwrapPushConn(conn net.Conn) *pushConn {
+	for {
+		switch c := conn.(type) {
+		case *pushConn:
+			return c
+		case *heldConn:
+			conn = c.Conn
+		case *countingConn:
+			conn = c.Conn
+		default:
+			return nil
+		}
+	}
+}
+
+// recordPush counts a frame the engine pushed unsolicited
+func recordPush(body []byte) {
+	atomic.AddInt64(&stats.PushMessages, 1)
+	switch body[0] {
+	case protocol.MessageTypeExecutionReport:
+		r, err := protocol.ParseExecutionReport(body)
+		if err != nil {
+			recordError(ErrCategoryMalformedResponse)
+			slog.Warn("malformed execution report", "err", err)
+			return
+		}
+		lastTrades.Record(r.Symbol, r.Price)
+		pendingFills.Fill(r.OrderID, time.Now())
+		slog.Debug("execution report", "order_id", r.OrderID, "symbol", r.Symbol, "quantity", r.Quantity, "price", r.Price)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestPushMessageBetweenResponses(t *testing.T) {
+	resetGlobals(t)
+
+	raw, server := net.Pipe()
+	pushed := make(chan []byte, 1)
+	client := newPushConn(raw, func(body []byte) { pushed <- body })
+	defer client.Close()
+
+	// The engine answers the first order, pushes a fill, then answers the
+	// second order
+	report := protocol.ExecutionReport{OrderID: "order_1", Symbol: "AAPL", Quantity: 1, Price: 100}
+	go func() {
+		defer server.Close()
+		for i := 0; i < 2; i++ {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, _ := protocol.DecodeSubmitOrder(body)
+			server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true, Message: "Order accepted"}))
+			if i == 0 {
+				server.Write(protocol.EncodeExecutionReport(report))
+			}
+		}
+	}()
+
+	for _, id := range []string{"order_1", "order_2"} {
+		resp, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{OrderID: id})
+		if err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+		if resp.OrderID != id || !resp.Accepted {
+			t.Errorf("%s: got response %+v", id, resp)
+		}
+	}
+
+	select {
+	case body := <-pushed:
+		got, err := protocol.ParseExecutionReport(body)
+		if err != nil || got != report {
+			t.Errorf("push = %+v, %v; want %+v", got, err, report)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("push message was not delivered")
+	}
+}
+
+func TestOrdersPipelinedOnOneConnection(t *testing.T) {
+	resetGlobals(t)
+
+	// The engine answers only once every order is in, newest first, and
+	// pushes a fill between the answers, so the orders pass only if all of
+	// them are in flight on the one connection at once
+	const inFlight = 4
+	raw, server := net.Pipe()
+	var sent atomic.Int64
+	go func() {
+		defer server.Close()
+		var ids []string
+		for len(ids) < inFlight {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, _ := protocol.DecodeSubmitOrder(body)
+			ids = append(ids, o.OrderID)
+		}
+		for i := len(ids) - 1; i >= 0; i-- {
+			frame := protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: ids[i], Accepted: true, Message: "Order accepted"})
+			if i == inFlight/2 {
+				frame = append(frame, protocol.EncodeExecutionReport(protocol.ExecutionReport{OrderID: ids[i], Symbol: "AAPL", Quantity: 1, Price: 100})...)
+			}
+			sent.Add(int64(len(frame)))
+			server.Write(frame)
+		}
+		io.Copy(io.Discard, server)
+	}()
+	received := atomic.LoadInt64(&bytesReceived)
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		return newCountingConn(newPushConn(raw, recordPush), nil), nil
+	})
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 1; i <= inFlight; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("order_%d", i)
+			var resp protocol.OrderResponse
+			err := withRetry(ctx, pool, 0, func(conn net.Conn) (err error) {
+				resp, err = submitOrderTCP(ctx, conn, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{OrderID: id})
+				return err
+			})
+			if err != nil {
+				t.Errorf("%s: %v", id, err)
+			} else if resp.OrderID != id || !resp.Accepted {
+				t.Errorf("%s: got response %+v", id, resp)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&stats.PushMessages); got != 1 {
+		t.Errorf("%d pushes counted, want 1", got)
+	}
+	// Routed responses and the push never pass through the counting wrapper
+	// but still count as received
+	if got, want := atomic.LoadInt64(&bytesReceived)-received, sent.Load(); got != want {
+		t.Errorf("%d bytes counted as received, want the %d the engine sent", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Quantity models selectable with -qty-model
+const (
+	QtyModelUniform   = "uniform"
+	QtyModelLognormal = "lognormal"
+	QtyModelRoundLots = "round-lots"
+)
+
+// Quantity model shapes. Uniform quantities run 1-100, lognormal ones have
+// a median of 10 with one in a hundred above about 100, and round lots are
+// 1-10 lots.
+const (
+	uniformMaxQty    = 100
+	lognormalMedian  = 10
+	lognormalSigma   = 1.0
+	lognormalMaxQty  = 10000
+	roundLotsMaxLots = 10
+)
+
+// defaultLotSize is the -lot-size for round-lots
+const defaultLotSize = 100
+
+// QuantityModel generates order quantities. Each draws one value from the
+// worker's source per order, so switching models leaves the rest of a
+// seeded stream in step.
+type QuantityModel interface {
+	Quantity(r *rand.Rand) int64
+}
+
+// newQuantityModel builds the model named by config.QtyModel
+func newQuantityModel(config StressConfig) (QuantityModel, error) {
+	switch config.QtyModel {
+	case "", QtyModelUniform:
+		return UniformQuantity{Max: uniformMaxQty}, nil
+	case QtyModelLognormal:
+		return LognormalQuantity{Median: lognormalMedian, Sigma: lognormalSigma, Max: lognormalMaxQty}, nil
+	case QtyModelRoundLots:
+		if config.LotSize < 1 {
+			return nil, fmt.Errorf("round-lots requires a lot-size of at least 1, got %d", config.LotSize)
+		}
+		return RoundLotQuantity{Lot: int64(config.LotSize), MaxLots: roundLotsMaxLots}, nil
+	default:
+		return nil, fmt.Errorf("unknown qty model %q (want uniform, lognormal or round-lots)", config.QtyModel)
+	}
+}
+
+// UniformQuantity draws quantities uniformly from 1 to Max
+type UniformQuantity struct {
+	Max int
+}
+
+// Quantity returns a uniformly distributed quantity
+func (u UniformQuantity) Quantity(r *rand.Rand) int64 {
+	return int64(r.Intn(u.Max) + 1)
+}
+
+// LognormalQuantity draws right-skewed quantities: mostly small orders with
+// a long tail of large ones, capped at Max
+type LognormalQuantity struct {
+	Median float64
+	Sigma  float64
+	Max    int64
+}
+
+// Quantity returns a lognormally distributed quantity of at least 1
+func (l LognormalQuantity) Quantity(r *rand.Rand) int64 {
+	q := int64(math.Round(l.Median * math.Exp(r.NormFloat64()*l.Sigma)))
+	return min(max(q, 1), l.Max)
+}
+
+// RoundLotQuantity draws whole multiples of Lot, from 1 to MaxLots lots
+type RoundLotQuantity struct {
+	Lot     int64
+	MaxLots int
+}
+
+// Quantity returns a round-lot quantity
+func (l RoundLotQuantity) Quantity(r *rand.Rand) int64 {
+	return l.Lot * int64(r.Intn(l.MaxLots)+1)
+}
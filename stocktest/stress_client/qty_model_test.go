@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRoundLotQuantities(t *testing.T) {
+	config := StressConfig{QtyModel: QtyModelRoundLots, LotSize: 100}
+	model, err := newQuantityModel(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		if q := model.Quantity(r); q <= 0 || q%100 != 0 {
+			t.Fatalf("round-lot quantity %d is not a positive multiple of 100", q)
+		}
+	}
+
+	config.LotSize = 0
+	if _, err := newQuantityModel(config); err == nil {
+		t.Error("round-lots accepted a lot size of 0")
+	}
+}
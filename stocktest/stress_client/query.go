@@ -0,0 +1,32 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// queryPortfolio fetches the user's portfolio from the frontend in place of
+// an order (-query-pct), recording the read latency in the user's shard
+func queryPortfolio(frontendURL, sessionToken string, shard int) error {
+	start := time.Now()
+	_, err := fetchPortfolio(frontendURL, sessionToken)
+	latency := time.Since(start)
+
+	atomic.AddInt64(&stats.QueriesSubmitted, 1)
+	if err != nil {
+		atomic.AddInt64(&stats.QueryErrors, 1)
+		recordError(classifyError(err, ErrCategoryQuery))
+		return err
+	}
+	stats.shard(shard).recordQuery(latency)
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryPortfolioRecordsLatency(t *testing.T) {
+	resetGlobals(t)
+
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/trading/portfolio" || r.Header.Get("Authorization") != "Bearer session" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if fail {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+		io.WriteString(w, `{"positions":[{"symbol":"AAPL","quantity":10},{"symbol":"MSFT","quantity":-3}]}`)
+	}))
+	defer srv.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := queryPortfolio(srv.URL, "session", 1); err != nil {
+			t.Fatalf("query %d: %v", i, err)
+		}
+	}
+	fail = true
+	if err := queryPortfolio(srv.URL, "session", 1); err == nil {
+		t.Fatal("query against a failing frontend succeeded")
+	}
+
+	statsMutex.Lock()
+	snap := stats.snapshot()
+	statsMutex.Unlock()
+	report := buildReport(&snap, StressConfig{QueryPct: 10}, time.Second, false)
+
+	if snap.QueriesSubmitted != 4 || snap.QueryErrors != 1 {
+		t.Errorf("queries = %d sent, %d failed; want 4 and 1", snap.QueriesSubmitted, snap.QueryErrors)
+	}
+	if q := report.QueryLatency; q == nil || q.Count != 3 || q.MinMs < 5 {
+		t.Errorf("query latency = %+v, want 3 reads of at least 5ms", q)
+	}
+	if report.OrderLatency.Count != 0 || report.OrdersSubmitted != 0 {
+		t.Errorf("queries leaked into order stats: %+v", report.OrderLatency)
+	}
+	if got := snap.ErrorCategories[ErrCategoryFrontendServer]; got != 1 {
+		t.Errorf("categories = %v, want the 500 counted once", snap.ErrorCategories)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// activeUsers is the number of user workers currently running
+var activeUsers int64
+
+// dispatchUsers calls launch for users 1..n, spreading the calls evenly over
+// rampUp so the engine does not see every connection arrive at once. With no
+// ramp-up, users are launched back to back. It returns early if ctx is
+// cancelled.
+func dispatchUsers(ctx context.Context, n int, rampUp time.Duration, launch func(userID int)) {
+	if n <= 0 {
+		return
+	}
+
+	interval := time.Duration(0)
+	if rampUp > 0 && n > 1 {
+		interval = rampUp / time.Duration(n)
+	}
+	if interval <= 0 {
+		for i := 1; i <= n; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+			launch(i)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	launch(1)
+	for i := 2; i <= n; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			launch(i)
+		}
+	}
+}
+
+// launchUsers dispatches users 1..n like dispatchUsers and runs each in its
+// own goroutine once limiter grants it a slot. wg.Add is called only for a
+// user that is about to start, and run must call wg.Done when it returns,
+// as the user workers do; the slot is released after it. A user still
+// waiting for a slot when ctx is cancelled is never started, so a cancelled
+// launch leaves nothing for wg.Wait to hang on.
+func launchUsers(ctx context.Context, n int, rampUp time.Duration, limiter *concurrencyLimiter, wg *sync.WaitGroup, run func(userID int)) {
+	dispatchUsers(ctx, n, rampUp, func(userID int) {
+		if limiter.Acquire(ctx) != nil {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			atomic.AddInt64(&activeUsers, 1)
+			defer func() {
+				atomic.AddInt64(&activeUsers, -1)
+				limiter.Release()
+			}()
+			run(userID)
+		}()
+	})
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRampUpSpreadsLaunches(t *testing.T) {
+	const users = 10
+	const window = 200 * time.Millisecond
+
+	var launches []time.Duration
+	start := time.Now()
+	dispatchUsers(context.Background(), users, window, func(userID int) {
+		launches = append(launches, time.Since(start))
+	})
+
+	if len(launches) != users {
+		t.Fatalf("launched %d users, want %d", len(launches), users)
+	}
+	if launches[0] > window/users {
+		t.Errorf("first user launched at %v, want immediately", launches[0])
+	}
+	// The last launch is at (n-1)/n of the window; allow slack for the scheduler
+	if last := launches[users-1]; last < window*(users-2)/users || last > window+100*time.Millisecond {
+		t.Errorf("last user launched at %v, want close to %v", last, window*(users-1)/users)
+	}
+	// No burst: half the users must not have launched before half the window
+	if mid := launches[users/2]; mid < window*4/10 {
+		t.Errorf("user %d launched at %v, launches are bunched at the start", users/2+1, mid)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// orderLimiter caps the aggregate order rate across all workers (-rate).
+// Nil means unlimited.
+var orderLimiter *rate.Limiter
+
+// newOrderLimiter returns a limiter for ordersPerSec, or nil for unlimited.
+// A burst of one spreads sends evenly instead of releasing them in clumps.
+func newOrderLimiter(ordersPerSec float64) *rate.Limiter {
+	if ordersPerSec <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(ordersPerSec), 1)
+}
+
+// waitForOrderToken blocks until the next order may be sent
+func waitForOrderToken(ctx context.Context) error {
+	if orderLimiter == nil {
+		return nil
+	}
+	return orderLimiter.Wait(ctx)
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOrderLimiterHoldsRate(t *testing.T) {
+	orderLimiter = newOrderLimiter(200)
+	defer func() { orderLimiter = nil }()
+
+	const window = 500 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), window)
+	defer cancel()
+
+	// Several workers compete for tokens, as users do
+	var sent int64
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for waitForOrderToken(ctx) == nil {
+				atomic.AddInt64(&sent, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 200/sec over 500ms is 100 orders, plus the initial token
+	if n := atomic.LoadInt64(&sent); n < 85 || n > 110 {
+		t.Errorf("sent %d orders in %v at 200/sec, want about 100", n, window)
+	}
+}
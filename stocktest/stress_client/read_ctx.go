@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+)
+
+// ioTimeout bounds each engine read made through readFullCtx (0 = no limit)
+var ioTimeout time.Duration
+
+// readFullCtx reads exactly len(buf) bytes from conn like io.ReadFull, with
+// the read deadline set to the earlier of ctx's deadline and -io-timeout.
+// Cancelling ctx moves the deadline to now, so a read blocked on a silent
+// engine returns at once with ctx's error instead of waiting for the
+// deadline. A deadline set here is cleared on return; with neither limit
+// any deadline the caller set on conn still applies.
+func readFullCtx(ctx context.Context, conn net.Conn, buf []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	var deadline time.Time
+	if ioTimeout > 0 {
+		deadline = time.Now().Add(ioTimeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	if !deadline.IsZero() {
+		conn.SetReadDeadline(deadline)
+		defer conn.SetReadDeadline(time.Time{})
+	}
+
+	if ctx.Done() != nil {
+		fired := make(chan struct{})
+		stop := context.AfterFunc(ctx, func() {
+			conn.SetReadDeadline(time.Now())
+			close(fired)
+		})
+		// Wait out a cancel that raced the read, then clear the deadline it
+		// set so the connection stays usable
+		defer func() {
+			if !stop() {
+				<-fired
+				conn.SetReadDeadline(time.Time{})
+			}
+		}()
+	}
+
+	n, err := io.ReadFull(conn, buf)
+	if err != nil && ctx.Err() != nil {
+		return n, ctx.Err()
+	}
+	return n, err
+}
+
+// ctxReader adapts readFullCtx to io.Reader for protocol.ReadFrame and the
+// decoders built on it. Every Read fills p or fails.
+type ctxReader struct {
+	ctx  context.Context
+	conn net.Conn
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	return readFullCtx(r.ctx, r.conn, p)
+}
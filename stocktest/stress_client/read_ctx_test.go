@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestReadFullCtxReturnsOnCancel(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	// The engine reads the order and never answers
+	go func() {
+		for {
+			if _, err := protocol.ReadFrame(server); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	const after = 50 * time.Millisecond
+	time.AfterFunc(after, cancel)
+
+	start := time.Now()
+	_, err := submitOrderTCP(ctx, client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > after+time.Second {
+		t.Errorf("cancelled read returned after %v", elapsed)
+	}
+
+	// The cancel must not leave a deadline behind on the connection
+	go server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: "late", Accepted: true}))
+	buf := make([]byte, 4)
+	if _, err := readFullCtx(context.Background(), client, buf); err != nil {
+		t.Errorf("read after cancel: %v", err)
+	}
+}
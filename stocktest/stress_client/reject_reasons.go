@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// maxRejectReasons caps the distinct reasons a stats shard keeps. Further
+// reasons are counted under rejectReasonOther, so an engine that puts order
+// IDs or other unique text in its messages cannot grow the map without bound.
+const maxRejectReasons = 64
+
+// Reasons for rejections that carry no usable message
+const (
+	rejectReasonOther = "(other)"
+	rejectReasonNone  = "(no message)"
+)
+
+// rejectReason reduces a rejection to the reason it is tallied under. The
+// message is lowercased and each number replaced by "#", so "Insufficient
+// buying power: need 1520.50" and "... need 99" count as one reason. A
+// rejection with no message falls back to its reject code when protocol
+// version 2 supplied one.
+func rejectReason(message string, code uint16, hasCode bool) string {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		if hasCode {
+			return fmt.Sprintf("(code %d)", code)
+		}
+		return rejectReasonNone
+	}
+
+	runes := []rune(strings.ToLower(message))
+	var b strings.Builder
+	for i := 0; i < len(runes); i++ {
+		if !unicode.IsDigit(runes[i]) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		// Skip the rest of the number, including decimal and thousands
+		// separators between digits
+		for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) ||
+			(runes[i+1] == '.' || runes[i+1] == ',') && i+2 < len(runes) && unicode.IsDigit(runes[i+2])) {
+			i++
+		}
+		b.WriteByte('#')
+	}
+	return b.String()
+}
+
+// countRejectReason adds one rejection to reasons, folding it into
+// rejectReasonOther once maxRejectReasons distinct reasons are held
+func countRejectReason(reasons map[string]int64, reason string) {
+	if _, ok := reasons[reason]; !ok && len(reasons) >= maxRejectReasons {
+		reason = rejectReasonOther
+	}
+	reasons[reason]++
+}
+
+// logRejectReasons prints rejection counts per reason, most frequent
+// first, with each reason's share of all rejections
+func logRejectReasons(reasons map[string]int64) {
+	if len(reasons) == 0 {
+		return
+	}
+	var total int64
+	for _, n := range reasons {
+		total += n
+	}
+	keys := slices.SortedFunc(maps.Keys(reasons), func(a, b string) int {
+		if c := cmp.Compare(reasons[b], reasons[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+
+	log.Printf("Rejection Reasons:")
+	for _, reason := range keys {
+		log.Printf("  %8d (%5.1f%%)  %s", reasons[reason], float64(reasons[reason])/float64(total)*100, reason)
+	}
+}
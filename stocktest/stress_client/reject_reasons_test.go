@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+func TestRejectReasonHistogram(t *testing.T) {
+	resetGlobals(t)
+
+	messages := []string{
+		"Insufficient buying power: need 1520.50",
+		"Invalid symbol",
+		"Insufficient buying power: need 99",
+		"Rate limited",
+		"Invalid symbol",
+		"",
+		"Order accepted",
+	}
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		for _, msg := range messages {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, _ := protocol.DecodeSubmitOrder(body)
+			server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: msg == "Order accepted", Message: msg}))
+		}
+	}()
+	for range messages {
+		if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	statsMutex.Lock()
+	snap := stats.snapshot()
+	statsMutex.Unlock()
+	want := map[string]int64{
+		"insufficient buying power: need #": 2,
+		"invalid symbol":                    2,
+		"rate limited":                      1,
+		rejectReasonNone:                    1,
+	}
+	if !maps.Equal(snap.RejectReasons, want) {
+		t.Errorf("reject reasons = %v, want %v", snap.RejectReasons, want)
+	}
+
+	// Past the cap, new reasons share one bucket
+	reasons := make(map[string]int64)
+	for i := range maxRejectReasons + 10 {
+		countRejectReason(reasons, fmt.Sprintf("reason %c", 'A'+i))
+	}
+	if len(reasons) != maxRejectReasons+1 || reasons[rejectReasonOther] != 10 {
+		t.Errorf("%d reasons with %d other, want %d with 10", len(reasons), reasons[rejectReasonOther], maxRejectReasons+1)
+	}
+}
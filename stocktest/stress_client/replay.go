@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// replayOrder is one order read back from a -csv file
+type replayOrder struct {
+	Time     time.Time
+	UserID   string
+	Symbol   string
+	Side     int
+	Type     int
+	Quantity int64
+	Price    float64
+}
+
+// replayColumns are the -csv columns a replay needs
+var replayColumns = []string{"timestamp", "user_id", "symbol", "side", "type", "quantity", "price"}
+
+// loadReplayFile reads the orders recorded by -csv at path, sorted by the
+// time each was sent
+func loadReplayFile(path string) ([]replayOrder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file: %w", err)
+	}
+	defer f.Close()
+	return parseReplay(f)
+}
+
+// parseReplay parses -csv output. Columns are found by header name, so
+// files with extra or reordered columns still load.
+func parseReplay(r io.Reader) ([]replayOrder, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay header: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		col[name] = i
+	}
+	for _, name := range replayColumns {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("replay file has no %q column", name)
+		}
+	}
+
+	var orders []replayOrder
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay line %d: %w", line, err)
+		}
+		o, err := parseReplayRow(row, col)
+		if err != nil {
+			return nil, fmt.Errorf("replay line %d: %w", line, err)
+		}
+		orders = append(orders, o)
+	}
+	if len(orders) == 0 {
+		return nil, errors.New("replay file has no orders")
+	}
+
+	// Rows are written as orders complete; replay them in the order sent
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].Time.Before(orders[j].Time) })
+	return orders, nil
+}
+
+func parseReplayRow(row []string, col map[string]int) (replayOrder, error) {
+	var o replayOrder
+	var err error
+	if o.Time, err = time.Parse(time.RFC3339Nano, row[col["timestamp"]]); err != nil {
+		return o, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	o.UserID = row[col["user_id"]]
+	o.Symbol = row[col["symbol"]]
+	if o.UserID == "" || o.Symbol == "" {
+		return o, errors.New("user_id and symbol must not be empty")
+	}
+	if o.Side, err = strconv.Atoi(row[col["side"]]); err != nil {
+		return o, fmt.Errorf("invalid side: %w", err)
+	}
+	if o.Type, err = strconv.Atoi(row[col["type"]]); err != nil {
+		return o, fmt.Errorf("invalid type: %w", err)
+	}
+	if o.Quantity, err = strconv.ParseInt(row[col["quantity"]], 10, 64); err != nil {
+		return o, fmt.Errorf("invalid quantity: %w", err)
+	}
+	if o.Price, err = strconv.ParseFloat(row[col["price"]], 64); err != nil {
+		return o, fmt.Errorf("invalid price: %w", err)
+	}
+	return o, nil
+}
+
+// replayUser is the session a recorded user ID is replayed through
+type replayUser struct {
+	id      int
+	engines *enginePools
+}
+
+// runReplay re-submits orders one at a time in recorded order, so the
+// engine sees the same sequence on every replay. Each recorded user is
+// signed up and logged in as a fresh user on first use, and its orders keep
+// their recorded user ID. With timing, orders are held back to their
+// original offset from the first order; otherwise they are sent back to
+// back. -orders, -users and the order generator flags do not apply.
+func runReplay(ctx context.Context, config StressConfig, orders []replayOrder, timing bool) {
+	addrs, err := parseEngineAddrs(config.EngineAddr)
+	if err != nil {
+		slog.Error("invalid engine list", "err", err)
+		recordError(ErrCategoryConfig)
+		return
+	}
+
+	// The order in flight when ctx is cancelled is read until the drain ends
+	orderCtx, cancelOrders := drainContext(ctx, config.DrainTimeout)
+	defer cancelOrders()
+
+	users := make(map[string]*replayUser)
+	defer func() {
+		for _, u := range users {
+			u.engines.Close()
+		}
+	}()
+
+	start := time.Now()
+	for i, o := range orders {
+		if timing {
+			wait := time.Until(start.Add(o.Time.Sub(orders[0].Time)))
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		u := users[o.UserID]
+		if u == nil {
+			id := len(users) + 1
+			tokens, ok := signupAndLogin(ctx, config, id)
+			if !ok {
+				slog.Warn("replay stopped, could not log in user", "user_id", o.UserID)
+				return
+			}
+			u = &replayUser{id: id, engines: newEnginePools(addrs, config.ShardBy, id, 1, func(addr string) (net.Conn, error) {
+				return dialEngine(orderCtx, addr, tokens.TradingToken)
+			})}
+			users[o.UserID] = u
+		}
+
+		if err := waitForOrderToken(ctx); err != nil {
+			return
+		}
+		err := withRetry(ctx, u.engines.For(o.Symbol), config.MaxRetries, func(conn net.Conn) error {
+			_, err := submitOrderTCP(orderCtx, conn, o.UserID, o.Symbol, o.Side, o.Type, o.Quantity, o.Price, submitOptions{Shard: u.id})
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("replayed order failed", "line", i+2, "user_id", o.UserID, "symbol", o.Symbol, "err", err)
+		}
+	}
+}
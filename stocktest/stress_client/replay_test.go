@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReplayResubmitsRecordedOrders(t *testing.T) {
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	dir := t.TempDir()
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         2,
+		OrdersPerUser:    10,
+		Concurrency:      2,
+		OrderConcurrency: 1,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		PriceSpread:      50,
+		Symbols:          defaultSymbols,
+		CPUThreshold:     100,
+		DrainTimeout:     time.Second,
+		OrdersCSV:        filepath.Join(dir, "recorded.csv"),
+	}
+	run := func(config StressConfig) Report {
+		resetGlobals(t)
+		return runStressTest(context.Background(), config, func() bool { return false })
+	}
+
+	recorded := run(config)
+	config.Replay = config.OrdersCSV
+	config.OrdersCSV = filepath.Join(dir, "replayed.csv")
+	replayed := run(config)
+
+	if recorded.OrdersSubmitted != 20 || replayed.OrdersSubmitted != recorded.OrdersSubmitted {
+		t.Fatalf("replayed %d orders from a %d-order recording, want 20 each", replayed.OrdersSubmitted, recorded.OrdersSubmitted)
+	}
+
+	want, err := loadReplayFile(config.Replay)
+	if err != nil {
+		t.Fatalf("load recording: %v", err)
+	}
+	got, err := loadReplayFile(config.OrdersCSV)
+	if err != nil {
+		t.Fatalf("load replay output: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("replay sent %d orders, want %d", len(got), len(want))
+	}
+	for i := range want {
+		// Replayed orders are sent now, so only the timestamps differ
+		got[i].Time, want[i].Time = time.Time{}, time.Time{}
+		if got[i] != want[i] {
+			t.Errorf("order %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
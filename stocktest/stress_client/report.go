@@ -0,0 +1,625 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// LatencySummary describes one latency distribution in milliseconds
+type LatencySummary struct {
+	Count int64   `json:"count"`
+	MinMs float64 `json:"min_ms"`
+	AvgMs float64 `json:"avg_ms"`
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// Report is the final result of a run. Its JSON form (-output-json) is the
+// stable machine-readable schema; Log prints the same values for humans.
+type Report struct {
+	DurationSec     float64 `json:"duration_sec"`
+	Interrupted     bool    `json:"interrupted"`
+	UsersCreated    int64   `json:"users_created"`
+	UsersLoggedIn   int64   `json:"users_logged_in"`
+	UsersExisting   int64   `json:"users_existing"`
+	UsersCompleted  int64   `json:"users_completed"`
+	OrdersSubmitted int64   `json:"orders_submitted"`
+	OrdersAccepted  int64   `json:"orders_accepted"`
+	AcceptedPct     float64 `json:"accepted_pct"`
+	ThroughputOPS   float64 `json:"throughput_orders_per_sec"`
+	Errors          int64   `json:"errors"`
+	// Errors by category, e.g. "io_timeout" or "signup"
+	ErrorCategories map[string]int64 `json:"error_categories,omitempty"`
+	// Orders still awaiting a response when the shutdown drain timed out
+	AbandonedOrders int64 `json:"abandoned_orders"`
+	// Engine-closed connections that were redialed and the order resent
+	Reconnects int64 `json:"reconnects"`
+	// Connections redialed and reauthenticated to replace one discarded
+	// after an error, and replacements that failed to connect
+	Reauths        int64 `json:"reauths"`
+	ReauthFailures int64 `json:"reauth_failures"`
+	// Frames the engine pushed unsolicited, such as execution reports
+	PushMessages int64 `json:"push_messages"`
+	// Signups and logins resent after the frontend answered 429
+	RateLimitedRetries int64 `json:"rate_limited_retries"`
+	// Engine TLS handshakes run in full and resumed from the session cache
+	TLSFullHandshakes int64 `json:"tls_full_handshakes"`
+	TLSResumed        int64 `json:"tls_resumed_handshakes"`
+	// Why -max-error-rate stopped the run, if it did
+	Aborted string `json:"aborted,omitempty"`
+
+	SignupLatency LatencySummary `json:"signup_latency"`
+	LoginLatency  LatencySummary `json:"login_latency"`
+	// Engine dial, TLS handshake and authentication
+	ConnectLatency LatencySummary `json:"connect_latency"`
+
+	OrderLatency LatencySummary `json:"order_latency"`
+	// Service-time latency, present with -correct-omission
+	UncorrectedOrderLatency *LatencySummary `json:"uncorrected_order_latency,omitempty"`
+	// Portfolio read latency, present with -query-pct
+	QueryLatency *LatencySummary `json:"query_latency,omitempty"`
+	// Marketable orders from submit to their first fill, present when the
+	// engine pushed execution reports for them
+	SubmitToFillLatency *LatencySummary `json:"submit_to_fill_latency,omitempty"`
+	// Order latency distribution, present with -hist-buckets
+	LatencyBuckets []LatencyBucket `json:"latency_buckets,omitempty"`
+	// Per-engine breakdown, present when orders went to more than one engine
+	Engines map[string]EngineReport `json:"engines,omitempty"`
+	// Concurrency -autoscale settled on, present with -autoscale
+	AutoscaleConcurrency int `json:"autoscale_concurrency,omitempty"`
+	// Orders per skew, keyed by offset (e.g. "-5s"), present with -timestamp-skew
+	TimestampSkew map[string]SkewReport `json:"timestamp_skew,omitempty"`
+	// Rejections by normalized engine message, e.g. "insufficient buying power"
+	RejectReasons map[string]int64 `json:"reject_reasons,omitempty"`
+	// Book depth check, present with -verify-depth
+	DepthVerification *DepthReport `json:"depth_verification,omitempty"`
+	// Spread of orders over connections, present with -conns-per-user
+	ConnOrders *ConnOrdersReport `json:"conn_orders,omitempty"`
+	// Engine clock offsets by address, present with -protocol-version 3
+	// when the engine sent server times
+	ClockSkew map[string]ClockSkewReport `json:"clock_skew,omitempty"`
+	// Connection keepalives
+	Heartbeats HeartbeatReport `json:"heartbeats"`
+	// Highest client CPU utilization seen, and whether it reached
+	// -cpu-threshold, in which case latencies may be client-limited
+	PeakClientCPUPct float64 `json:"peak_client_cpu_pct"`
+	CPUSaturated     bool    `json:"client_cpu_saturated"`
+	// Per-symbol breakdown
+	Symbols map[string]SymbolReport `json:"symbols,omitempty"`
+	// Orders sent across several writes, present with -fragment-pct
+	Fragmentation *FragmentationReport `json:"fragmentation,omitempty"`
+	// Order resends, present with -max-retries
+	Retries *RetryReport `json:"retries,omitempty"`
+	// Present with -cancel-pct and -modify-pct
+	Cancels  *AckReport `json:"cancels,omitempty"`
+	Modifies *AckReport `json:"modifies,omitempty"`
+	// Present with -query-pct; their latency is QueryLatency
+	PortfolioQueries *QueryReport `json:"portfolio_queries,omitempty"`
+	// Top-of-book samples, present with -verify-book
+	BookQueries *BookQueryReport `json:"book_queries,omitempty"`
+	// Present with -cross-accounts
+	CrossAccount *CrossAccountReport `json:"cross_account,omitempty"`
+	// Rejections by engine reject code, present with -protocol-version 2 or
+	// later (empty when none were reported)
+	RejectCodes map[uint16]int64 `json:"reject_codes,omitempty"`
+}
+
+// HeartbeatReport counts heartbeats and connections that missed too many
+type HeartbeatReport struct {
+	Sent           int64 `json:"sent"`
+	Acked          int64 `json:"acked"`
+	Missed         int64 `json:"missed"`
+	UnhealthyConns int64 `json:"unhealthy_connections"`
+}
+
+// SymbolReport is one symbol's share of the orders
+type SymbolReport struct {
+	OrdersSubmitted int64   `json:"orders_submitted"`
+	OrdersAccepted  int64   `json:"orders_accepted"`
+	AcceptedPct     float64 `json:"accepted_pct"`
+	AvgMs           float64 `json:"avg_ms"`
+	P99Ms           float64 `json:"p99_ms"`
+}
+
+// FragmentationReport is how the engine answered order frames delivered
+// across several TCP writes
+type FragmentationReport struct {
+	Responded int64   `json:"responded"`
+	Accepted  int64   `json:"accepted"`
+	Errors    int64   `json:"errors"`
+	AvgMs     float64 `json:"avg_ms"`
+}
+
+// RetryReport counts order resends after failures
+type RetryReport struct {
+	Attempts   int64 `json:"attempts"`
+	Succeeded  int64 `json:"succeeded"`
+	Failed     int64 `json:"failed"`
+	MaxRetries int   `json:"max_retries"`
+}
+
+// AckReport counts the engine's acks of cancels or modifies
+type AckReport struct {
+	Acknowledged int64   `json:"acknowledged"`
+	Accepted     int64   `json:"accepted"`
+	AcceptedPct  float64 `json:"accepted_pct"`
+	// Not sent because one had gone unanswered on the same connection
+	Skipped int64 `json:"skipped,omitempty"`
+}
+
+// QueryReport counts portfolio reads
+type QueryReport struct {
+	Sent   int64 `json:"sent"`
+	Failed int64 `json:"failed"`
+}
+
+// BookQueryReport counts top-of-book samples
+type BookQueryReport struct {
+	Sent  int64 `json:"sent"`
+	Empty int64 `json:"empty"`
+	// Not sent because the engine left an earlier query unanswered
+	Skipped int64 `json:"skipped,omitempty"`
+}
+
+// CrossAccountReport is the cross-account fill and settlement outcome
+type CrossAccountReport struct {
+	PairsSubmitted      int64 `json:"pairs_submitted"`
+	PairsAccepted       int64 `json:"pairs_accepted"`
+	PositionsVerified   int64 `json:"positions_verified"`
+	PositionsMismatched int64 `json:"positions_mismatched"`
+	VerifyErrors        int64 `json:"verify_errors"`
+}
+
+// ClockSkewReport is one engine's estimated clock offset: its clock runs
+// OffsetMs ahead of the client's, give or take ErrorMs (half the best
+// round trip)
+type ClockSkewReport struct {
+	OffsetMs float64 `json:"offset_ms"`
+	ErrorMs  float64 `json:"error_ms"`
+	Samples  int     `json:"samples"`
+}
+
+// ConnOrdersReport is how evenly orders spread over users' connections
+type ConnOrdersReport struct {
+	Connections int     `json:"connections"`
+	Min         int64   `json:"min"`
+	Max         int64   `json:"max"`
+	Mean        float64 `json:"mean"`
+}
+
+// EngineReport is one engine's share of the orders
+type EngineReport struct {
+	OrdersSubmitted int64   `json:"orders_submitted"`
+	OrdersAccepted  int64   `json:"orders_accepted"`
+	ThroughputOPS   float64 `json:"throughput_orders_per_sec"`
+}
+
+// SkewReport is how the engine answered orders sent with one timestamp skew
+type SkewReport struct {
+	OrdersSubmitted int64   `json:"orders_submitted"`
+	OrdersAccepted  int64   `json:"orders_accepted"`
+	AcceptedPct     float64 `json:"accepted_pct"`
+}
+
+// DepthReport is the outcome of -verify-depth. Discrepancies are accepted
+// orders the book does not hold; Error is set when the check could not
+// finish, which is a load problem rather than a correctness one.
+type DepthReport struct {
+	Symbol          string   `json:"symbol"`
+	OrdersSubmitted int64    `json:"orders_submitted"`
+	OrdersAccepted  int64    `json:"orders_accepted"`
+	LevelsExpected  int      `json:"levels_expected"`
+	LevelsFound     int      `json:"levels_found"`
+	Discrepancies   []string `json:"discrepancies,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e6
+}
+
+// summarizeSlice summarizes a full latency slice
+func summarizeSlice(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	var r latencyReservoir
+	r.capacity = len(latencies)
+	for _, lat := range latencies {
+		r.Record(lat)
+	}
+	return summarizeRecorder(&r)
+}
+
+// summarizeRecorder summarizes a latency recorder
+func summarizeRecorder(r LatencyRecorder) LatencySummary {
+	pcts := r.Percentiles(0.50, 0.95, 0.99)
+	return LatencySummary{
+		Count: r.Count(),
+		MinMs: toMs(r.Min()),
+		AvgMs: toMs(r.Mean()),
+		P50Ms: toMs(pcts[0]),
+		P95Ms: toMs(pcts[1]),
+		P99Ms: toMs(pcts[2]),
+		MaxMs: toMs(r.Max()),
+	}
+}
+
+// buildReport assembles the final report from s, a snapshot whose latency
+// recorders and breakdowns have been merged from the stats shards.
+func buildReport(s *StressStats, config StressConfig, duration time.Duration, interrupted bool) Report {
+	r := Report{
+		DurationSec:     duration.Seconds(),
+		Interrupted:     interrupted,
+		UsersCreated:    atomic.LoadInt64(&s.UsersCreated),
+		UsersLoggedIn:   atomic.LoadInt64(&s.UsersLoggedIn),
+		UsersExisting:   atomic.LoadInt64(&s.UsersExisting),
+		UsersCompleted:  atomic.LoadInt64(&s.UsersCompleted),
+		OrdersSubmitted: atomic.LoadInt64(&s.OrdersSubmitted),
+		OrdersAccepted:  atomic.LoadInt64(&s.OrdersAccepted),
+		Errors:          atomic.LoadInt64(&s.Errors),
+		Reconnects:      atomic.LoadInt64(&s.Reconnects),
+		PushMessages:    atomic.LoadInt64(&s.PushMessages),
+		ErrorCategories: maps.Clone(s.ErrorCategories),
+		RejectReasons:   maps.Clone(s.RejectReasons),
+		SignupLatency:   summarizeSlice(s.SignupLatencies),
+		LoginLatency:    summarizeSlice(s.LoginLatencies),
+		ConnectLatency:  summarizeSlice(s.ConnectLatencies),
+		OrderLatency:    summarizeRecorder(s.OrderLatencies),
+	}
+	r.TLSFullHandshakes = atomic.LoadInt64(&s.TLSFullHandshakes)
+	r.RateLimitedRetries = atomic.LoadInt64(&s.RateLimitedRetries)
+	r.Reauths = atomic.LoadInt64(&s.Reauths)
+	r.ReauthFailures = atomic.LoadInt64(&s.ReauthFailures)
+	r.TLSResumed = atomic.LoadInt64(&s.TLSResumed)
+	if r.OrdersSubmitted > 0 {
+		r.AcceptedPct = float64(r.OrdersAccepted) / float64(r.OrdersSubmitted) * 100
+	}
+	if duration > 0 {
+		r.ThroughputOPS = float64(r.OrdersSubmitted) / duration.Seconds()
+	}
+	if config.CorrectOmission {
+		uncorrected := summarizeRecorder(s.UncorrectedLatencies)
+		r.UncorrectedOrderLatency = &uncorrected
+	}
+	if config.QueryPct > 0 {
+		query := summarizeRecorder(s.QueryLatencies)
+		r.QueryLatency = &query
+	}
+	if s.SubmitToFillLatencies != nil && s.SubmitToFillLatencies.Count() > 0 {
+		fill := summarizeRecorder(s.SubmitToFillLatencies)
+		r.SubmitToFillLatency = &fill
+	}
+	if bounds, _ := parseHistBuckets(config.HistBuckets); len(bounds) > 0 {
+		r.LatencyBuckets = bucketLatencies(s.OrderLatencies, bounds)
+	}
+	if len(s.Engines) > 1 {
+		r.Engines = make(map[string]EngineReport, len(s.Engines))
+		for addr, es := range s.Engines {
+			er := EngineReport{OrdersSubmitted: es.OrdersSubmitted, OrdersAccepted: es.OrdersAccepted}
+			if duration > 0 {
+				er.ThroughputOPS = float64(es.OrdersSubmitted) / duration.Seconds()
+			}
+			r.Engines[addr] = er
+		}
+	}
+	if protocolVersion >= ProtocolVersionServerTime {
+		for addr, e := range clockSkews.Estimates() {
+			if r.ClockSkew == nil {
+				r.ClockSkew = make(map[string]ClockSkewReport)
+			}
+			r.ClockSkew[addr] = ClockSkewReport{OffsetMs: toMs(e.Best.Offset), ErrorMs: toMs(e.Best.RTT / 2), Samples: e.Samples}
+		}
+	}
+	if config.ConnsPerUser > 1 && len(s.ConnOrders) > 0 {
+		c := &ConnOrdersReport{Connections: len(s.ConnOrders), Min: slices.Min(s.ConnOrders), Max: slices.Max(s.ConnOrders)}
+		var total int64
+		for _, n := range s.ConnOrders {
+			total += n
+		}
+		c.Mean = float64(total) / float64(len(s.ConnOrders))
+		r.ConnOrders = c
+	}
+	if len(s.Skews) > 0 {
+		r.TimestampSkew = make(map[string]SkewReport, len(s.Skews))
+		for skew, ss := range s.Skews {
+			sr := SkewReport{OrdersSubmitted: ss.OrdersSubmitted, OrdersAccepted: ss.OrdersAccepted}
+			if ss.OrdersSubmitted > 0 {
+				sr.AcceptedPct = float64(ss.OrdersAccepted) / float64(ss.OrdersSubmitted) * 100
+			}
+			r.TimestampSkew[skew.String()] = sr
+		}
+	}
+	r.Heartbeats = HeartbeatReport{
+		Sent:           atomic.LoadInt64(&s.HeartbeatsSent),
+		Acked:          atomic.LoadInt64(&s.HeartbeatAcks),
+		Missed:         atomic.LoadInt64(&s.HeartbeatsMissed),
+		UnhealthyConns: atomic.LoadInt64(&s.UnhealthyConns),
+	}
+	r.PeakClientCPUPct = peakCPU()
+	r.CPUSaturated = r.PeakClientCPUPct >= config.CPUThreshold
+	for symbol, ss := range s.Symbols {
+		if r.Symbols == nil {
+			r.Symbols = make(map[string]SymbolReport, len(s.Symbols))
+		}
+		sr := SymbolReport{OrdersSubmitted: ss.OrdersSubmitted, OrdersAccepted: ss.OrdersAccepted}
+		if ss.OrdersSubmitted > 0 {
+			sr.AcceptedPct = float64(ss.OrdersAccepted) / float64(ss.OrdersSubmitted) * 100
+		}
+		if ss.Latencies != nil {
+			sr.AvgMs = toMs(ss.Latencies.Mean())
+			sr.P99Ms = toMs(ss.Latencies.Percentile(0.99))
+		}
+		r.Symbols[symbol] = sr
+	}
+	if config.FragmentPct > 0 {
+		f := &FragmentationReport{
+			Responded: atomic.LoadInt64(&s.FragmentedSubmitted),
+			Accepted:  atomic.LoadInt64(&s.FragmentedAccepted),
+			Errors:    atomic.LoadInt64(&s.FragmentedErrors),
+		}
+		if s.FragmentedLatencies != nil {
+			f.AvgMs = toMs(s.FragmentedLatencies.Mean())
+		}
+		r.Fragmentation = f
+	}
+	if config.MaxRetries > 0 {
+		r.Retries = &RetryReport{
+			Attempts:   atomic.LoadInt64(&s.RetryAttempts),
+			Succeeded:  atomic.LoadInt64(&s.RetriedSucceeded),
+			Failed:     atomic.LoadInt64(&s.RetriedFailed),
+			MaxRetries: config.MaxRetries,
+		}
+	}
+	if config.CancelPct > 0 {
+		r.Cancels = newAckReport(atomic.LoadInt64(&s.CancelsSubmitted), atomic.LoadInt64(&s.CancelsAccepted))
+		r.Cancels.Skipped = atomic.LoadInt64(&s.CancelsSkipped)
+	}
+	if config.ModifyPct > 0 {
+		r.Modifies = newAckReport(atomic.LoadInt64(&s.ModifiesSubmitted), atomic.LoadInt64(&s.ModifiesAccepted))
+		r.Modifies.Skipped = atomic.LoadInt64(&s.ModifiesSkipped)
+	}
+	if config.QueryPct > 0 {
+		r.PortfolioQueries = &QueryReport{Sent: atomic.LoadInt64(&s.QueriesSubmitted), Failed: atomic.LoadInt64(&s.QueryErrors)}
+	}
+	if config.VerifyBook > 0 {
+		r.BookQueries = &BookQueryReport{
+			Sent:    atomic.LoadInt64(&s.BookQueries),
+			Empty:   atomic.LoadInt64(&s.BookEmpty),
+			Skipped: atomic.LoadInt64(&s.BookQueriesSkipped),
+		}
+	}
+	if config.CrossAccounts >= 2 {
+		r.CrossAccount = &CrossAccountReport{
+			PairsSubmitted:      atomic.LoadInt64(&s.CrossPairsSubmitted),
+			PairsAccepted:       atomic.LoadInt64(&s.CrossPairsAccepted),
+			PositionsVerified:   atomic.LoadInt64(&s.CrossPositionsVerified),
+			PositionsMismatched: atomic.LoadInt64(&s.CrossPositionsMismatch),
+			VerifyErrors:        atomic.LoadInt64(&s.CrossVerifyErrors),
+		}
+	}
+	if protocolVersion >= ProtocolVersionRejectCodes {
+		r.RejectCodes = make(map[uint16]int64, len(s.RejectCodes))
+		maps.Copy(r.RejectCodes, s.RejectCodes)
+	}
+	return r
+}
+
+// newAckReport summarizes acknowledged cancels or modifies
+func newAckReport(acknowledged, accepted int64) *AckReport {
+	a := &AckReport{Acknowledged: acknowledged, Accepted: accepted}
+	if acknowledged > 0 {
+		a.AcceptedPct = float64(accepted) / float64(acknowledged) * 100
+	}
+	return a
+}
+
+// Log prints the report
+func (r Report) Log() {
+	if r.Aborted != "" {
+		log.Printf("❌ Run aborted: %s", r.Aborted)
+	}
+	log.Printf("Test completed in %v", time.Duration(r.DurationSec*float64(time.Second)).Round(time.Millisecond))
+	log.Printf("Users: %d created, %d logged in, %d completed", r.UsersCreated, r.UsersLoggedIn, r.UsersCompleted)
+	if r.UsersExisting > 0 {
+		log.Printf("Existing Users: %d signups found the account already registered and logged in instead", r.UsersExisting)
+	}
+	log.Printf("Orders: %d submitted, %d accepted (%.1f%%)", r.OrdersSubmitted, r.OrdersAccepted, r.AcceptedPct)
+	log.Printf("Throughput: %.1f orders/sec", r.ThroughputOPS)
+	log.Printf("Errors: %d", r.Errors)
+	logErrorCategories(r.ErrorCategories)
+	if r.Reconnects > 0 {
+		log.Printf("Reconnects: %d connections closed by the engine were redialed", r.Reconnects)
+	}
+	if conns := r.ConnectLatency.Count; conns > 0 {
+		log.Printf("Connection Reuse: %.1f orders per connection over %d connections, %d replaced after errors, %d replacements failed",
+			float64(r.OrdersSubmitted)/float64(conns), conns, r.Reauths, r.ReauthFailures)
+	}
+	if handshakes := r.TLSFullHandshakes + r.TLSResumed; handshakes > 0 {
+		log.Printf("TLS Handshakes: %d full, %d resumed (%.1f%%)", r.TLSFullHandshakes, r.TLSResumed,
+			float64(r.TLSResumed)/float64(handshakes)*100)
+	}
+	for _, addr := range slices.Sorted(maps.Keys(r.ClockSkew)) {
+		c := r.ClockSkew[addr]
+		log.Printf("Clock Skew: engine %s clock is %.3fms ahead of the client (±%.3fms, %d round trips)",
+			addr, c.OffsetMs, c.ErrorMs, c.Samples)
+	}
+	if c := r.ConnOrders; c != nil {
+		log.Printf("Connections: %d, orders per connection min %d / avg %.1f / max %d",
+			c.Connections, c.Min, c.Mean, c.Max)
+	}
+	if r.RateLimitedRetries > 0 {
+		log.Printf("Rate-Limited Retries: %d signups and logins resent after a frontend 429", r.RateLimitedRetries)
+	}
+	if r.PushMessages > 0 {
+		log.Printf("Push Messages: %d frames pushed by the engine between responses", r.PushMessages)
+	}
+	if r.AbandonedOrders > 0 {
+		log.Printf("Abandoned Orders: %d still in flight when the drain timeout expired", r.AbandonedOrders)
+	}
+	log.Printf("Average Latencies: Signup=%.2fms, Login=%.2fms, Order=%.2fms",
+		r.SignupLatency.AvgMs, r.LoginLatency.AvgMs, r.OrderLatency.AvgMs)
+	log.Printf("Connect Latency: %d connections, Min=%.2fms, Avg=%.2fms, p99=%.2fms",
+		r.ConnectLatency.Count, r.ConnectLatency.MinMs, r.ConnectLatency.AvgMs, r.ConnectLatency.P99Ms)
+	log.Printf("Order Latency: Min=%.2fms, p50=%.2fms, p95=%.2fms, p99=%.2fms, Max=%.2fms",
+		r.OrderLatency.MinMs, r.OrderLatency.P50Ms, r.OrderLatency.P95Ms, r.OrderLatency.P99Ms, r.OrderLatency.MaxMs)
+	if u := r.UncorrectedOrderLatency; u != nil {
+		log.Printf("Uncorrected (service time) Percentiles: p50=%.2fms, p95=%.2fms, p99=%.2fms",
+			u.P50Ms, u.P95Ms, u.P99Ms)
+	}
+	if f := r.SubmitToFillLatency; f != nil {
+		log.Printf("Submit-to-Fill Latency: %d fills, p50=%.2fms, p95=%.2fms, p99=%.2fms, Max=%.2fms",
+			f.Count, f.P50Ms, f.P95Ms, f.P99Ms, f.MaxMs)
+	}
+	if len(r.LatencyBuckets) > 0 {
+		logLatencyBuckets(r.LatencyBuckets)
+	}
+	if r.AutoscaleConcurrency > 0 {
+		log.Printf("Autoscale: settled on %d concurrent users", r.AutoscaleConcurrency)
+	}
+	if d := r.DepthVerification; d != nil {
+		switch {
+		case d.Error != "":
+			log.Printf("Depth Verification: incomplete on %s after %d orders: %s", d.Symbol, d.OrdersSubmitted, d.Error)
+		case len(d.Discrepancies) > 0:
+			log.Printf("❌ Depth Verification: %d of %d accepted orders on %s not in the book (%d levels found)",
+				len(d.Discrepancies), d.OrdersAccepted, d.Symbol, d.LevelsFound)
+			for _, msg := range d.Discrepancies {
+				log.Printf("  %s", msg)
+			}
+		default:
+			log.Printf("Depth Verification: all %d accepted orders on %s found in the book (%d levels)",
+				d.OrdersAccepted, d.Symbol, d.LevelsFound)
+		}
+	}
+	for _, addr := range slices.Sorted(maps.Keys(r.Engines)) {
+		e := r.Engines[addr]
+		log.Printf("Engine %s: %d orders (%.1f orders/sec), %d accepted",
+			addr, e.OrdersSubmitted, e.ThroughputOPS, e.OrdersAccepted)
+	}
+	h := r.Heartbeats
+	log.Printf("Heartbeats: %d sent, %d acked, %d missed, %d unhealthy connections", h.Sent, h.Acked, h.Missed, h.UnhealthyConns)
+	log.Printf("Peak Client CPU: %.1f%%", r.PeakClientCPUPct)
+	if r.CPUSaturated {
+		log.Printf("⚠️  Client CPU reached the saturation threshold; latency results may be client-limited")
+	}
+	logSymbols(r.Symbols)
+	if f := r.Fragmentation; f != nil {
+		logFragmentation(f)
+	}
+	if t := r.Retries; t != nil {
+		log.Printf("Retries: %d attempts, %d orders succeeded after retrying, %d failed after %d retries",
+			t.Attempts, t.Succeeded, t.Failed, t.MaxRetries)
+	}
+	if c := r.Cancels; c != nil {
+		log.Printf("Cancels: %d acknowledged, %d accepted (%.1f%%)", c.Acknowledged, c.Accepted, c.AcceptedPct)
+		if c.Skipped > 0 {
+			log.Printf("Cancels skipped after one went unanswered on the connection: %d", c.Skipped)
+		}
+	}
+	if m := r.Modifies; m != nil {
+		log.Printf("Modifies: %d acknowledged, %d accepted (%.1f%%)", m.Acknowledged, m.Accepted, m.AcceptedPct)
+		if m.Skipped > 0 {
+			log.Printf("Modifies skipped after one went unanswered on the connection: %d", m.Skipped)
+		}
+	}
+	if q := r.PortfolioQueries; q != nil {
+		var latency LatencySummary
+		if r.QueryLatency != nil {
+			latency = *r.QueryLatency
+		}
+		log.Printf("Portfolio Queries: %d sent, %d failed, latency avg=%.2fms p99=%.2fms",
+			q.Sent, q.Failed, latency.AvgMs, latency.P99Ms)
+	}
+	logTimestampSkew(r.TimestampSkew)
+	if b := r.BookQueries; b != nil {
+		log.Printf("Book Queries: %d sent, %d returned an empty book", b.Sent, b.Empty)
+		if b.Skipped > 0 {
+			log.Printf("Book queries skipped after one went unanswered: %d", b.Skipped)
+		}
+	}
+	if c := r.CrossAccount; c != nil {
+		logCrossAccount(c)
+	}
+	if r.RejectCodes != nil {
+		logRejectCodes(r.RejectCodes)
+	}
+	logRejectReasons(r.RejectReasons)
+}
+
+// logSymbols prints a per-symbol order table sorted by symbol
+func logSymbols(symbols map[string]SymbolReport) {
+	if len(symbols) == 0 {
+		return
+	}
+	log.Printf("Per-Symbol Breakdown:")
+	log.Printf("  %-8s %10s %10s %8s %10s %10s", "SYMBOL", "SUBMITTED", "ACCEPTED", "ACC%", "AVG(ms)", "P99(ms)")
+	for _, name := range slices.Sorted(maps.Keys(symbols)) {
+		s := symbols[name]
+		log.Printf("  %-8s %10d %10d %7.1f%% %10.2f %10.2f", name, s.OrdersSubmitted, s.OrdersAccepted, s.AcceptedPct, s.AvgMs, s.P99Ms)
+	}
+}
+
+// logFragmentation summarizes how the engine handled order frames that
+// were delivered across multiple TCP writes.
+func logFragmentation(f *FragmentationReport) {
+	log.Printf("Fragmented Orders: %d responded, %d accepted, %d errors, Avg Latency=%.2fms",
+		f.Responded, f.Accepted, f.Errors, f.AvgMs)
+	switch {
+	case f.Responded == 0 && f.Errors == 0:
+		log.Printf("Frame Reassembly: no fragmented orders were sent")
+	case f.Errors == 0:
+		log.Printf("Frame Reassembly: OK (every fragmented frame got a well-formed response)")
+	default:
+		log.Printf("Frame Reassembly: FAILED (%d fragmented frames were not answered correctly)", f.Errors)
+	}
+}
+
+// logRejectCodes prints rejection counts per engine reject code, most
+// frequent first.
+func logRejectCodes(codes map[uint16]int64) {
+	if len(codes) == 0 {
+		log.Printf("Reject Codes: none reported")
+		return
+	}
+	keys := slices.SortedFunc(maps.Keys(codes), func(a, b uint16) int {
+		if c := cmp.Compare(codes[b], codes[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	log.Printf("Reject Codes:")
+	for _, code := range keys {
+		log.Printf("  %5d: %d", code, codes[code])
+	}
+}
+
+// writeJSONReport writes r to path as a single JSON object
+func writeJSONReport(path string, r Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
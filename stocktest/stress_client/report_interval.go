@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReportIntervalAdaptive reports every second at first and doubles the gap
+// after each report, up to adaptiveReportMax
+const ReportIntervalAdaptive = "adaptive"
+
+const (
+	defaultReportInterval = 5 * time.Second
+	adaptiveReportFirst   = time.Second
+	adaptiveReportMax     = time.Minute
+)
+
+// reportSchedule returns the gap between live status n-1 and n, where
+// status 0 follows the start of the run
+type reportSchedule func(n int) time.Duration
+
+// parseReportInterval parses -report-interval: a fixed duration, "adaptive",
+// or "0" to turn live status off, which returns nil. An empty spec uses
+// defaultReportInterval.
+func parseReportInterval(spec string) (reportSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == ReportIntervalAdaptive {
+		return adaptiveReportSchedule, nil
+	}
+	d := defaultReportInterval
+	var err error
+	if spec != "" {
+		d, err = time.ParseDuration(spec)
+	}
+	if err != nil || d < 0 {
+		return nil, fmt.Errorf("invalid report-interval %q (want a duration, %s or 0)", spec, ReportIntervalAdaptive)
+	}
+	if d == 0 {
+		return nil, nil
+	}
+	return func(int) time.Duration { return d }, nil
+}
+
+// adaptiveReportSchedule doubles the gap after every report, so the number
+// of reports grows with the log of the elapsed time until the gap reaches
+// adaptiveReportMax: statuses land at 1s, 3s, 7s, 15s, 31s, 63s, then every
+// minute.
+func adaptiveReportSchedule(n int) time.Duration {
+	d := adaptiveReportFirst
+	for i := 0; i < n && d < adaptiveReportMax; i++ {
+		d *= 2
+	}
+	return min(d, adaptiveReportMax)
+}
+
+// runReportSchedule calls report at each time schedule yields, measured
+// from start, until ctx is cancelled. Each time is an offset from start
+// rather than from the previous report, so slow reports do not push the
+// schedule back.
+func runReportSchedule(ctx context.Context, start time.Time, schedule reportSchedule, report func()) {
+	due := start.Add(schedule(0))
+	timer := time.NewTimer(time.Until(due))
+	defer timer.Stop()
+
+	for n := 1; ; n++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			report()
+			due = due.Add(schedule(n))
+			timer.Reset(time.Until(due))
+		}
+	}
+}
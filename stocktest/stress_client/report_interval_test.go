@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveReportSchedule(t *testing.T) {
+	schedule, err := parseReportInterval(ReportIntervalAdaptive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Duration{1, 3, 7, 15, 31, 63, 123, 183, 243}
+	var at time.Duration
+	for n, w := range want {
+		at += schedule(n)
+		if at != w*time.Second {
+			t.Errorf("status %d at %v, want %v", n, at, w*time.Second)
+		}
+	}
+	if got := schedule(100); got != adaptiveReportMax {
+		t.Errorf("gap after many reports = %v, want %v", got, adaptiveReportMax)
+	}
+
+	// Run the schedule at 1/100 scale: each status lands at or after its
+	// offset from the start, never drifting behind by the report's own cost
+	start := time.Now()
+	var ticks []time.Duration
+	ctx, cancel := context.WithCancel(context.Background())
+	runReportSchedule(ctx, start, func(n int) time.Duration { return schedule(n) / 100 }, func() {
+		ticks = append(ticks, time.Since(start))
+		time.Sleep(5 * time.Millisecond)
+		if len(ticks) == 4 {
+			cancel()
+		}
+	})
+	for i, got := range ticks {
+		w := want[i] * 10 * time.Millisecond
+		if got < w || got > w+50*time.Millisecond {
+			t.Errorf("tick %d at %v, want ~%v", i, got, w)
+		}
+	}
+
+	if s, err := parseReportInterval("0"); err != nil || s != nil {
+		t.Errorf("parseReportInterval(0) = %v, %v; want disabled", s, err)
+	}
+	if s, _ := parseReportInterval("2s"); s == nil || s(0) != 2*time.Second || s(9) != 2*time.Second {
+		t.Error("fixed report interval not constant")
+	}
+	if s, _ := parseReportInterval(""); s == nil || s(0) != defaultReportInterval {
+		t.Error("empty report interval does not use the default")
+	}
+	for _, spec := range []string{"-1s", "often"} {
+		if _, err := parseReportInterval(spec); err == nil {
+			t.Errorf("parseReportInterval(%q) accepted", spec)
+		}
+	}
+}
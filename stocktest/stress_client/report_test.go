@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReportCarriesEverySection(t *testing.T) {
+	resetGlobals(t)
+	defer func(v int) { protocolVersion = v }(protocolVersion)
+	protocolVersion = ProtocolVersionRejectCodes
+
+	stats.shard(1).recordOrder(orderOutcome{Symbol: "AAPL", Latency: time.Millisecond, Accepted: true})
+	stats.shard(1).recordOrder(orderOutcome{Symbol: "MSFT", Latency: time.Millisecond, RejectCode: 3, HasRejectCode: true})
+	atomic.AddInt64(&stats.HeartbeatsSent, 4)
+	atomic.AddInt64(&stats.CancelsSubmitted, 2)
+	atomic.AddInt64(&stats.CancelsAccepted, 1)
+
+	config := StressConfig{
+		FragmentPct:   10,
+		MaxRetries:    2,
+		CancelPct:     10,
+		ModifyPct:     10,
+		QueryPct:      10,
+		VerifyBook:    time.Second,
+		CrossAccounts: 2,
+		CPUThreshold:  90,
+	}
+	snap := stats.snapshot()
+	r := buildReport(&snap, config, time.Second, false)
+
+	// Every section reaches -output-json
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"heartbeats", "peak_client_cpu_pct", "symbols", "fragmentation", "retries",
+		"cancels", "modifies", "portfolio_queries", "book_queries", "cross_account", "reject_codes"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("JSON report has no %q", key)
+		}
+	}
+	if r.Heartbeats.Sent != 4 || r.Cancels.Acknowledged != 2 || r.Cancels.AcceptedPct != 50 || r.RejectCodes[3] != 1 {
+		t.Errorf("heartbeats %+v, cancels %+v, reject codes %v", r.Heartbeats, r.Cancels, r.RejectCodes)
+	}
+	if s := r.Symbols["MSFT"]; s.OrdersSubmitted != 1 || s.OrdersAccepted != 0 {
+		t.Errorf("MSFT = %+v, want 1 submitted, none accepted", s)
+	}
+
+	// and is printed from the report
+	var out strings.Builder
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+	r.Log()
+	for _, line := range []string{"Heartbeats: 4 sent", "Peak Client CPU", "Per-Symbol Breakdown", "Fragmented Orders",
+		"Retries:", "Cancels: 2 acknowledged", "Modifies:", "Portfolio Queries", "Book Queries", "Cross-Account:", "Reject Codes:"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("report log has no %q", line)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// Retry backoff: base * 2^attempt, capped, with jitter over the upper half
+const (
+	retryBaseDelay = 10 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+// retryBackoff returns the jittered delay before retry attempt (0-based)
+func retryBackoff(attempt int) time.Duration {
+	d := retryMaxDelay
+	if attempt < 16 {
+		d = min(retryBaseDelay<<attempt, retryMaxDelay)
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// withRetry runs exchange on a pooled connection, retrying up to maxRetries
+// times with exponential backoff. A failed connection is discarded, so each
+// retry runs on a freshly dialed and authenticated one. If the last failure
+// was the engine closing the connection, one more attempt is made on a new
+// connection without backoff and counted as a reconnect.
+func withRetry(ctx context.Context, pool *ConnPool, maxRetries int, exchange func(conn net.Conn) error) error {
+	reconnected := false
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		conn, err := pool.Get(ctx)
+		if err == nil {
+			err = runExchange(pool, conn, exchange)
+		}
+
+		if err == nil {
+			if attempt > 0 {
+				atomic.AddInt64(&stats.RetriedSucceeded, 1)
+			}
+			return nil
+		}
+		// Resending cannot fix an order rejected before it was sent
+		if errors.Is(err, protocol.ErrInvalidOrder) {
+			return err
+		}
+		// A retry skipped because an earlier attempt went unanswered leaves
+		// that attempt's failure standing
+		if errors.Is(err, errUnanswered) {
+			if attempt > 0 {
+				atomic.AddInt64(&stats.RetriedFailed, 1)
+				return lastErr
+			}
+			return err
+		}
+		if attempt >= maxRetries || ctx.Err() != nil {
+			// A connection the engine closed (for example for idleness) is
+			// redialed and reauthenticated once even with retries used up
+			if !reconnected && ctx.Err() == nil && isConnClosed(err) {
+				reconnected = true
+				atomic.AddInt64(&stats.Reconnects, 1)
+				attempt--
+				continue
+			}
+			if attempt > 0 {
+				atomic.AddInt64(&stats.RetriedFailed, 1)
+			}
+			return err
+		}
+
+		lastErr = err
+		atomic.AddInt64(&stats.RetryAttempts, 1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryBackoff(attempt)):
+		}
+	}
+}
+
+// runExchange runs exchange on conn and returns conn to pool. If exchange
+// panics the stream is in an unknown state, so conn is discarded, freeing
+// its slot, before the panic carries on to the worker's recover. An exchange
+// that handed conn back early (see heldConn) leaves it in the pool whatever
+// happens next: a connection that has failed is replaced by the pool's next
+// Get.
+func runExchange(pool *ConnPool, conn net.Conn, exchange func(conn net.Conn) error) error {
+	atomic.AddInt64(&ordersInFlight, 1)
+	held := &heldConn{Conn: conn, pool: pool}
+	returned := false
+	defer func() {
+		if !returned {
+			atomic.AddInt64(&ordersInFlight, -1)
+			if !held.released.Load() {
+				pool.Discard(conn)
+			}
+		}
+	}()
+	err := exchange(held)
+	returned = true
+	atomic.AddInt64(&ordersInFlight, -1)
+	if !held.released.Load() {
+		releaseConn(pool, conn, err)
+	}
+	return err
+}
+
+// heldConn is a pooled connection lent to one exchange. An exchange whose
+// response is routed to it by order ID (see orderRoute.Await) gives the
+// connection back with Release once its request is written, so other
+// requests are written on the connection while it waits, and must not read
+// from it after that.
+type heldConn struct {
+	net.Conn
+	pool     *ConnPool
+	released atomic.Bool
+}
+
+// Release returns the connection to its pool; later calls do nothing
+func (c *heldConn) Release() {
+	if c.released.CompareAndSwap(false, true) {
+		c.pool.Put(c.Conn)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestRetryRecoversFromFlakyConnection(t *testing.T) {
+	resetGlobals(t)
+
+	// The first two connections drop the order; the third answers it
+	var dials int32
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		client, server := net.Pipe()
+		if atomic.AddInt32(&dials, 1) <= 2 {
+			go func() {
+				protocol.ReadFrame(server)
+				server.Close()
+			}()
+		} else {
+			go serveFakeOrders(server, func(int) time.Duration { return 0 })
+		}
+		return client, nil
+	})
+	defer pool.Close()
+
+	var resp protocol.OrderResponse
+	err := withRetry(context.Background(), pool, 3, func(conn net.Conn) (err error) {
+		resp, err = submitOrderTCP(context.Background(), conn, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{OrderID: "order_retry"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("withRetry: %v", err)
+	}
+	if !resp.Accepted || resp.OrderID != "order_retry" {
+		t.Errorf("response %+v, want order_retry accepted", resp)
+	}
+	if n := atomic.LoadInt32(&dials); n != 3 {
+		t.Errorf("dialed %d times, want 3", n)
+	}
+	if got := atomic.LoadInt64(&stats.RetryAttempts); got != 2 {
+		t.Errorf("RetryAttempts = %d, want 2", got)
+	}
+	if got := atomic.LoadInt64(&stats.RetriedSucceeded); got != 1 {
+		t.Errorf("RetriedSucceeded = %d, want 1", got)
+	}
+	if got := atomic.LoadInt64(&stats.RetriedFailed); got != 0 {
+		t.Errorf("RetriedFailed = %d, want 0", got)
+	}
+}
+
+func TestReconnectAfterIdleClose(t *testing.T) {
+	resetGlobals(t)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// The first connection answers one order and then goes idle-closed;
+	// later ones answer everything
+	var dials int32
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		client, server := net.Pipe()
+		if atomic.AddInt32(&dials, 1) == 1 {
+			go func() {
+				defer server.Close()
+				body, err := protocol.ReadFrame(server)
+				if err != nil {
+					return
+				}
+				o, _ := protocol.DecodeSubmitOrder(body)
+				server.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: true}))
+			}()
+		} else {
+			go serveFakeOrders(server, func(int) time.Duration { return 0 })
+		}
+		return client, nil
+	})
+	defer pool.Close()
+
+	// No retries configured: only the reconnect lets the second order through
+	for i := 0; i < 3; i++ {
+		err := withRetry(context.Background(), pool, 0, func(conn net.Conn) error {
+			_, err := submitOrderTCP(context.Background(), conn, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{})
+			return err
+		})
+		if err != nil {
+			t.Fatalf("order %d after the idle close: %v", i, err)
+		}
+	}
+	if n := atomic.LoadInt32(&dials); n != 2 {
+		t.Errorf("dialed %d times, want 2", n)
+	}
+	if got := atomic.LoadInt64(&stats.Reconnects); got != 1 {
+		t.Errorf("Reconnects = %d, want 1", got)
+	}
+	if got := atomic.LoadInt64(&stats.OrdersAccepted); got != 3 {
+		t.Errorf("OrdersAccepted = %d, want all 3 orders", got)
+	}
+	if got := atomic.LoadInt64(&stats.RetryAttempts); got != 0 {
+		t.Errorf("RetryAttempts = %d, want the reconnect kept out of retries", got)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Engine sharding strategies selectable with -shard-by
+const (
+	// Each user connects to one engine, users spread evenly across engines
+	ShardRoundRobin = "round-robin"
+	// Each symbol always goes to the same engine
+	ShardSymbol = "symbol"
+)
+
+// EngineStats counts orders answered by one engine
+type EngineStats struct {
+	OrdersSubmitted int64
+	OrdersAccepted  int64
+}
+
+// parseEngineAddrs splits a comma-separated -engine list into host:port
+// addresses
+func parseEngineAddrs(spec string) ([]string, error) {
+	var addrs []string
+	for _, part := range strings.Split(spec, ",") {
+		addr := strings.TrimSpace(part)
+		if addr == "" {
+			return nil, fmt.Errorf("engine list %q has an empty entry", spec)
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, fmt.Errorf("invalid engine address %q: %w", addr, err)
+		}
+		if slices.Contains(addrs, addr) {
+			return nil, fmt.Errorf("engine %s listed twice", addr)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// symbolShard maps symbol to one of n engines. FNV-1a is fixed across
+// processes, unlike Go's map hash, so a symbol lands on the same engine in
+// every run.
+func symbolShard(symbol string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(symbol))
+	return int(h.Sum32() % uint32(n))
+}
+
+// enginePools holds one user's connections to each engine and routes each
+// order to the engine chosen by the shard strategy. With -conns-per-user a
+// user holds several connections per engine and spreads its orders across
+// them in turn.
+type enginePools struct {
+	addrs   []string
+	shardBy string
+	// home is the round-robin engine for this user
+	home int
+	// conns is the number of connections per engine; the pools of engine
+	// i are pools[i*conns : (i+1)*conns], one connection each
+	conns int
+	pools []*ConnPool
+	// next picks each engine's connection for the next order
+	next []atomic.Uint64
+	// orders counts the orders answered on each connection
+	orders []atomic.Int64
+}
+
+// newEnginePools builds conns single-connection pools per engine in addrs
+// for user userID. Pools dial on demand, so engines the user never routes to
+// are never contacted.
+func newEnginePools(addrs []string, shardBy string, userID, conns int, dial func(addr string) (net.Conn, error)) *enginePools {
+	conns = max(conns, 1)
+	e := &enginePools{
+		addrs:   addrs,
+		shardBy: shardBy,
+		home:    (userID - 1) % len(addrs),
+		conns:   conns,
+		pools:   make([]*ConnPool, len(addrs)*conns),
+		next:    make([]atomic.Uint64, len(addrs)),
+		orders:  make([]atomic.Int64, len(addrs)*conns),
+	}
+	for i := range e.pools {
+		addr := addrs[i/conns]
+		e.pools[i] = NewConnPool(1, func() (net.Conn, error) { return dial(addr) })
+	}
+	return e
+}
+
+// Route returns the index of the engine that handles symbol
+func (e *enginePools) Route(symbol string) int {
+	if e.shardBy == ShardSymbol {
+		return symbolShard(symbol, len(e.addrs))
+	}
+	return e.home
+}
+
+// For returns the first connection's pool for orders on symbol
+func (e *enginePools) For(symbol string) *ConnPool {
+	return e.pools[e.Route(symbol)*e.conns]
+}
+
+// Next returns the slot of the connection to engine that carries the next
+// order, cycling through the engine's connections
+func (e *enginePools) Next(engine int) int {
+	return engine*e.conns + int((e.next[engine].Add(1)-1)%uint64(e.conns))
+}
+
+// CountOrder records an order answered on the connection in slot
+func (e *enginePools) CountOrder(slot int) {
+	e.orders[slot].Add(1)
+}
+
+// Used returns the pools this user can route orders to
+func (e *enginePools) Used() []*ConnPool {
+	if e.shardBy == ShardSymbol {
+		return e.pools
+	}
+	return e.pools[e.home*e.conns : (e.home+1)*e.conns]
+}
+
+// OrderCounts returns the orders answered on each connection this user can
+// route to, in the order of Used
+func (e *enginePools) OrderCounts() []int64 {
+	first, last := 0, len(e.pools)
+	if e.shardBy != ShardSymbol {
+		first, last = e.home*e.conns, (e.home+1)*e.conns
+	}
+	counts := make([]int64, 0, last-first)
+	for i := first; i < last; i++ {
+		counts = append(counts, e.orders[i].Load())
+	}
+	return counts
+}
+
+// connectAll opens a connection in each of pools, all or none at a time
+// under -max-connections
+func connectAll(ctx context.Context, pools []*ConnPool) error {
+	unlock, err := lockConnect(ctx, len(pools))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	for _, pool := range pools {
+		conn, err := pool.Get(ctx)
+		if err != nil {
+			return err
+		}
+		pool.Put(conn)
+	}
+	return nil
+}
+
+// recordConnOrders adds one user's per-connection order counts to the run
+func recordConnOrders(counts []int64) {
+	statsMutex.Lock()
+	stats.ConnOrders = append(stats.ConnOrders, counts...)
+	statsMutex.Unlock()
+}
+
+// Close closes every pool
+func (e *enginePools) Close() {
+	for _, p := range e.pools {
+		p.Close()
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSymbolShardingStable(t *testing.T) {
+	addrs := []string{"engine-a:9000", "engine-b:9000", "engine-c:9000"}
+	dial := func(string) (net.Conn, error) { return nil, errors.New("not dialed") }
+
+	// Pinned FNV-1a assignments: a change here would move symbols between
+	// engines from one run (or client version) to the next
+	want := map[string]int{"AAPL": 1, "GOOGL": 2, "MSFT": 1, "AMZN": 0, "TSLA": 1}
+	for userID := 1; userID <= 4; userID++ {
+		engines := newEnginePools(addrs, ShardSymbol, userID, 1, dial)
+		for symbol, idx := range want {
+			if got := engines.Route(symbol); got != idx {
+				t.Errorf("user %d: %s routed to engine %d, want %d", userID, symbol, got, idx)
+			}
+		}
+		engines.Close()
+	}
+
+	// Round-robin spreads users evenly and ignores the symbol
+	counts := make([]int, len(addrs))
+	for userID := 1; userID <= 30; userID++ {
+		engines := newEnginePools(addrs, ShardRoundRobin, userID, 1, dial)
+		home := engines.Route("AAPL")
+		if engines.Route("AMZN") != home || len(engines.Used()) != 1 {
+			t.Fatalf("round-robin user %d does not stick to one engine", userID)
+		}
+		counts[home]++
+		engines.Close()
+	}
+	for i, n := range counts {
+		if n != 10 {
+			t.Errorf("engine %d got %d of 30 round-robin users, want 10", i, n)
+		}
+	}
+}
+
+func TestConnsPerUserSpreadsOrders(t *testing.T) {
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         2,
+		OrdersPerUser:    30,
+		Concurrency:      2,
+		OrderConcurrency: 3,
+		ConnsPerUser:     3,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+		CPUThreshold:     100,
+		DrainTimeout:     time.Second,
+	}
+	report := runStressTest(context.Background(), config, func() bool { return false })
+
+	snap := stats.snapshot()
+	if got := len(snap.ConnectLatencies); got != 6 {
+		t.Errorf("%d connections authenticated, want 6", got)
+	}
+	if len(snap.ConnOrders) != 6 {
+		t.Fatalf("orders counted on %d connections, want 6: %v", len(snap.ConnOrders), snap.ConnOrders)
+	}
+	var total int64
+	for i, n := range snap.ConnOrders {
+		// Round-robin gives each of a user's 3 connections exactly 10 of its 30 orders
+		if n != 10 {
+			t.Errorf("connection %d answered %d orders, want 10", i, n)
+		}
+		total += n
+	}
+	if total != report.OrdersSubmitted {
+		t.Errorf("connections answered %d orders, %d were submitted", total, report.OrdersSubmitted)
+	}
+	if c := report.ConnOrders; c == nil || c.Connections != 6 || c.Min != 10 || c.Max != 10 {
+		t.Errorf("conn_orders report = %+v, want 6 connections of 10 orders", c)
+	}
+}
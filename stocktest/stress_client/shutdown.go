@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os/signal"
+)
+
+// runUntilShutdown runs the workload, or each -sweep level, until it
+// finishes, -duration expires or the first shutdown signal arrives, and
+// returns the exit status. It is the one place a run ends: main exits with
+// the result after every deferred cleanup has run. Handling of the signals
+// is reset once the first arrives, so a second one kills a stuck drain.
+func runUntilShutdown(config StressConfig) int {
+	sigCtx, stop := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer stop()
+	// Nothing but a signal cancels sigCtx until stop runs on return
+	interrupted := func() bool { return sigCtx.Err() != nil }
+	defer context.AfterFunc(sigCtx, func() {
+		stop()
+		slog.Info("received shutdown signal, draining in-flight orders")
+	})()
+
+	// On a signal stop issuing orders and drain in-flight ones. -duration
+	// bounds the run; in -soak mode it is the only thing that ends it.
+	// Expiry drains and reports exactly like a signal. A -sweep applies it
+	// to each level instead.
+	ctx := sigCtx
+	if config.TestDuration > 0 && config.Sweep == "" {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.TestDuration)
+		defer cancel()
+	}
+
+	if config.Sweep != "" {
+		levels, _ := parseSweep(config.Sweep)
+		runSweep(ctx, config, levels)
+		if interrupted() {
+			return exitInterrupted
+		}
+		return exitOK
+	}
+	return exitCode(runStressTest(ctx, config, interrupted), config.FailErrorRate)
+}
@@ -0,0 +1,17 @@
+//go:build !unix && !windows
+
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import "os"
+
+// shutdownSignals stop a run gracefully. This platform has no SIGTERM.
+var shutdownSignals = []os.Signal{os.Interrupt}
@@ -0,0 +1,21 @@
+//go:build unix || windows
+
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals stop a run gracefully. Windows delivers its console close,
+// logoff and shutdown events as SIGTERM.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestShutdownSignalReportsOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Process.Signal cannot send an interrupt on windows")
+	}
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	reports := &countingWriter{marker: "=== FINAL RESULTS ==="}
+	log.SetOutput(reports)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         2,
+		Concurrency:      2,
+		OrderConcurrency: 2,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+		CPUThreshold:     100,
+		DrainTimeout:     time.Second,
+		TestDuration:     time.Minute,
+		Soak:             true,
+	}
+
+	self, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(200*time.Millisecond, func() { self.Signal(os.Interrupt) })
+
+	start := time.Now()
+	code := runUntilShutdown(config)
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond+config.DrainTimeout+time.Second {
+		t.Errorf("run took %v after the signal, want it to drain and stop", elapsed)
+	}
+	if code != exitInterrupted {
+		t.Errorf("exit status %d, want %d", code, exitInterrupted)
+	}
+	if n := reports.Count(); n != 1 {
+		t.Errorf("final results printed %d times, want once", n)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import "context"
+
+// signupSlots caps the signup and login requests users have in flight
+// together (-signup-concurrency), so account creation can be throttled for
+// the frontend while orders still go to the engine at full concurrency. Nil
+// leaves them unlimited.
+var signupSlots chan struct{}
+
+// newSignupSlots returns a semaphore of n slots, or nil when n is 0
+func newSignupSlots(n int) chan struct{} {
+	if n <= 0 {
+		return nil
+	}
+	return make(chan struct{}, n)
+}
+
+// withSignupSlot runs one frontend call once a signup slot is free. It
+// returns ctx's error without calling if ctx ends while waiting.
+func withSignupSlot(ctx context.Context, call func() error) error {
+	if signupSlots != nil {
+		select {
+		case signupSlots <- struct{}{}:
+			defer func() { <-signupSlots }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return call()
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSignupConcurrencyLimit(t *testing.T) {
+	var inFlight, peak, calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		n := inFlight.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		calls.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		io.WriteString(w, `{"tokens":{"tradingToken":"token"}}`)
+	}))
+	defer srv.Close()
+
+	saved := frontendClient
+	frontendClient = newFrontendClient(5*time.Second, 50)
+	defer func() { frontendClient = saved }()
+
+	const limit = 3
+	signupSlots = newSignupSlots(limit)
+	defer func() { signupSlots = nil }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	const workers = 30
+	config := StressConfig{FrontendURL: srv.URL}
+	var wg sync.WaitGroup
+	for userID := 1; userID <= workers; userID++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := signupAndLogin(context.Background(), config, userID); !ok {
+				t.Errorf("user %d failed to sign up and log in", userID)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 2*workers {
+		t.Errorf("%d frontend calls, want %d", n, 2*workers)
+	}
+	if p := peak.Load(); p != limit {
+		t.Errorf("%d frontend calls in flight at once, want the limit of %d", p, limit)
+	}
+
+	// A user queued for a slot gives up when the run is cancelled
+	for range limit {
+		signupSlots <- struct{}{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := signupAndLogin(ctx, config, 1); ok {
+		t.Error("cancelled user signed up while every slot was taken")
+	}
+	if n := calls.Load(); n != 2*workers {
+		t.Errorf("cancelled user made %d frontend calls", n-2*workers)
+	}
+}
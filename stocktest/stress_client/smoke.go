@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// Smoke orders are a single-share limit buy at -price-ref
+const smokeQuantity = 1
+
+// runSmoke checks end-to-end connectivity for -smoke: it signs up and logs
+// in one user, connects to the first engine with the TLS flags, submits one
+// order and requires it to be accepted. -http-timeout bounds each frontend
+// request and ctx bounds the whole check, including the engine exchange.
+func runSmoke(ctx context.Context, config StressConfig) error {
+	addrs, err := parseEngineAddrs(config.EngineAddr)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := loginSharedUser(config)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	conn, err := dialEngine(ctx, addrs[0], tokens.TradingToken)
+	if err != nil {
+		return fmt.Errorf("engine %s: %w", addrs[0], err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// Unblock the exchange if ctx is cancelled first
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	symbol := config.Symbols[0]
+	start := time.Now()
+	resp, err := submitOrderTCP(ctx, conn, userIDString(sharedUserID), symbol,
+		protocol.OrderSideBuy, protocol.OrderTypeLimit, smokeQuantity, config.PriceRef, submitOptions{})
+	if err != nil {
+		return fmt.Errorf("submit order: %w", err)
+	}
+	if !resp.Accepted {
+		return fmt.Errorf("order %s on %s rejected: %s", resp.OrderID, symbol, resp.Message)
+	}
+	log.Printf("Smoke order %s on %s accepted in %v", resp.OrderID, symbol, time.Since(start).Round(time.Microsecond))
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSmoke(t *testing.T) {
+	frontend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		switch r.URL.Path {
+		case "/api/auth/stress-signup":
+			w.WriteHeader(http.StatusCreated)
+		case "/api/auth/login":
+			io.WriteString(w, `{"tokens":{"tradingToken":"token"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer frontend.Close()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, accept := range []bool{true, false} {
+		config := StressConfig{
+			FrontendURL: frontend.URL,
+			EngineAddr:  startFakeTLSEngine(t, accept),
+			Symbols:     defaultSymbols,
+			PriceRef:    100,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := runSmoke(ctx, config)
+		cancel()
+		if accept && err != nil {
+			t.Errorf("smoke against an accepting engine: %v", err)
+		}
+		if !accept && err == nil {
+			t.Error("smoke passed against an engine that rejects every order")
+		}
+	}
+}
+
+func TestSmokeSeedsNamespace(t *testing.T) {
+	defer func(seed int64) { runSeed = seed }(runSeed)
+	runSeed = 0
+
+	var email string
+	frontend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/auth/stress-signup":
+			var req SignupRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			email = req.Email
+			w.WriteHeader(http.StatusCreated)
+		case "/api/auth/login":
+			io.Copy(io.Discard, r.Body)
+			io.WriteString(w, `{"tokens":{"tradingToken":"token"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer frontend.Close()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// As main does with no -seed, before the smoke check signs anyone up
+	seedRun(0)
+	config := StressConfig{
+		FrontendURL: frontend.URL,
+		EngineAddr:  startFakeTLSEngine(t, true),
+		Symbols:     defaultSymbols,
+		PriceRef:    100,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := runSmoke(ctx, config); err != nil {
+		t.Fatalf("smoke: %v", err)
+	}
+	if email == "" {
+		t.Fatal("smoke signed up no user")
+	}
+	if strings.HasPrefix(email, "stress-0-") {
+		t.Errorf("smoke signed up %s in the unseeded namespace", email)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+)
+
+// socketOptions are the TCP options set on every engine connection
+type socketOptions struct {
+	// NoDelay disables Nagle's algorithm so small order frames are sent
+	// at once instead of waiting to be coalesced
+	NoDelay bool
+	// Kernel socket buffer sizes in bytes; 0 keeps the OS default
+	SendBuffer int
+	RecvBuffer int
+}
+
+// engineSocket is set from -nodelay, -sndbuf and -rcvbuf
+var engineSocket = socketOptions{NoDelay: true}
+
+// apply sets the options on conn, unwrapping a TLS connection to reach the
+// TCP socket beneath it. Connections that are not TCP, such as dry-run
+// sinks, are left alone.
+func (o socketOptions) apply(conn net.Conn) error {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	tcp, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+	if err := tcp.SetNoDelay(o.NoDelay); err != nil {
+		return fmt.Errorf("set TCP_NODELAY: %w", err)
+	}
+	if o.SendBuffer > 0 {
+		if err := tcp.SetWriteBuffer(o.SendBuffer); err != nil {
+			return fmt.Errorf("set send buffer: %w", err)
+		}
+	}
+	if o.RecvBuffer > 0 {
+		if err := tcp.SetReadBuffer(o.RecvBuffer); err != nil {
+			return fmt.Errorf("set receive buffer: %w", err)
+		}
+	}
+	return nil
+}
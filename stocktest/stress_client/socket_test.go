@@ -0,0 +1,93 @@
+//go:build linux
+
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"syscall"
+	"testing"
+)
+
+// sockopt reads an integer socket option from conn
+func sockopt(t *testing.T, conn *net.TCPConn, level, opt int) int {
+	t.Helper()
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		t.Fatalf("SyscallConn: %v", err)
+	}
+	var val int
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		val, sockErr = syscall.GetsockoptInt(int(fd), level, opt)
+	}); err != nil {
+		t.Fatalf("Control: %v", err)
+	}
+	if sockErr != nil {
+		t.Fatalf("getsockopt: %v", sockErr)
+	}
+	return val
+}
+
+func TestSocketOptionsApplied(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	dial := func() *net.TCPConn {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn.(*net.TCPConn)
+	}
+
+	// Go enables TCP_NODELAY by default, so turning it off shows the option
+	// is really applied
+	off := dial()
+	if err := (socketOptions{NoDelay: false}).apply(off); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := sockopt(t, off, syscall.IPPROTO_TCP, syscall.TCP_NODELAY); got != 0 {
+		t.Errorf("TCP_NODELAY = %d after NoDelay false, want 0", got)
+	}
+
+	// Options reach the TCP socket beneath a TLS connection
+	const bufSize = 128 << 10
+	tcp := dial()
+	opts := socketOptions{NoDelay: true, SendBuffer: bufSize, RecvBuffer: bufSize}
+	if err := opts.apply(tls.Client(tcp, &tls.Config{InsecureSkipVerify: true})); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := sockopt(t, tcp, syscall.IPPROTO_TCP, syscall.TCP_NODELAY); got == 0 {
+		t.Error("TCP_NODELAY not set through the TLS connection")
+	}
+	// Linux doubles the requested size to leave room for bookkeeping
+	if got := sockopt(t, tcp, syscall.SOL_SOCKET, syscall.SO_SNDBUF); got < bufSize {
+		t.Errorf("SO_SNDBUF = %d, want at least %d", got, bufSize)
+	}
+	if got := sockopt(t, tcp, syscall.SOL_SOCKET, syscall.SO_RCVBUF); got < bufSize {
+		t.Errorf("SO_RCVBUF = %d, want at least %d", got, bufSize)
+	}
+}
@@ -0,0 +1,313 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Interval latency windows. Each periodic reader takes its own window, so
+// one draining it does not leave the others with nothing.
+const (
+	windowTimeSeries = iota
+	windowAutoscale
+	numIntervalWindows
+)
+
+// intervalWindowsEnabled marks the windows whose reader is running; orders
+// are only recorded into those
+var intervalWindowsEnabled [numIntervalWindows]atomic.Bool
+
+// statsShard holds the per-order stats recorded on the hot path. Each user
+// records into one shard (see StressStats.shard), so orders from users on
+// different shards never contend for a lock. Readers merge every shard into
+// the StressStats report fields with StressStats.snapshot.
+type statsShard struct {
+	mu sync.Mutex
+
+	// Recorders are created on first use, so shards that never see a
+	// fragmented or corrected order hold no memory for them
+	orderLatencies       LatencyRecorder
+	fragmentedLatencies  LatencyRecorder
+	uncorrectedLatencies LatencyRecorder
+	queryLatencies       LatencyRecorder
+	fillLatencies        LatencyRecorder
+	intervalLatencies    [numIntervalWindows]LatencyRecorder
+
+	symbols       map[string]*SymbolStats
+	rejectCodes   map[uint16]int64
+	rejectReasons map[string]int64
+	engines       map[string]*EngineStats
+	skews         map[time.Duration]*SkewStats
+
+	// Recently accepted order IDs, kept across warmup resets
+	acked *orderIDWindow
+}
+
+// orderOutcome is one answered order as recorded in a stats shard
+type orderOutcome struct {
+	OrderID  string
+	Symbol   string
+	Latency  time.Duration
+	Accepted bool
+	// ServiceLatency is recorded separately when Corrected is set
+	ServiceLatency time.Duration
+	Corrected      bool
+	Fragmented     bool
+	RejectCode     uint16
+	HasRejectCode  bool
+	RejectReason   string
+	Skew           time.Duration
+	SkewTracked    bool
+}
+
+// newStatsShards returns GOMAXPROCS shards. Shards are chosen by user ID, not
+// by CPU, so users whose IDs share a residue still contend on one shard.
+func newStatsShards() []*statsShard {
+	shards := make([]*statsShard, runtime.GOMAXPROCS(0))
+	for i := range shards {
+		shards[i] = &statsShard{}
+	}
+	return shards
+}
+
+// recordInto records d into *r, creating the recorder on first use
+func recordInto(r *LatencyRecorder, d time.Duration) {
+	if *r == nil {
+		*r = newLatencyRecorder()
+	}
+	(*r).Record(d)
+}
+
+// recordOrder adds one answered order to the shard. It reports whether the
+// order was accepted under an order ID this shard had already seen accepted.
+func (sh *statsShard) recordOrder(o orderOutcome) (duplicate bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	recordInto(&sh.orderLatencies, o.Latency)
+	for w := range sh.intervalLatencies {
+		if intervalWindowsEnabled[w].Load() {
+			recordInto(&sh.intervalLatencies[w], o.Latency)
+		}
+	}
+	if o.Corrected {
+		recordInto(&sh.uncorrectedLatencies, o.ServiceLatency)
+	}
+	if o.Fragmented {
+		recordInto(&sh.fragmentedLatencies, o.Latency)
+	}
+
+	if sh.symbols == nil {
+		sh.symbols = make(map[string]*SymbolStats)
+	}
+	symStats := sh.symbols[o.Symbol]
+	if symStats == nil {
+		symStats = &SymbolStats{Latencies: &latencyReservoir{}}
+		sh.symbols[o.Symbol] = symStats
+	}
+	symStats.OrdersSubmitted++
+	symStats.Latencies.Record(o.Latency)
+
+	if o.Accepted {
+		symStats.OrdersAccepted++
+	} else {
+		if o.HasRejectCode {
+			if sh.rejectCodes == nil {
+				sh.rejectCodes = make(map[uint16]int64)
+			}
+			sh.rejectCodes[o.RejectCode]++
+		}
+		if sh.rejectReasons == nil {
+			sh.rejectReasons = make(map[string]int64)
+		}
+		countRejectReason(sh.rejectReasons, o.RejectReason)
+	}
+
+	if o.SkewTracked {
+		if sh.skews == nil {
+			sh.skews = make(map[time.Duration]*SkewStats)
+		}
+		ss := sh.skews[o.Skew]
+		if ss == nil {
+			ss = &SkewStats{}
+			sh.skews[o.Skew] = ss
+		}
+		ss.OrdersSubmitted++
+		if o.Accepted {
+			ss.OrdersAccepted++
+		}
+	}
+
+	if o.Accepted && o.OrderID != "" {
+		if sh.acked == nil {
+			sh.acked = newOrderIDWindow(shardAckedOrders())
+		}
+		duplicate = sh.acked.Add(o.OrderID)
+	}
+	return duplicate
+}
+
+// shardAckedOrders is one shard's share of ackedOrderWindow. A user always
+// records into the same shard, so its repeated order IDs meet in one window.
+func shardAckedOrders() int {
+	return max(ackedOrderWindow/runtime.GOMAXPROCS(0), minShardAckedOrders)
+}
+
+// recordQuery adds one successful -query-pct read to the shard
+func (sh *statsShard) recordQuery(latency time.Duration) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	recordInto(&sh.queryLatencies, latency)
+}
+
+// recordFill adds one marketable order's submit-to-fill latency to the shard
+func (sh *statsShard) recordFill(latency time.Duration) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	recordInto(&sh.fillLatencies, latency)
+}
+
+// recordEngine counts an order answered by the engine at addr
+func (sh *statsShard) recordEngine(addr string, accepted bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if sh.engines == nil {
+		sh.engines = make(map[string]*EngineStats)
+	}
+	es := sh.engines[addr]
+	if es == nil {
+		es = &EngineStats{}
+		sh.engines[addr] = es
+	}
+	es.OrdersSubmitted++
+	if accepted {
+		es.OrdersAccepted++
+	}
+}
+
+// reset discards the shard's order stats. The interval recorders belong to
+// their periodic readers and are left alone.
+func (sh *statsShard) reset() {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.orderLatencies = nil
+	sh.fragmentedLatencies = nil
+	sh.uncorrectedLatencies = nil
+	sh.queryLatencies = nil
+	sh.fillLatencies = nil
+	sh.symbols = nil
+	sh.rejectCodes = nil
+	sh.rejectReasons = nil
+	sh.engines = nil
+	sh.skews = nil
+}
+
+// shard returns the shard that worker id records into, id modulo the shard
+// count
+func (s *StressStats) shard(id int) *statsShard {
+	return s.shards[id%len(s.shards)]
+}
+
+// mergeLatencies merges the recorder pick selects from every shard into a
+// new recorder, locking each shard in turn
+func (s *StressStats) mergeLatencies(pick func(*statsShard) LatencyRecorder) LatencyRecorder {
+	merged := newLatencyRecorder()
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		if r := pick(sh); r != nil {
+			merged.Merge(r)
+		}
+		sh.mu.Unlock()
+	}
+	return merged
+}
+
+// takeIntervalLatencies merges and clears every shard's recorder for the
+// interval window w
+func (s *StressStats) takeIntervalLatencies(w int) LatencyRecorder {
+	return s.mergeLatencies(func(sh *statsShard) LatencyRecorder {
+		r := sh.intervalLatencies[w]
+		sh.intervalLatencies[w] = nil
+		return r
+	})
+}
+
+// snapshot returns a copy of s with the latency recorders and the symbol,
+// reject-code, reject-reason, engine and skew breakdowns merged from every shard. The copy shares
+// nothing with the shards, so it can be read after the locks are released.
+// Callers hold statsMutex for the fields it guards.
+func (s *StressStats) snapshot() StressStats {
+	out := *s
+	out.OrderLatencies = s.mergeLatencies(func(sh *statsShard) LatencyRecorder { return sh.orderLatencies })
+	out.FragmentedLatencies = s.mergeLatencies(func(sh *statsShard) LatencyRecorder { return sh.fragmentedLatencies })
+	out.UncorrectedLatencies = s.mergeLatencies(func(sh *statsShard) LatencyRecorder { return sh.uncorrectedLatencies })
+	out.QueryLatencies = s.mergeLatencies(func(sh *statsShard) LatencyRecorder { return sh.queryLatencies })
+	out.SubmitToFillLatencies = s.mergeLatencies(func(sh *statsShard) LatencyRecorder { return sh.fillLatencies })
+	out.Symbols, out.RejectCodes, out.RejectReasons, out.Engines, out.Skews = nil, nil, nil, nil, nil
+
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		for symbol, ss := range sh.symbols {
+			if out.Symbols == nil {
+				out.Symbols = make(map[string]*SymbolStats)
+			}
+			merged := out.Symbols[symbol]
+			if merged == nil {
+				merged = &SymbolStats{Latencies: &latencyReservoir{}}
+				out.Symbols[symbol] = merged
+			}
+			merged.OrdersSubmitted += ss.OrdersSubmitted
+			merged.OrdersAccepted += ss.OrdersAccepted
+			merged.Latencies.Merge(ss.Latencies)
+		}
+		for code, n := range sh.rejectCodes {
+			if out.RejectCodes == nil {
+				out.RejectCodes = make(map[uint16]int64)
+			}
+			out.RejectCodes[code] += n
+		}
+		for reason, n := range sh.rejectReasons {
+			if out.RejectReasons == nil {
+				out.RejectReasons = make(map[string]int64)
+			}
+			out.RejectReasons[reason] += n
+		}
+		for addr, es := range sh.engines {
+			if out.Engines == nil {
+				out.Engines = make(map[string]*EngineStats)
+			}
+			merged := out.Engines[addr]
+			if merged == nil {
+				merged = &EngineStats{}
+				out.Engines[addr] = merged
+			}
+			merged.OrdersSubmitted += es.OrdersSubmitted
+			merged.OrdersAccepted += es.OrdersAccepted
+		}
+		for skew, ss := range sh.skews {
+			if out.Skews == nil {
+				out.Skews = make(map[time.Duration]*SkewStats)
+			}
+			merged := out.Skews[skew]
+			if merged == nil {
+				merged = &SkewStats{}
+				out.Skews[skew] = merged
+			}
+			merged.OrdersSubmitted += ss.OrdersSubmitted
+			merged.OrdersAccepted += ss.OrdersAccepted
+		}
+		sh.mu.Unlock()
+	}
+	return out
+}
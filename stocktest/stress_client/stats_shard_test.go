@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShardedStatsMerge(t *testing.T) {
+	defer func(cap int) { latencySampleCap = cap }(latencySampleCap)
+	latencySampleCap = 1000
+
+	for _, kind := range []string{HistogramReservoir, HistogramHDR} {
+		latencyHistogram = kind
+		s := StressStats{shards: []*statsShard{{}, {}}}
+
+		// 9000 fast AAPL orders on one shard and 1000 slow, rejected MSFT
+		// orders on the other, overflowing each reservoir
+		for i := 0; i < 9000; i++ {
+			s.shard(0).recordOrder(orderOutcome{Symbol: "AAPL", Latency: time.Millisecond, Accepted: true})
+		}
+		for i := 0; i < 1000; i++ {
+			s.shard(1).recordOrder(orderOutcome{Symbol: "MSFT", Latency: 2 * time.Millisecond, RejectCode: 3, HasRejectCode: true})
+		}
+		s.shard(1).recordEngine("engine-a:9000", true)
+
+		snap := s.snapshot()
+		r := snap.OrderLatencies
+		if r.Count() != 10000 || r.Min() != time.Millisecond || r.Max() != 2*time.Millisecond {
+			t.Errorf("%s: merged Count/Min/Max = %d/%v/%v, want 10000/1ms/2ms", kind, r.Count(), r.Min(), r.Max())
+		}
+		if want := 1100 * time.Microsecond; r.Mean() != want {
+			t.Errorf("%s: merged Mean = %v, want %v", kind, r.Mean(), want)
+		}
+		// The slow orders are 10% of the total, so p85 is fast and p95 slow
+		if p85, p95 := r.Percentile(0.85), r.Percentile(0.95); p85 > 1100*time.Microsecond || p95 < 1900*time.Microsecond {
+			t.Errorf("%s: merged p85/p95 = %v/%v, want the 10%% slow tail weighted by count", kind, p85, p95)
+		}
+		if ss := snap.Symbols["MSFT"]; ss == nil || ss.OrdersSubmitted != 1000 || ss.OrdersAccepted != 0 {
+			t.Errorf("%s: MSFT symbol stats = %+v, want 1000 submitted, none accepted", kind, ss)
+		}
+		// Per-symbol latencies stay sampled even with -histogram hdr
+		for symbol, ss := range snap.Symbols {
+			if _, ok := ss.Latencies.(*latencyReservoir); !ok {
+				t.Errorf("%s: %s latencies are %T, want a reservoir", kind, symbol, ss.Latencies)
+			}
+		}
+		if got := snap.RejectCodes[3]; got != 1000 {
+			t.Errorf("%s: reject code 3 counted %d times, want 1000", kind, got)
+		}
+		if es := snap.Engines["engine-a:9000"]; es == nil || es.OrdersAccepted != 1 {
+			t.Errorf("%s: engine stats = %+v, want 1 accepted", kind, es)
+		}
+	}
+	latencyHistogram = HistogramReservoir
+}
+
+// BenchmarkRecordOrderStats measures the per-order stats update on the old
+// path ("baseline": statsMutex, a latency slice append and the symbol map),
+// with every worker contending on one shard ("global") and with workers
+// spread over GOMAXPROCS shards ("sharded"):
+// go test -run '^$' -bench RecordOrderStats -cpu 1,4,8
+func BenchmarkRecordOrderStats(b *testing.B) {
+	b.Run("baseline", func(b *testing.B) {
+		var (
+			mu        sync.Mutex
+			latencies []time.Duration
+			symbols   = make(map[string]*SymbolStats)
+			minLat    time.Duration
+			maxLat    time.Duration
+			total     time.Duration
+		)
+		b.RunParallel(func(pb *testing.PB) {
+			latency := time.Millisecond
+			for pb.Next() {
+				mu.Lock()
+				latencies = append(latencies, latency)
+				symStats := symbols["AAPL"]
+				if symStats == nil {
+					symStats = &SymbolStats{}
+					symbols["AAPL"] = symStats
+				}
+				symStats.OrdersSubmitted++
+				symStats.OrdersAccepted++
+				if minLat == 0 || latency < minLat {
+					minLat = latency
+				}
+				if latency > maxLat {
+					maxLat = latency
+				}
+				total += latency
+				_ = total / time.Duration(len(latencies))
+				mu.Unlock()
+			}
+		})
+	})
+	for _, tc := range []struct {
+		name   string
+		shards func() []*statsShard
+	}{
+		{"global", func() []*statsShard { return []*statsShard{{}} }},
+		{"sharded", newStatsShards},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			s := StressStats{shards: tc.shards()}
+			var workers atomic.Int64
+			b.RunParallel(func(pb *testing.PB) {
+				sh := s.shard(int(workers.Add(1)))
+				o := orderOutcome{Symbol: "AAPL", Latency: time.Millisecond, Accepted: true}
+				for pb.Next() {
+					sh.recordOrder(o)
+				}
+			})
+		})
+	}
+}
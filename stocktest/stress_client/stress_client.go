@@ -10,69 +10,131 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
-	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
-	"math/rand"
+	"log/slog"
 	"net"
 	"net/http"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
-	"unsafe"
+
+	"stress_client/protocol"
 )
 
-// Stress client configuration
+// Stress client configuration. The yaml tags name the keys accepted by
+// -config files.
 type StressConfig struct {
-	FrontendURL      string
-	EngineAddr       string
-	NumUsers         int
-	OrdersPerUser    int
-	Concurrency      int
-	OrderConcurrency int
-	TestDuration     time.Duration
-	Symbols          []string
+	FrontendURL      string        `yaml:"frontend"`
+	EngineAddr       string        `yaml:"engine"`
+	NumUsers         int           `yaml:"users"`
+	OrdersPerUser    int           `yaml:"orders"`
+	Concurrency      int           `yaml:"concurrency"`
+	OrderConcurrency int           `yaml:"order_concurrency"`
+	TestDuration     time.Duration `yaml:"duration"`
+	RampUp           time.Duration `yaml:"ramp_up"`
+	Symbols          []string      `yaml:"symbols"`
+	HDRPath          string        `yaml:"hdr"`
+	FragmentPct      int           `yaml:"fragment_pct"`
+	FragmentSize     int           `yaml:"fragment_size"`
+	FragmentDelay    time.Duration `yaml:"fragment_delay"`
+	CancelPct        int           `yaml:"cancel_pct"`
+	OrderMix         string        `yaml:"order_mix"`
+	PriceModel       string        `yaml:"price_model"`
+	PriceRef         float64       `yaml:"price_ref"`
+	PriceSpread      float64       `yaml:"price_spread"`
+	CPUThreshold     float64       `yaml:"cpu_threshold"`
+	CPUBackoff       bool          `yaml:"cpu_backoff"`
+	CrossAccounts    int           `yaml:"cross_accounts"`
+	CrossSettle      time.Duration `yaml:"cross_settle"`
+	TimeSeriesPath   string        `yaml:"timeseries"`
+	CorrectOmission  bool          `yaml:"correct_omission"`
+	TargetRate       float64       `yaml:"target_rate"`
+	Rate             float64       `yaml:"rate"`
+	Heartbeat        time.Duration `yaml:"heartbeat"`
+	VerifyBook       time.Duration `yaml:"verify_book"`
+	HeartbeatMisses  int           `yaml:"heartbeat_misses"`
+	Warmup           time.Duration `yaml:"warmup"`
+	DrainTimeout     time.Duration `yaml:"drain_timeout"`
+	MaxRetries       int           `yaml:"max_retries"`
+	OutputJSON       string        `yaml:"output_json"`
+	OrdersCSV        string        `yaml:"csv"`
+	MetricsAddr      string        `yaml:"metrics_addr"`
+	PprofAddr        string        `yaml:"pprof_addr"`
+	TLSCA            string        `yaml:"tls_ca"`
+	TLSServerName    string        `yaml:"tls_servername"`
+	TLSInsecure      bool          `yaml:"tls_insecure"`
+	HTTPTimeout      time.Duration `yaml:"http_timeout"`
+	IOTimeout        time.Duration `yaml:"io_timeout"`
+	DryRun           bool          `yaml:"dry_run"`
+	Profile          string        `yaml:"profile"`
+	ModifyPct        int           `yaml:"modify_pct"`
+	ShardBy          string        `yaml:"shard_by"`
+	Soak             bool          `yaml:"soak"`
+	LogLevel         string        `yaml:"log_level"`
+	Replay           string        `yaml:"replay"`
+	ReplayTiming     bool          `yaml:"replay_timing"`
+	Autoscale        bool          `yaml:"autoscale"`
+	LatencySLO       time.Duration `yaml:"latency_slo"`
+	Smoke            bool          `yaml:"smoke"`
+	ThinkTime        string        `yaml:"think_time"`
+	MaxErrorRate     float64       `yaml:"max_error_rate"`
+	FailErrorRate    float64       `yaml:"fail_error_rate"`
+	NoDelay          bool          `yaml:"nodelay"`
+	SendBuffer       int           `yaml:"sndbuf"`
+	RecvBuffer       int           `yaml:"rcvbuf"`
+	QueryPct         int           `yaml:"query_pct"`
+	OrderPrefix      string        `yaml:"order_prefix"`
+	TimestampSkew    string        `yaml:"timestamp_skew"`
+	ReportInterval   string        `yaml:"report_interval"`
+	VerifyDepth      int           `yaml:"verify_depth"`
+	DepthSymbol      string        `yaml:"verify_depth_symbol"`
+	HistBuckets      string        `yaml:"hist_buckets"`
+	TokensFile       string        `yaml:"tokens_file"`
+	ReuseTokens      bool          `yaml:"reuse_tokens"`
+	SingleUser       bool          `yaml:"single_user"`
+	Sweep            string        `yaml:"sweep"`
+	Manifest         string        `yaml:"manifest"`
+	SkipPreflight    bool          `yaml:"skip_preflight"`
+	QtyModel         string        `yaml:"qty_model"`
+	LotSize          int           `yaml:"lot_size"`
+	CrossPct         int           `yaml:"cross_pct"`
+	Probe            bool          `yaml:"probe"`
+	VerifyTIF        bool          `yaml:"verify_tif"`
+	TotalOrders      int64         `yaml:"total_orders"`
+	TLSSessionCache  int           `yaml:"tls_session_cache_size"`
+	OrderIDLen       int           `yaml:"order_id_len"`
+	ConnsPerUser     int           `yaml:"conns_per_user"`
+	SignupConc       int           `yaml:"signup_concurrency"`
+	SymbolDist       string        `yaml:"symbol_dist"`
+	ZipfSkew         float64       `yaml:"zipf_skew"`
+	MaxConns         int           `yaml:"max_connections"`
+	UserIDLen        int           `yaml:"user_id_len"`
+	TrackFills       bool          `yaml:"track_fills"`
 }
 
-// TCP Protocol Constants (matching TCPServer.h)
+// Order response layout versions. Version 2 appends a uint16 reject_code
+// after the message string, and version 3 the engine's clock after that.
 const (
-	MessageTypeLoginRequest  = 1
-	MessageTypeLoginResponse = 2
-	MessageTypeSubmitOrder   = 3
-	MessageTypeOrderResponse = 4
-	MessageTypeHeartbeat     = 5
-	MessageTypeHeartbeatAck  = 6
-	OrderSideBuy             = 0
-	OrderSideSell            = 1
-	OrderTypeMarket          = 0
-	OrderTypeLimit           = 1
+	ProtocolVersionBase        = 1
+	ProtocolVersionRejectCodes = 2
+	ProtocolVersionServerTime  = 3
 )
 
-// Binary protocol structures matching C++ implementation
-type BinaryLoginRequestBody struct {
-	Type     uint8
-	TokenLen uint32
-}
+// protocolVersion selects which optional response fields the client decodes
+var protocolVersion = ProtocolVersionBase
 
-type BinaryLoginResponse struct {
-	MessageLength uint32
-	Type          uint8
-	Success       uint8
-	MessageLen    uint32
-}
+// minServerVersion is the oldest protocol version an engine may answer a
+// login with. Engines that report no version count as ProtocolVersionBase.
+var minServerVersion = ProtocolVersionBase
 
-type BinaryOrderResponse struct {
-	MessageLength uint32
-	Type          uint8
-	OrderIdLen    uint32
-	Accepted      uint8
-	MessageLen    uint32
-}
+// errVersionMismatch marks an engine login that succeeded with a protocol
+// version this client cannot or will not speak
+var errVersionMismatch = errors.New("protocol version mismatch")
 
 // Frontend API types
 type SignupRequest struct {
@@ -96,32 +158,147 @@ type AuthResponse struct {
 		ID    string `json:"id"`
 		Email string `json:"email"`
 	} `json:"user"`
-	Tokens struct {
-		SessionToken     string `json:"sessionToken"`
-		TradingToken     string `json:"tradingToken"`
-		ExpiresIn        int    `json:"expiresIn"`
-		TradingExpiresIn int    `json:"tradingExpiresIn"`
-	} `json:"tokens"`
+	Tokens AuthTokens `json:"tokens"`
+	// Some frontend builds return the tokens flat at the top level instead
+	AuthTokens
+}
+
+// errMissingTradingToken means a login response carried no trading token in
+// either of the shapes the frontend has used
+var errMissingTradingToken = errors.New("login response has no trading token (checked tokens.tradingToken and tradingToken)")
+
+// decodeAuthResponse reads a login response and returns its tokens, taking
+// whichever of the nested and flat shapes has a trading token
+func decodeAuthResponse(r io.Reader) (AuthTokens, error) {
+	var authResp AuthResponse
+	if err := json.NewDecoder(r).Decode(&authResp); err != nil {
+		return AuthTokens{}, fmt.Errorf("failed to decode login response: %w", err)
+	}
+	switch {
+	case authResp.Tokens.TradingToken != "":
+		return authResp.Tokens, nil
+	case authResp.AuthTokens.TradingToken != "":
+		return authResp.AuthTokens, nil
+	}
+	return AuthTokens{}, errMissingTradingToken
+}
+
+type AuthTokens struct {
+	SessionToken     string `json:"sessionToken"`
+	TradingToken     string `json:"tradingToken"`
+	ExpiresIn        int    `json:"expiresIn"`
+	TradingExpiresIn int    `json:"tradingExpiresIn"`
 }
 
 // Global stats
 type StressStats struct {
 	UsersCreated    int64
 	UsersLoggedIn   int64
+	UsersExisting   int64
+	UsersCompleted  int64
 	OrdersSubmitted int64
 	OrdersAccepted  int64
 	Errors          int64
-	// Latency tracking (in nanoseconds)
+	// Errors by category (see error_stats.go), guarded by statsMutex
+	ErrorCategories map[string]int64
+	// Order cancellation
+	CancelsSubmitted int64
+	CancelsAccepted  int64
+	// Cancels not sent because one had gone unanswered on the connection
+	CancelsSkipped int64
+	// Order amendment
+	ModifiesSubmitted int64
+	ModifiesAccepted  int64
+	// Modifies not sent because one had gone unanswered on the connection
+	ModifiesSkipped int64
+	// Portfolio reads mixed in with -query-pct
+	QueriesSubmitted int64
+	QueryErrors      int64
+	// Transient failures retried with -max-retries
+	RetryAttempts    int64
+	RetriedSucceeded int64
+	RetriedFailed    int64
+	// Connections closed by the engine and redialed by withRetry
+	Reconnects int64
+	// Pooled connections dialed and authenticated again to replace one
+	// discarded after an error, and those replacements that failed
+	Reauths        int64
+	ReauthFailures int64
+	// Signups and logins resent after the frontend answered 429
+	RateLimitedRetries int64
+	// Frames the engine pushed unsolicited, such as execution reports
+	PushMessages int64
+	// Engine TLS handshakes, by whether a cached session was resumed
+	TLSFullHandshakes int64
+	TLSResumed        int64
+	// Top-of-book queries from -verify-book
+	BookQueries int64
+	BookEmpty   int64
+	// Book queries not sent because the engine left one unanswered
+	BookQueriesSkipped int64
+	// Heartbeat keepalive
+	HeartbeatsSent   int64
+	HeartbeatAcks    int64
+	HeartbeatsMissed int64
+	UnhealthyConns   int64
+	// Fragmented-write robustness tracking
+	FragmentedSubmitted int64
+	FragmentedAccepted  int64
+	FragmentedErrors    int64
+	// Cross-account matching and settlement verification
+	CrossPairsSubmitted    int64
+	CrossPairsAccepted     int64
+	CrossPositionsVerified int64
+	CrossPositionsMismatch int64
+	CrossVerifyErrors      int64
+	// Latency tracking (in nanoseconds), guarded by statsMutex
 	SignupLatencies []time.Duration
 	LoginLatencies  []time.Duration
-	OrderLatencies  []time.Duration
-	// Live latency stats
-	MinOrderLatency time.Duration
-	MaxOrderLatency time.Duration
-	AvgOrderLatency time.Duration
+	// Engine dial, TLS handshake and authentication, per connection
+	ConnectLatencies []time.Duration
+	// Orders answered on each connection, per user, with -conns-per-user
+	ConnOrders []int64
+
+	// Order latencies and breakdowns are recorded into shards without
+	// statsMutex and are filled in only on copies returned by snapshot
+	OrderLatencies      LatencyRecorder
+	FragmentedLatencies LatencyRecorder
+	// Service time only, recorded when coordinated-omission correction is on
+	UncorrectedLatencies LatencyRecorder
+	// Successful -query-pct portfolio reads
+	QueryLatencies LatencyRecorder
+	// Marketable orders from submit to their first execution report
+	SubmitToFillLatencies LatencyRecorder
+	// Rejections keyed by engine reject code (protocol v2+)
+	RejectCodes map[uint16]int64
+	// Rejections keyed by normalized message (see rejectReason)
+	RejectReasons map[string]int64
+	// Per-symbol breakdown
+	Symbols map[string]*SymbolStats
+	// Orders per -timestamp-skew bucket
+	Skews map[time.Duration]*SkewStats
+	// Per-engine breakdown
+	Engines map[string]*EngineStats
+
+	shards []*statsShard
 }
 
-var stats StressStats
+// Per-symbol order stats. Latencies is always a reservoir, whatever
+// -histogram selects: an HdrHistogram per symbol in every shard would cost
+// hundreds of kilobytes each, and the per-symbol table shows only mean/p99.
+type SymbolStats struct {
+	OrdersSubmitted int64
+	OrdersAccepted  int64
+	Latencies       LatencyRecorder
+}
+
+// newStressStats returns empty stats whose shards record latencies of the
+// -histogram kind
+func newStressStats() StressStats {
+	return StressStats{shards: newStatsShards()}
+}
+
+var stats = newStressStats()
 var statsMutex sync.Mutex
 
 // Helper function to calculate average latency
@@ -136,54 +313,153 @@ func averageLatency(latencies []time.Duration) time.Duration {
 	return sum / time.Duration(len(latencies))
 }
 
-// Live status reporter
-func startLiveReporter(config StressConfig, startTime time.Time, ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+// percentile returns the q-quantile (0..1) of latencies using linear
+// interpolation between closest ranks. It sorts a copy so the caller's slice
+// is never reordered.
+func percentile(latencies []time.Duration, q float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return percentileSorted(sorted, q)
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			statsMutex.Lock()
-			currentStats := stats
-			statsMutex.Unlock()
-
-			elapsed := time.Since(startTime)
-			usersCreated := atomic.LoadInt64(&currentStats.UsersCreated)
-			usersLoggedIn := atomic.LoadInt64(&currentStats.UsersLoggedIn)
-			ordersSubmitted := atomic.LoadInt64(&currentStats.OrdersSubmitted)
-			ordersAccepted := atomic.LoadInt64(&currentStats.OrdersAccepted)
-			errors := atomic.LoadInt64(&currentStats.Errors)
-
-			avgSignup := averageLatency(currentStats.SignupLatencies)
-			avgLogin := averageLatency(currentStats.LoginLatencies)
-			_ = avgSignup // Keep for future use
-			_ = avgLogin  // Keep for future use
-
-			ordersPerSec := float64(ordersSubmitted) / elapsed.Seconds()
-
-			log.Printf("=== LIVE STATUS (%.1fs) ===", elapsed.Seconds())
-			log.Printf("Users: %d created, %d logged in", usersCreated, usersLoggedIn)
-			log.Printf("Orders: %d submitted, %d accepted (%.1f%%)", ordersSubmitted, ordersAccepted,
-				float64(ordersAccepted)/float64(ordersSubmitted)*100)
-			log.Printf("Throughput: %.1f orders/sec", ordersPerSec)
-			log.Printf("Errors: %d", errors)
-			log.Printf("Order Latencies - Min: %.2fms, Max: %.2fms, Avg: %.2fms",
-				float64(currentStats.MinOrderLatency.Nanoseconds())/1e6,
-				float64(currentStats.MaxOrderLatency.Nanoseconds())/1e6,
-				float64(currentStats.AvgOrderLatency.Nanoseconds())/1e6)
-			log.Printf("Progress: %d/%d users completed", usersLoggedIn, config.NumUsers)
-			log.Println("==========================")
-		}
+// percentileSorted is percentile for a slice that is already sorted
+func percentileSorted(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return sorted[0]
+	}
+	if q >= 1 {
+		return sorted[len(sorted)-1]
+	}
+	rank := q * float64(len(sorted)-1)
+	lo := int(rank)
+	hi := lo + 1
+	if hi >= len(sorted) {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + time.Duration(frac*float64(sorted[hi]-sorted[lo]))
+}
+
+// startLiveReporter logs a live status block at each time schedule yields
+// until ctx is cancelled. The final results are printed by the caller once
+// the run ends, whatever the schedule.
+func startLiveReporter(ctx context.Context, config StressConfig, startTime time.Time, schedule reportSchedule) {
+	if schedule == nil {
+		return
+	}
+	runReportSchedule(ctx, startTime, schedule, func() { logLiveStatus(config, startTime) })
+}
+
+// liveSnapshot holds the scalar values shown in one live status block
+type liveSnapshot struct {
+	UsersCreated    int64
+	UsersLoggedIn   int64
+	UsersCompleted  int64
+	OrdersSubmitted int64
+	OrdersAccepted  int64
+	OrdersInFlight  int64
+	Errors          int64
+	MinOrderLatency time.Duration
+	MaxOrderLatency time.Duration
+	AvgOrderLatency time.Duration
+	P50, P95, P99   time.Duration
+}
+
+// takeLiveSnapshot summarizes stats and copies out only scalars. Order
+// latencies are merged from the shards into a fresh recorder that no writer
+// can reach.
+func takeLiveSnapshot() liveSnapshot {
+	latencies := stats.mergeLatencies(func(sh *statsShard) LatencyRecorder { return sh.orderLatencies })
+	pcts := latencies.Percentiles(0.50, 0.95, 0.99)
+	return liveSnapshot{
+		UsersCreated:    atomic.LoadInt64(&stats.UsersCreated),
+		UsersLoggedIn:   atomic.LoadInt64(&stats.UsersLoggedIn),
+		UsersCompleted:  atomic.LoadInt64(&stats.UsersCompleted),
+		OrdersSubmitted: atomic.LoadInt64(&stats.OrdersSubmitted),
+		OrdersAccepted:  atomic.LoadInt64(&stats.OrdersAccepted),
+		OrdersInFlight:  atomic.LoadInt64(&ordersInFlight),
+		Errors:          atomic.LoadInt64(&stats.Errors),
+		MinOrderLatency: latencies.Min(),
+		MaxOrderLatency: latencies.Max(),
+		AvgOrderLatency: latencies.Mean(),
+		P50:             pcts[0],
+		P95:             pcts[1],
+		P99:             pcts[2],
+	}
+}
+
+// logLiveStatus prints one live status block
+func logLiveStatus(config StressConfig, startTime time.Time) {
+	snap := takeLiveSnapshot()
+
+	elapsed := time.Since(startTime)
+	measured := time.Since(measurementStart())
+	ordersPerSec := float64(snap.OrdersSubmitted) / measured.Seconds()
+
+	warmup := inWarmup()
+	if warmup {
+		ordersPerSec = float64(snap.OrdersSubmitted) / elapsed.Seconds()
+	}
+	acceptedPct := 0.0
+	if snap.OrdersSubmitted > 0 {
+		acceptedPct = float64(snap.OrdersAccepted) / float64(snap.OrdersSubmitted) * 100
+	}
+
+	attrs := []any{
+		"elapsed", elapsed.Round(100 * time.Millisecond),
+		"warmup", warmup,
+		"seed", runSeed,
+		"users_created", snap.UsersCreated,
+		"users_logged_in", snap.UsersLoggedIn,
+		"users_active", atomic.LoadInt64(&activeUsers),
+		"users_completed", snap.UsersCompleted,
+		"users_total", config.NumUsers,
+		"orders_submitted", snap.OrdersSubmitted,
+		"orders_accepted", snap.OrdersAccepted,
+		"orders_in_flight", snap.OrdersInFlight,
+		"accepted_pct", round1(acceptedPct),
+		"orders_per_sec", round1(ordersPerSec),
+	}
+	if config.Rate > 0 {
+		attrs = append(attrs, "target_rate", config.Rate)
+	}
+	if b := totalBudget; b != nil {
+		attrs = append(attrs, "budget_used", b.Used(), "budget_total", b.Limit())
 	}
+	attrs = append(attrs,
+		"errors", snap.Errors,
+		"latency_min", snap.MinOrderLatency,
+		"latency_avg", snap.AvgOrderLatency,
+		"latency_max", snap.MaxOrderLatency,
+		"latency_p50", snap.P50,
+		"latency_p95", snap.P95,
+		"latency_p99", snap.P99,
+	)
+	slog.Info("live status", attrs...)
+}
+
+// userEmail returns the account email of user userNum in this run
+func userEmail(userNum int) string {
+	return fmt.Sprintf("stress-%s-%d@example.com", runNamespace(), userNum)
 }
 
 // HTTP client for frontend
-func createUser(frontendURL string, userNum int) (string, string, error) {
-	email := fmt.Sprintf("stress%d_%d@example.com", userNum, time.Now().UnixNano())
-	password := "TestPass123!"
+func createUser(frontendURL string, userNum int) (email, password string, err error) {
+	defer func() {
+		if err != nil {
+			recordError(classifyError(err, ErrCategorySignup))
+		}
+	}()
+
+	email = userEmail(userNum)
+	password = "TestPass123!"
 
 	signupReq := SignupRequest{
 		Email:         email,
@@ -199,9 +475,7 @@ func createUser(frontendURL string, userNum int) (string, string, error) {
 		return "", "", fmt.Errorf("failed to marshal signup request: %w", err)
 	}
 
-	start := time.Now()
-	resp, err := http.Post(frontendURL+"/api/auth/stress-signup", "application/json", bytes.NewBuffer(jsonData))
-	latency := time.Since(start)
+	resp, latency, err := postFrontend(frontendURL+"/api/auth/stress-signup", jsonData)
 
 	if err != nil {
 		return "", "", fmt.Errorf("signup request failed: %w", err)
@@ -209,19 +483,34 @@ func createUser(frontendURL string, userNum int) (string, string, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return "", "", fmt.Errorf("signup failed with status %d: %s", resp.StatusCode, string(body))
+		fe := newFrontendError("signup", resp)
+		// A rerun may sign up an account an earlier run created; the
+		// password is fixed, so logging in still works
+		if fe.userExists() {
+			slog.Debug("user already exists, logging in", "user_id", userNum, "email", email)
+			atomic.AddInt64(&stats.UsersExisting, 1)
+			return email, password, nil
+		}
+		return "", "", fe
 	}
 
 	statsMutex.Lock()
 	stats.SignupLatencies = append(stats.SignupLatencies, latency)
 	atomic.AddInt64(&stats.UsersCreated, 1)
 	statsMutex.Unlock()
+	usersCreatedTotal.Inc()
 
 	return email, password, nil
 }
 
-func loginUser(frontendURL, email, password string) (string, error) {
+// loginUser logs in and returns the session and trading tokens
+func loginUser(frontendURL, email, password string) (_ AuthTokens, err error) {
+	defer func() {
+		if err != nil {
+			recordError(classifyError(err, ErrCategoryLogin))
+		}
+	}()
+
 	loginReq := LoginRequest{
 		Email:    email,
 		Password: password,
@@ -229,231 +518,301 @@ func loginUser(frontendURL, email, password string) (string, error) {
 
 	jsonData, err := json.Marshal(loginReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal login request: %w", err)
+		return AuthTokens{}, fmt.Errorf("failed to marshal login request: %w", err)
 	}
 
-	start := time.Now()
-	resp, err := http.Post(frontendURL+"/api/auth/login", "application/json", bytes.NewBuffer(jsonData))
-	latency := time.Since(start)
+	resp, latency, err := postFrontend(frontendURL+"/api/auth/login", jsonData)
 
 	if err != nil {
-		return "", fmt.Errorf("login request failed: %w", err)
+		return AuthTokens{}, fmt.Errorf("login request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("login failed with status %d: %s", resp.StatusCode, string(body))
+		return AuthTokens{}, newFrontendError("login", resp)
 	}
 
-	var authResp AuthResponse
-	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
-		return "", fmt.Errorf("failed to decode login response: %w", err)
-	}
-
-	// Validate that we got a trading token
-	if authResp.Tokens.TradingToken == "" {
-		return "", fmt.Errorf("empty trading token received from login")
+	tokens, err := decodeAuthResponse(resp.Body)
+	if err != nil {
+		return AuthTokens{}, err
 	}
 
 	statsMutex.Lock()
 	stats.LoginLatencies = append(stats.LoginLatencies, latency)
 	atomic.AddInt64(&stats.UsersLoggedIn, 1)
 	statsMutex.Unlock()
+	usersLoggedInTotal.Inc()
 
-	log.Printf("User %s logged in successfully with token: %s...", email, authResp.Tokens.TradingToken[:20])
-	return authResp.Tokens.TradingToken, nil
+	slog.Debug("user logged in", "email", email, "token", tokenPreview(tokens.TradingToken)+"...", "latency", latency)
+	return tokens, nil
 }
 
-// authenticateTCP handles the login handshake for TCP connections.
-func authenticateTCP(conn net.Conn, token string) error {
-	// Prepare the login request message
-	tokenBytes := []byte(token)
-	bodyLen := 1 + 4 + len(tokenBytes) // type(1) + token_len(4) + token
-	totalLen := 4 + bodyLen            // message_length(4) + body
-
-	buf := new(bytes.Buffer)
-	// Write message length (total message size including this field)
-	binary.Write(buf, binary.BigEndian, uint32(totalLen))
-	// Write message type
-	buf.WriteByte(byte(MessageTypeLoginRequest))
-	// Write token length
-	binary.Write(buf, binary.BigEndian, uint32(len(tokenBytes)))
-	// Write token
-	buf.Write(tokenBytes)
+// tokenPreview returns at most the first 20 characters of a token for logging
+func tokenPreview(token string) string {
+	const previewLen = 20
+	if len(token) < previewLen {
+		return token
+	}
+	return token[:previewLen]
+}
 
+// authenticateTCP handles the login handshake for TCP connections.
+func authenticateTCP(ctx context.Context, conn net.Conn, token string) error {
 	// Send the request
-	if _, err := conn.Write(buf.Bytes()); err != nil {
+	if _, err := conn.Write(protocol.EncodeLoginRequest(token, uint8(protocolVersion))); err != nil {
 		return fmt.Errorf("failed to send login request: %w", err)
 	}
 
-	// Read the response header: message_length(4)
-	var messageLength uint32
-	if err := binary.Read(conn, binary.BigEndian, &messageLength); err != nil {
-		return fmt.Errorf("failed to read login response length: %w", err)
+	resp, err := protocol.DecodeLoginResponse(ctxReader{ctx, conn})
+	if err != nil {
+		return fmt.Errorf("failed to read login response: %w", err)
 	}
 
-	// Read the response body (excluding the 4-byte length we already read)
-	bodySize := messageLength - 4
-	respBody := make([]byte, bodySize)
-	if _, err := io.ReadFull(conn, respBody); err != nil {
-		return fmt.Errorf("failed to read login response body: %w", err)
+	// Check for success
+	if !resp.Success {
+		return fmt.Errorf("authentication failed: %s", resp.Message)
 	}
 
-	// Parse response: type(1) + success(1) + message_len(4) + message
-	if len(respBody) < 6 {
-		return fmt.Errorf("login response too short: %d bytes", len(respBody))
+	// An engine must answer with the version asked for or an older one,
+	// whose frames are a prefix of ours; anything newer would be misread
+	version := int(resp.Version)
+	if version == 0 {
+		version = ProtocolVersionBase
+	}
+	if version > protocolVersion {
+		return fmt.Errorf("%w: engine answered with version %d, but this client asked for %d (see -protocol-version)",
+			errVersionMismatch, version, protocolVersion)
+	}
+	if version < minServerVersion {
+		return fmt.Errorf("%w: engine speaks version %d, older than -min-server-version %d",
+			errVersionMismatch, version, minServerVersion)
 	}
 
-	msgType := respBody[0]
-	success := respBody[1]
-	messageLen := binary.BigEndian.Uint32(respBody[2:6])
+	slog.Debug("engine authentication succeeded", "message", resp.Message, "protocol_version", version)
+	return nil
+}
 
-	if msgType != MessageTypeLoginResponse {
-		return fmt.Errorf("unexpected response type: %d", msgType)
-	}
+// fragmentConfig controls splitting an order frame across several writes
+type fragmentConfig struct {
+	Size  int
+	Delay time.Duration
+}
 
-	// Read message text if present
-	var message string
-	if messageLen > 0 && len(respBody) >= 6+int(messageLen) {
-		message = string(respBody[6 : 6+messageLen])
+// writeFragmented writes data in chunks of at most size bytes, pausing
+// between chunks so the engine sees the frame arrive across multiple reads.
+func writeFragmented(conn net.Conn, data []byte, frag *fragmentConfig) error {
+	size := frag.Size
+	if size <= 0 {
+		size = 1
 	}
-
-	// Check for success
-	if success != 1 {
-		return fmt.Errorf("authentication failed: %s", message)
+	for off := 0; off < len(data); off += size {
+		end := off + size
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := conn.Write(data[off:end]); err != nil {
+			return err
+		}
+		if end < len(data) && frag.Delay > 0 {
+			time.Sleep(frag.Delay)
+		}
 	}
-
-	log.Printf("TCP authentication successful: %s", message)
 	return nil
 }
 
-// Helper to convert double to network byte order (as uint64)
-func doubleToNetworkBytes(val float64) uint64 {
-	bits := *(*uint64)(unsafe.Pointer(&val))
-	// Convert to big endian
-	return uint64(binary.BigEndian.Uint64((*[8]byte)(unsafe.Pointer(&bits))[:]))
+// readOrderResponse reads the next order response, skipping any late
+// heartbeat acks left on the stream by a timed-out heartbeat. The response
+// must echo orderID; one for another order means the stream is out of step,
+// and the error makes the caller discard the connection. Responses with no
+// order ID, such as the engine's "Not authenticated" reject, are accepted.
+func readOrderResponse(r io.Reader, orderID string) (protocol.OrderResponse, error) {
+	for {
+		body, err := protocol.ReadFrame(r)
+		if err != nil {
+			return protocol.OrderResponse{}, err
+		}
+		if len(body) > 0 && body[0] == protocol.MessageTypeHeartbeatAck {
+			continue
+		}
+		resp, err := protocol.ParseOrderResponse(body)
+		if err != nil {
+			return resp, fmt.Errorf("%w: %v", errMalformedResponse, err)
+		}
+		if resp.OrderID != "" && resp.OrderID != orderID {
+			return resp, fmt.Errorf("%w: sent %q, got %q", errCorrelation, orderID, resp.OrderID)
+		}
+		return resp, nil
+	}
 }
 
-// Submit order via TCP binary protocol
-func submitOrderTCP(conn net.Conn, userID, symbol string, side, orderType int, quantity int64, price float64) error {
-	orderId := fmt.Sprintf("order_%d_%d", time.Now().UnixNano(), rand.Int())
-
-	buf := &bytes.Buffer{}
-
-	// Prepare binary order request
-	orderIdBytes := []byte(orderId)
-	userIdBytes := []byte(userID)
-	symbolBytes := []byte(symbol)
-
-	// Calculate total length: message_length(4) + type(1) + order_id_len(4) + user_id_len(4) +
-	// symbol_len(4) + side(1) + order_type(1) + quantity(8) + price(8) + timestamp_ms(8) + strings
-	bodyLen := 1 + 4 + 4 + 4 + 1 + 1 + 8 + 8 + 8 + len(orderIdBytes) + len(userIdBytes) + len(symbolBytes)
-	totalLen := 4 + bodyLen
-
-	// Write message length
-	binary.Write(buf, binary.BigEndian, uint32(totalLen))
-	// Write message type
-	buf.WriteByte(MessageTypeSubmitOrder)
-	// Write string lengths
-	binary.Write(buf, binary.BigEndian, uint32(len(orderIdBytes)))
-	binary.Write(buf, binary.BigEndian, uint32(len(userIdBytes)))
-	binary.Write(buf, binary.BigEndian, uint32(len(symbolBytes)))
-	// Write order parameters
-	buf.WriteByte(uint8(side))
-	buf.WriteByte(uint8(orderType))
-	binary.Write(buf, binary.BigEndian, uint64(quantity))
-
-	// Write price as double in network byte order
-	priceBits := *(*uint64)(unsafe.Pointer(&price))
-	binary.Write(buf, binary.BigEndian, priceBits)
-
-	// Write timestamp
-	binary.Write(buf, binary.BigEndian, uint64(time.Now().UnixMilli()))
-	// Write strings
-	buf.Write(orderIdBytes)
-	buf.Write(userIdBytes)
-	buf.Write(symbolBytes)
+// submitOptions carries optional per-order behavior for submitOrderTCP
+type submitOptions struct {
+	// Frag sends the frame in fragments and tags the result separately
+	Frag *fragmentConfig
+	// OrderID is the client order ID to send; one is generated when empty
+	OrderID string
+	// Intended is the scheduled dispatch time. When set, latency is measured
+	// from it rather than from the write, correcting for coordinated omission.
+	Intended time.Time
+	// Shard selects the stats shard the result is recorded in; workers pass
+	// their user ID
+	Shard int
+	// TimestampSkew is subtracted from the order's timestamp_ms; negative
+	// values future-date it. With SkewTracked the outcome is counted under
+	// its skew bucket.
+	TimestampSkew time.Duration
+	SkewTracked   bool
+}
 
-	start := time.Now()
-	if _, err := conn.Write(buf.Bytes()); err != nil {
-		atomic.AddInt64(&stats.Errors, 1)
-		return fmt.Errorf("TCP write failed: %w", err)
+// Submit order via TCP binary protocol and return the engine's response,
+// including the order ID it assigned.
+func submitOrderTCP(ctx context.Context, conn net.Conn, userID, symbol string, side, orderType int, quantity int64, price float64, opts submitOptions) (resp protocol.OrderResponse, err error) {
+	orderID := opts.OrderID
+	if orderID == "" {
+		orderID = newOrderID()
+	}
+	// An order the engine could only reject never reaches the network
+	frame, err := protocol.EncodeSubmitOrder(protocol.Order{
+		OrderID:     orderID,
+		UserID:      userID,
+		Symbol:      symbol,
+		Side:        uint8(side),
+		Type:        uint8(orderType),
+		Quantity:    quantity,
+		Price:       price,
+		TimestampMs: time.Now().Add(-opts.TimestampSkew).UnixMilli(),
+	})
+	if err != nil {
+		recordError(ErrCategoryInvalidOrder)
+		return protocol.OrderResponse{}, err
 	}
 
-	// Read response: message_length(4)
-	var messageLength uint32
-	if err := binary.Read(conn, binary.BigEndian, &messageLength); err != nil {
-		atomic.AddInt64(&stats.Errors, 1)
-		return fmt.Errorf("TCP read response length failed: %w", err)
+	frag := opts.Frag
+	fragmented := frag != nil
+	if fragmented {
+		defer func() {
+			if err != nil {
+				atomic.AddInt64(&stats.FragmentedErrors, 1)
+			}
+		}()
 	}
 
-	// Read response body (excluding the 4-byte length we already read)
-	bodySize := messageLength - 4
-	respBody := make([]byte, bodySize)
-	if _, err := io.ReadFull(conn, respBody); err != nil {
-		atomic.AddInt64(&stats.Errors, 1)
-		return fmt.Errorf("TCP read response body failed: %w", err)
+	start := time.Now()
+	var latency time.Duration
+	if orderLog != nil {
+		defer func() {
+			if err != nil {
+				latency = time.Since(start)
+			}
+			orderLog.Record(orderRecord{
+				Time: start, UserID: userID, Symbol: symbol, Side: side, Type: orderType,
+				Quantity: quantity, Price: price, Accepted: resp.Accepted, Latency: latency, Err: err,
+			})
+		}()
 	}
 
-	// Parse response: type(1) + order_id_len(4) + accepted(1) + message_len(4) + order_id + message
-	if len(respBody) < 10 {
-		atomic.AddInt64(&stats.Errors, 1)
-		return fmt.Errorf("order response too short: %d bytes", len(respBody))
+	// Track the fill before writing: its execution report can arrive
+	// ahead of the order response
+	if marketable(orderType) {
+		pendingFills.Track(orderID, opts.Shard, start)
+		defer func() {
+			if err != nil || !resp.Accepted {
+				pendingFills.Forget(orderID)
+			}
+		}()
 	}
 
-	msgType := respBody[0]
-	orderIdLen := binary.BigEndian.Uint32(respBody[1:5])
-	accepted := respBody[5]
-	messageLen := binary.BigEndian.Uint32(respBody[6:10])
+	route, routed := routeOrderResponse(conn, orderID)
+	if routed {
+		defer route.Close()
+	}
+	if fragmented {
+		err = writeFragmented(conn, frame, frag)
+	} else {
+		_, err = conn.Write(frame)
+	}
+	if err != nil {
+		recordError(classifyError(err, ErrCategoryWrite))
+		return protocol.OrderResponse{}, fmt.Errorf("TCP write failed: %w", err)
+	}
+
+	if routed {
+		resp, err = route.Await(ctx, 0)
+	} else {
+		resp, err = readOrderResponse(ctxReader{ctx, conn}, orderID)
+	}
+	if err != nil {
+		recordError(classifyError(err, ErrCategoryRead))
+		return protocol.OrderResponse{}, fmt.Errorf("TCP read order response failed: %w", err)
+	}
 
-	latency := time.Since(start)
+	end := time.Now()
+	latency = end.Sub(start)
+	serviceLatency := latency
+	corrected := !opts.Intended.IsZero()
+	if corrected {
+		latency = end.Sub(opts.Intended)
+	}
 
-	if msgType != MessageTypeOrderResponse {
-		atomic.AddInt64(&stats.Errors, 1)
-		return fmt.Errorf("unexpected response type: %d", msgType)
+	// Extract reject code (v2+): reject_code(2) follows the message
+	var rejectCode uint16
+	hasRejectCode := false
+	if protocolVersion >= ProtocolVersionRejectCodes {
+		rejectCode, hasRejectCode = resp.RejectCode()
+	}
+	// The engine's clock (v3+) against our write and read times
+	if protocolVersion >= ProtocolVersionServerTime {
+		if at, ok := resp.ServerTime(); ok {
+			clockSkews.Add(conn.RemoteAddr().String(), start, at, end)
+		}
 	}
 
-	// Extract message if present
-	var message string
-	offset := 10 + int(orderIdLen)
-	if len(respBody) >= offset+int(messageLen) {
-		message = string(respBody[offset : offset+int(messageLen)])
+	ordersSubmittedTotal.Inc()
+	orderLatencySeconds.Observe(latency.Seconds())
+	if resp.Accepted {
+		ordersAcceptedTotal.Inc()
 	}
 
-	// Update stats
-	statsMutex.Lock()
-	stats.OrderLatencies = append(stats.OrderLatencies, latency)
+	// Update stats. Only the user's own shard is locked.
 	atomic.AddInt64(&stats.OrdersSubmitted, 1)
-
-	if accepted == 1 {
+	if resp.Accepted {
 		atomic.AddInt64(&stats.OrdersAccepted, 1)
-	} else {
-		// Log rejection for debugging
-		if rand.Intn(100) < 5 { // Log 5% of rejections to avoid spam
-			log.Printf("Order rejected: %s", message)
+	}
+	if fragmented {
+		atomic.AddInt64(&stats.FragmentedSubmitted, 1)
+		if resp.Accepted {
+			atomic.AddInt64(&stats.FragmentedAccepted, 1)
 		}
 	}
-
-	// Update live latency stats
-	if stats.MinOrderLatency == 0 || latency < stats.MinOrderLatency {
-		stats.MinOrderLatency = latency
+	var reason string
+	if !resp.Accepted {
+		reason = rejectReason(resp.Message, rejectCode, hasRejectCode)
 	}
-	if latency > stats.MaxOrderLatency {
-		stats.MaxOrderLatency = latency
+	duplicate := stats.shard(opts.Shard).recordOrder(orderOutcome{
+		OrderID:        resp.OrderID,
+		Symbol:         symbol,
+		Latency:        latency,
+		Accepted:       resp.Accepted,
+		ServiceLatency: serviceLatency,
+		Corrected:      corrected,
+		Fragmented:     fragmented,
+		RejectCode:     rejectCode,
+		HasRejectCode:  hasRejectCode,
+		RejectReason:   reason,
+		Skew:           opts.TimestampSkew,
+		SkewTracked:    opts.SkewTracked,
+	})
+
+	if duplicate {
+		// The engine accepted this order ID before: an idempotency bug
+		recordError(ErrCategoryDuplicateAck)
+		slog.Warn("engine accepted an order ID twice", "user_id", userID, "symbol", symbol, "order_id", resp.OrderID)
 	}
-	// Calculate running average
-	totalOrders := len(stats.OrderLatencies)
-	if totalOrders > 0 {
-		var sum time.Duration
-		for _, lat := range stats.OrderLatencies {
-			sum += lat
-		}
-		stats.AvgOrderLatency = sum / time.Duration(totalOrders)
+	if !resp.Accepted {
+		slog.Debug("order rejected", "user_id", userID, "symbol", symbol, "latency", latency, "message", resp.Message)
 	}
-	statsMutex.Unlock()
 
-	return nil
+	return resp, nil
 }
 
 // Worker function for each user (legacy, without context)
@@ -461,80 +820,125 @@ func userWorker(config StressConfig, userID int, wg *sync.WaitGroup) {
 	userWorkerWithContext(context.Background(), config, userID, wg)
 }
 
-// Worker function for each user with context support
-func userWorkerWithContext(ctx context.Context, config StressConfig, userID int, wg *sync.WaitGroup) {
-	defer wg.Done()
+// signupAndLogin creates the user on the frontend and logs in, returning
+// false if either step fails or ctx is cancelled. Dry runs skip the frontend.
+func signupAndLogin(ctx context.Context, config StressConfig, userID int) (AuthTokens, bool) {
+	if dryRun {
+		return AuthTokens{TradingToken: dryRunToken}, ctx.Err() == nil
+	}
+	if tokenList != nil {
+		tokens, err := tokenList.For(userID)
+		if err != nil {
+			slog.Warn("no trading token for user", "user_id", userID, "err", err)
+			recordError(ErrCategoryConfig)
+			return AuthTokens{}, false
+		}
+		return tokens, ctx.Err() == nil
+	}
 
-	// Check if already cancelled
+	// Create user, waiting for a -signup-concurrency slot
+	var email, password string
+	err := withSignupSlot(ctx, func() (err error) {
+		email, password, err = createUser(config.FrontendURL, userID)
+		return err
+	})
+	if err != nil {
+		slog.Warn("failed to create user", "user_id", userID, "err", err)
+		return AuthTokens{}, false
+	}
+
+	// Check cancellation
 	select {
 	case <-ctx.Done():
-		return
+		return AuthTokens{}, false
 	default:
 	}
 
-	// Create user
-	email, password, err := createUser(config.FrontendURL, userID)
+	// Login to get trading token
+	var tokens AuthTokens
+	err = withSignupSlot(ctx, func() (err error) {
+		tokens, err = loginUser(config.FrontendURL, email, password)
+		return err
+	})
 	if err != nil {
-		log.Printf("Failed to create user %d: %v", userID, err)
-		atomic.AddInt64(&stats.Errors, 1)
-		return
+		slog.Warn("failed to log in user", "user_id", userID, "err", err)
+		return AuthTokens{}, false
 	}
 
-	// Check cancellation
+	return tokens, true
+}
+
+// Worker function for each user with context support
+func userWorkerWithContext(ctx context.Context, config StressConfig, userID int, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer recoverWorkerPanic(userID)
+
+	// Check if already cancelled
 	select {
 	case <-ctx.Done():
 		return
 	default:
 	}
 
-	// Login to get trading token
-	tradingToken, err := loginUser(config.FrontendURL, email, password)
-	if err != nil {
-		log.Printf("Failed to login user %d: %v", userID, err)
-		atomic.AddInt64(&stats.Errors, 1)
+	tokens, ok := signupAndLogin(ctx, config, userID)
+	if !ok {
 		return
 	}
 
-	log.Printf("User %d authenticated successfully", userID)
+	slog.Debug("user authenticated", "user_id", userID)
 
 	// Check cancellation
 	select {
 	case <-ctx.Done():
-		log.Printf("User %d: Cancelled before TCP connection", userID)
+		slog.Debug("user cancelled before connecting", "user_id", userID)
 		return
 	default:
 	}
 
-	// Connect to engine via TCP with TLS
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: true, // Skip certificate verification for testing
-	}
-
-	conn, err := tls.Dial("tcp", config.EngineAddr, tlsConfig)
+	// Connections are dialed and authenticated on demand; a connection that
+	// fails is discarded and replaced on the next Get. With several engines
+	// each gets its own pool and orders are routed by -shard-by.
+	addrs, err := parseEngineAddrs(config.EngineAddr)
 	if err != nil {
-		log.Printf("Failed to connect to TLS TCP server: %v", err)
-		atomic.AddInt64(&stats.Errors, 1)
+		slog.Error("invalid engine list", "user_id", userID, "err", err)
+		recordError(ErrCategoryConfig)
 		return
 	}
+	// Orders in flight when ctx is cancelled are read until the drain ends
+	orderCtx, cancelOrders := drainContext(ctx, config.DrainTimeout)
+	defer cancelOrders()
+
+	engines := newEnginePools(addrs, config.ShardBy, userID, config.ConnsPerUser, func(addr string) (net.Conn, error) {
+		return dialEngine(orderCtx, addr, tokens.TradingToken)
+	})
 	defer func() {
-		conn.Close()
-		log.Printf("User %d: Connection closed", userID)
+		engines.Close()
+		slog.Debug("user connections closed", "user_id", userID)
+		if config.ConnsPerUser > 1 {
+			recordConnOrders(engines.OrderCounts())
+		}
 	}()
 
-	// Authenticate TCP connection with trading token
-	if err := authenticateTCP(conn, tradingToken); err != nil {
-		log.Printf("Failed to authenticate TCP connection for user %d: %v", userID, err)
-		atomic.AddInt64(&stats.Errors, 1)
+	// Connect up front so a user that cannot reach an engine fails fast
+	if err := connectAll(ctx, engines.Used()); err != nil {
+		slog.Warn("failed to open engine connection", "user_id", userID, "err", err)
 		return
 	}
 
+	gen, err := newOrderGenerator(config, workerRand(userID))
+	if err != nil {
+		slog.Error("invalid order generator config", "user_id", userID, "err", err)
+		recordError(ErrCategoryConfig)
+		return
+	}
+	if config.Profile != "" {
+		slog.Debug("user trading profile", "user_id", userID, "archetype", gen.Archetype())
+	}
+
 	// Submit orders concurrently
 	var orderWg sync.WaitGroup
 	orderSem := make(chan struct{}, config.OrderConcurrency)
 
-	// Use a mutex to serialize TCP writes on the same connection
-	var connMutex sync.Mutex
-
 	// Track if we should stop
 	stopOrders := make(chan struct{})
 
@@ -544,15 +948,85 @@ func userWorkerWithContext(ctx context.Context, config StressConfig, userID int,
 		close(stopOrders)
 	}()
 
+	// Accepted order IDs available for -cancel-pct and -modify-pct, kept
+	// per engine so a cancel or modify goes where its order was accepted
+	recent := make([]recentOrders, len(addrs))
+
+	// Keep the connections alive with periodic heartbeats
+	health := &connHealth{}
+	if config.Heartbeat > 0 {
+		hbCtx, hbCancel := context.WithCancel(ctx)
+		defer hbCancel()
+		for _, pool := range engines.Used() {
+			go runHeartbeat(hbCtx, pool, config.Heartbeat, config.HeartbeatMisses, health)
+		}
+	}
+
+	// One user samples the book so the log stays readable. It queries on
+	// connections of its own, so a query waiting out its timeout never
+	// holds a connection the user's orders need.
+	if config.VerifyBook > 0 && userID == 1 {
+		book := newEnginePools(addrs, config.ShardBy, userID, 1, func(addr string) (net.Conn, error) {
+			return dialEngine(orderCtx, addr, tokens.TradingToken)
+		})
+		defer book.Close()
+		bookCtx, bookCancel := context.WithCancel(ctx)
+		defer bookCancel()
+		go runBookVerifier(bookCtx, book, config.Symbols, config.VerifyBook)
+	}
+
+	// With coordinated-omission correction, order i is scheduled at
+	// scheduleStart + i/TargetRate and its latency is measured from then,
+	// so time spent queued behind a slow response is counted.
+	var interval time.Duration
+	var scheduleStart time.Time
+	if config.CorrectOmission && config.TargetRate > 0 {
+		interval = time.Duration(float64(time.Second) / config.TargetRate)
+		scheduleStart = time.Now()
+	}
+
+	// In soak mode users ignore OrdersPerUser and stop only when ctx is
+	// cancelled, by -duration or a signal, or -total-orders is spent
+	orders := ordersForUser(config, userID)
 orderLoop:
-	for i := 0; i < config.OrdersPerUser; i++ {
+	for i := 0; config.Soak || i < orders; i++ {
 		// Check if we should stop
 		select {
 		case <-stopOrders:
-			log.Printf("User %d: Stopping order submission (cancelled)", userID)
+			slog.Debug("user stopping order submission", "user_id", userID, "reason", "cancelled")
 			break orderLoop
 		default:
 		}
+		if health.Unhealthy() {
+			slog.Warn("user stopping order submission", "user_id", userID, "reason", "connection unhealthy")
+			break orderLoop
+		}
+		if !totalBudget.Take() {
+			slog.Debug("user stopping order submission", "user_id", userID, "reason", "total-orders spent")
+			break orderLoop
+		}
+
+		var intended time.Time
+		if interval > 0 {
+			intended = scheduleStart.Add(time.Duration(i) * interval)
+			if wait := time.Until(intended); wait > 0 {
+				select {
+				case <-stopOrders:
+					break orderLoop
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		// Draw parameters here, in order, so a seeded run is reproducible
+		params := gen.Next()
+		if params.Pause > 0 {
+			select {
+			case <-stopOrders:
+				break orderLoop
+			case <-time.After(params.Pause):
+			}
+		}
 
 		orderWg.Add(1)
 		orderSem <- struct{}{} // Acquire
@@ -562,6 +1036,7 @@ orderLoop:
 				<-orderSem // Release
 				orderWg.Done()
 			}()
+			defer recoverWorkerPanic(userID, "order", orderNum, "symbol", params.Symbol, "side", params.Side, "type", params.Type)
 
 			// Check cancellation before submitting
 			select {
@@ -570,28 +1045,107 @@ orderLoop:
 			default:
 			}
 
-			symbol := config.Symbols[rand.Intn(len(config.Symbols))]
-			side := rand.Intn(2)      // Buy or Sell
-			orderType := rand.Intn(2) // Market or Limit
-			quantity := int64(rand.Intn(100) + 1)
-			price := 100.0 + rand.Float64()*100.0
+			// Back off while the client CPU monitor reports saturation
+			if delay := atomic.LoadInt64(&sendBackoff); delay > 0 {
+				time.Sleep(time.Duration(delay))
+			}
 
-			// Lock the connection for this order submission
-			connMutex.Lock()
-			err := submitOrderTCP(conn, fmt.Sprintf("user_%d", userID), symbol, side, orderType, quantity, price)
-			connMutex.Unlock()
+			opts := submitOptions{Intended: intended, Shard: userID, TimestampSkew: params.Skew, SkewTracked: params.SkewTracked}
+			if params.Fragment {
+				opts.Frag = &fragmentConfig{Size: config.FragmentSize, Delay: config.FragmentDelay}
+			}
 
+			// Read the portfolio instead of trading; -rate limits orders only
+			if params.Query {
+				if err := queryPortfolio(config.FrontendURL, tokens.SessionToken, userID); err != nil {
+					select {
+					case <-stopOrders:
+					default:
+						slog.Warn("portfolio query failed", "user_id", userID, "err", err)
+					}
+				}
+				return
+			}
+
+			// Respect the aggregate -rate limit
+			if err := waitForOrderToken(ctx); err != nil {
+				return
+			}
+
+			engine := engines.Route(params.Symbol)
+			slot := engines.Next(engine)
+			pool := engines.pools[slot]
+
+			// Mix in cancels of this user's recently accepted orders
+			if params.Cancel {
+				if orderID, ok := recent[engine].Pop(); ok {
+					err := withRetry(ctx, pool, config.MaxRetries, func(conn net.Conn) error {
+						_, err := submitCancelTCP(conn, orderID)
+						return err
+					})
+					if errors.Is(err, errUnanswered) {
+						// Not sent, so the order can still be modified or cancelled
+						recent[engine].Push(orderID)
+					} else if err != nil {
+						select {
+						case <-stopOrders:
+						default:
+							slog.Warn("cancel failed", "user_id", userID, "order_id", orderID, "err", err)
+						}
+					}
+					return
+				}
+			}
+
+			// Amend the most recent accepted order to this slot's size and price
+			if params.Modify {
+				if orderID, ok := recent[engine].Peek(); ok {
+					err := withRetry(ctx, pool, config.MaxRetries, func(conn net.Conn) error {
+						_, err := modifyOrderTCP(conn, orderID, params.Quantity, params.Price)
+						return err
+					})
+					if err != nil && !errors.Is(err, errUnanswered) {
+						select {
+						case <-stopOrders:
+						default:
+							slog.Warn("modify failed", "user_id", userID, "order_id", orderID, "err", err)
+						}
+					}
+					return
+				}
+			}
+
+			// Retries reuse the order ID so the engine can recognize a resend.
+			// Holding the connection serializes each attempt's write and read.
+			opts.OrderID = newOrderID()
+			var resp protocol.OrderResponse
+			err := withRetry(ctx, pool, config.MaxRetries, func(conn net.Conn) (err error) {
+				resp, err = submitOrder(orderCtx, conn, userIDString(userID), params.Symbol, params.Side, params.Type, params.Quantity, params.Price, opts)
+				return err
+			})
+			if err == nil {
+				engines.CountOrder(slot)
+				stats.shard(userID).recordEngine(addrs[engine], resp.Accepted)
+				if resp.Accepted && resp.OrderID != "" {
+					recent[engine].Push(resp.OrderID)
+				}
+			}
 			if err != nil {
 				// Don't log errors if we're shutting down
 				select {
 				case <-stopOrders:
 					return
 				default:
-					log.Printf("Order submission failed for user %d: %v", userID, err)
+					slog.Warn("order submission failed", "user_id", userID, "symbol", params.Symbol, "err", err)
 				}
 			}
 		}(i)
 	}
+	// A user stopped by cancellation or an unhealthy connection did not finish
+	finished := ctx.Err() == nil && !health.Unhealthy()
 
 	orderWg.Wait()
+	if finished {
+		atomic.AddInt64(&stats.UsersCompleted, 1)
+	}
 }
@@ -11,129 +11,452 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
-	"os/signal"
 	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
+
+	"stress_client/protocol"
 )
 
 func main() {
 	config := StressConfig{}
 
 	flag.StringVar(&config.FrontendURL, "frontend", "http://localhost:3000", "Frontend URL")
-	flag.StringVar(&config.EngineAddr, "engine", "localhost:50052", "Engine TCP address (host:port)")
+	flag.DurationVar(&config.HTTPTimeout, "http-timeout", 30*time.Second, "Timeout for each signup and login request to the frontend (0 disables)")
+	flag.StringVar(&config.TokensFile, "tokens-file", "", "Authenticate users with the pre-issued trading tokens in this file, one per line, instead of signing up and logging in through the frontend")
+	flag.BoolVar(&config.ReuseTokens, "reuse-tokens", false, "With -tokens-file, hand tokens out again round-robin when there are fewer tokens than users")
+	flag.BoolVar(&config.SingleUser, "single-user", false, "Sign up and log in one user, and open every worker's engine connections with its trading token")
+	flag.StringVar(&config.Sweep, "sweep", "", "Comma-separated concurrency levels (e.g. 10,50,100,200,500) to run one after another with -orders orders per user, printing orders/sec, p50 and p99 per level; -duration caps each level")
+	flag.StringVar(&config.EngineAddr, "engine", "localhost:50052", "Engine TCP address (host:port), or a comma-separated list to spread load across engines")
+	flag.StringVar(&config.ShardBy, "shard-by", ShardRoundRobin, "With several engines: round-robin spreads users evenly, symbol routes each symbol to one engine")
+	flag.StringVar(&config.TLSCA, "tls-ca", "", "PEM CA certificate used to verify the engine's TLS certificate")
+	flag.StringVar(&config.TLSServerName, "tls-servername", "", "Server name for SNI and certificate verification (default: host from -engine)")
+	flag.BoolVar(&config.TLSInsecure, "tls-insecure", false, "Skip engine TLS certificate verification (implied for loopback engines without -tls-ca)")
+	flag.IntVar(&config.TLSSessionCache, "tls-session-cache-size", defaultTLSSessionCacheSize, "TLS sessions cached for resumption across engine connections (0 disables resumption)")
+	flag.BoolVar(&config.NoDelay, "nodelay", true, "Set TCP_NODELAY on engine connections so order frames are not delayed by Nagle's algorithm")
+	flag.IntVar(&config.SendBuffer, "sndbuf", 0, "Engine socket send buffer size in bytes (0 keeps the OS default)")
+	flag.IntVar(&config.RecvBuffer, "rcvbuf", 0, "Engine socket receive buffer size in bytes (0 keeps the OS default)")
 	flag.IntVar(&config.NumUsers, "users", 10, "Number of users to create")
 	flag.IntVar(&config.OrdersPerUser, "orders", 100, "Orders per user")
+	flag.Int64Var(&config.TotalOrders, "total-orders", 0, "Orders for the whole run, split evenly across -users and overriding -orders; the run stops once this many are sent (0 disables)")
 	flag.IntVar(&config.Concurrency, "concurrency", 50, "Concurrent users")
 	flag.IntVar(&config.OrderConcurrency, "order-concurrency", 10, "Concurrent orders per user")
-	flag.DurationVar(&config.TestDuration, "duration", 5*time.Minute, "Test duration")
+	flag.IntVar(&config.MaxConns, "max-connections", 0, "Engine connections open at once across all users; dials queue for a free slot (0 for no limit)")
+	flag.IntVar(&config.SignupConc, "signup-concurrency", 0, "Signup and login requests in flight to the frontend at once across all users (0 for no limit)")
+	flag.IntVar(&config.ConnsPerUser, "conns-per-user", 1, "Authenticated connections each user opens to each engine it trades on, with its orders spread across them in turn")
+	flag.DurationVar(&config.TestDuration, "duration", 5*time.Minute, "Stop the run after this long, even if users have orders left (0 disables)")
+	flag.BoolVar(&config.Autoscale, "autoscale", false, "Tune concurrent users between 1 and -concurrency, growing while throughput rises and p99 stays under -latency-slo")
+	flag.DurationVar(&config.LatencySLO, "latency-slo", 0, "p99 order latency target for -autoscale")
+	flag.BoolVar(&config.Soak, "soak", false, "Keep every user submitting until -duration elapses, ignoring -orders")
+	flag.DurationVar(&config.RampUp, "ramp-up", 0, "Spread user launches evenly over this window (0 launches all at once)")
+	flag.IntVar(&config.FragmentPct, "fragment-pct", 0, "Percentage of orders sent as fragmented frames (0 disables)")
+	flag.IntVar(&config.FragmentSize, "fragment-size", 4, "Bytes per write when fragmenting an order frame")
+	flag.DurationVar(&config.FragmentDelay, "fragment-delay", time.Millisecond, "Delay between fragment writes")
+	flag.IntVar(&config.CancelPct, "cancel-pct", 0, "Percentage of order slots used to cancel a recently accepted order (0 disables)")
+	flag.IntVar(&config.ModifyPct, "modify-pct", 0, "Percentage of order slots used to amend the quantity and price of the latest accepted order (0 disables)")
+	flag.StringVar(&config.OrderPrefix, "order-prefix", "", "Prefix for generated order IDs (random UUIDs), e.g. a run tag to grep for in engine logs")
+	flag.IntVar(&config.OrderIDLen, "order-id-len", 0, "Pad generated order IDs to at least this many bytes after the prefix, to stress the engine's string handling (0 keeps 36-byte UUIDs)")
+	flag.IntVar(&config.UserIDLen, "user-id-len", 0, "Pad the user IDs sent with orders to at least this many bytes (0 keeps user_<n>)")
+	flag.IntVar(&config.CrossPct, "cross-pct", 0, "Percentage of orders sent as limits priced through the last trade (or -price-ref) by -price-spread, so they match immediately (0 disables)")
+	flag.IntVar(&config.QueryPct, "query-pct", 0, "Percentage of order slots used to fetch the user's portfolio from the frontend instead (0 disables)")
+	flag.StringVar(&config.TimestampSkew, "timestamp-skew", "", "Comma-separated offsets subtracted from each order's timestamp, one picked per order; negative values future-date it (e.g. 0,5s,-5s)")
+	flag.StringVar(&config.OrderMix, "order-mix", defaultOrderMix, "Weighted order types, e.g. market=20,limit=60,ioc=15,fok=5")
+	flag.StringVar(&config.Profile, "profile", "", "Weighted trader archetypes assigned per user, e.g. aggressive=20,passive=30,noise=50 (default all noise)")
+	flag.StringVar(&config.ThinkTime, "think-time", "0", "Pause before each of a user's orders: 0, a fixed duration, exp:<mean> or uniform:<min>-<max> (e.g. exp:50ms, uniform:0-100ms)")
+	flag.StringVar(&config.PriceModel, "price-model", PriceModelUniform, "Limit price distribution: uniform, normal or walk")
+	flag.Float64Var(&config.PriceRef, "price-ref", 150, "Reference (mid) price for -price-model")
+	flag.Float64Var(&config.PriceSpread, "price-spread", 50, "Price spread around -price-ref (uniform half-width, 3 stddevs for normal, max drift for walk)")
+	flag.StringVar(&config.QtyModel, "qty-model", QtyModelUniform, "Order quantity distribution: uniform (1-100), lognormal (median 10, long tail) or round-lots (1-10 lots of -lot-size)")
+	flag.IntVar(&config.LotSize, "lot-size", defaultLotSize, "Shares per lot for -qty-model round-lots")
+	flag.StringVar(&config.SymbolDist, "symbol-dist", SymbolDistUniform, "Symbol popularity: uniform or zipf (earlier symbols in the list trade more, see -zipf-skew)")
+	flag.Float64Var(&config.ZipfSkew, "zipf-skew", defaultZipfSkew, "Exponent for -symbol-dist zipf: the symbol of rank k gets weight 1/k^skew, so higher values concentrate more flow on the first symbols")
+	flag.IntVar(&protocolVersion, "protocol-version", ProtocolVersionBase, "Order response layout version (2 = decode reject codes, 3 = also estimate engine clock skew from server times)")
+	flag.IntVar(&minServerVersion, "min-server-version", ProtocolVersionBase, "Fail engine logins answered with an older protocol version than this (engines without version negotiation count as 1)")
+	flag.Float64Var(&config.CPUThreshold, "cpu-threshold", 90, "Warn when client CPU utilization (% of all cores) exceeds this")
+	flag.BoolVar(&config.CPUBackoff, "cpu-backoff", false, "Slow the send rate while client CPU is above -cpu-threshold")
+	flag.IntVar(&config.CrossAccounts, "cross-accounts", 0, "Accounts per worker trading against each other (>= 2 enables cross-account mode)")
+	flag.DurationVar(&config.CrossSettle, "cross-settle", 500*time.Millisecond, "Wait before verifying cross-account positions")
+	flag.StringVar(&config.TimeSeriesPath, "timeseries", "", "Append per-second metrics as CSV rows to this path")
+	flag.Float64Var(&config.Rate, "rate", 0, "Aggregate order rate limit across all users (orders/sec, 0 = unlimited)")
+	flag.BoolVar(&config.CorrectOmission, "correct-omission", false, "Measure latency from each order's scheduled send time (requires -target-rate)")
+	flag.Float64Var(&config.TargetRate, "target-rate", 0, "Per-user order rate (orders/sec) used to schedule sends for -correct-omission")
+	flag.DurationVar(&config.Heartbeat, "heartbeat", 30*time.Second, "Heartbeat interval per engine connection (0 disables)")
+	flag.IntVar(&config.HeartbeatMisses, "heartbeat-misses", 3, "Consecutive missed heartbeat acks before a connection is marked unhealthy")
+	flag.DurationVar(&config.VerifyBook, "verify-book", 0, "Query top of book for a random symbol at this interval and log the spread (0 disables)")
+	flag.IntVar(&config.VerifyDepth, "verify-depth", 0, "Rest this many limit buys on -verify-depth-symbol during the run, then check the book's depth holds every accepted one (0 disables)")
+	flag.StringVar(&config.DepthSymbol, "verify-depth-symbol", defaultVerifyDepthSymbol, "Untraded symbol used by -verify-depth and -verify-tif")
+	flag.BoolVar(&config.VerifyTIF, "verify-tif", false, "Check that IOC orders into an empty book are cancelled and FOK orders that cannot fill in full are killed, print PASS/FAIL per order type and exit 0 if both pass, 1 otherwise (bounded by -duration)")
+	flag.BoolVar(&config.TrackFills, "track-fills", false, "Measure submit-to-fill latency of market, IOC and FOK orders from the execution reports the engine pushes")
+	flag.StringVar(&latencyHistogram, "histogram", HistogramReservoir, "Latency recorder: reservoir (sampled, exact within the sample) or hdr (HdrHistogram over every order)")
+	flag.IntVar(&latencySampleCap, "latency-samples", 100000, "Latency samples retained per metric for percentiles (reservoir size)")
+	flag.Float64Var(&config.FailErrorRate, "fail-error-rate", 0.01, "Exit with status 1 when errors exceed this fraction of order attempts over the whole run")
+	flag.Float64Var(&config.MaxErrorRate, "max-error-rate", 0, "Abort the run (exit status 3) when errors exceed this fraction of attempts over a 10s window (e.g. 0.5, 0 disables)")
+	flag.IntVar(&config.MaxRetries, "max-retries", 0, "Retry a failed order up to this many times on a fresh connection, with exponential backoff")
+	flag.DurationVar(&config.Warmup, "warmup", 0, "Send orders for this long before measuring; warmup orders are excluded from results")
+	flag.DurationVar(&config.IOTimeout, "io-timeout", 0, "Timeout for each engine login and order response read; cancellation also interrupts a blocked read (0 disables)")
+	flag.DurationVar(&config.DrainTimeout, "drain-timeout", 5*time.Second, "On SIGINT/SIGTERM, wait this long for in-flight orders before exiting")
+	flag.StringVar(&config.OutputJSON, "output-json", "", "Write final results as a JSON object to this path")
+	flag.StringVar(&config.Manifest, "manifest", "", "Write the resolved config, seed, client version, host and endpoints to this path as JSON at start")
+	flag.StringVar(&config.OrdersCSV, "csv", "", "Write one CSV row per order (timestamp, user, symbol, side, type, quantity, price, accepted, latency_us, error) to this path")
+	flag.StringVar(&config.Replay, "replay", "", "Re-submit the orders recorded by -csv in this file instead of generating orders")
+	flag.BoolVar(&config.ReplayTiming, "replay-timing", false, "With -replay, keep the recorded gaps between orders instead of sending back to back")
+	flag.StringVar(&config.MetricsAddr, "metrics-addr", "", "Serve Prometheus metrics on this address (e.g. :9100)")
+	flag.StringVar(&config.ReportInterval, "report-interval", defaultReportInterval.String(), "Live status interval: a duration, adaptive (1s at first, doubling up to 1m) or 0 to disable")
+	flag.StringVar(&config.LogLevel, "log-level", "info", "Diagnostic log level: debug (adds per-user and per-order events), info, warn or error")
+	flag.StringVar(&config.PprofAddr, "pprof-addr", "", "Serve net/http/pprof handlers on this address (e.g. localhost:6060) and enable mutex profiling")
+	flag.BoolVar(&config.SkipPreflight, "skip-preflight", false, "Start without first checking that the frontend answers and each engine accepts a login")
+	flag.BoolVar(&config.Probe, "probe", false, "Liveness check: connect and authenticate to the first engine, time one heartbeat round trip and exit 0 if it is acked, 1 otherwise; sends no orders (bounded by -duration)")
+	flag.BoolVar(&config.Smoke, "smoke", false, "Connectivity check: sign up and log in one user, submit one order and exit 0 if it is accepted, 1 otherwise (bounded by -duration)")
+	flag.BoolVar(&config.DryRun, "dry-run", false, "Skip the frontend and answer every frame from an in-memory sink, measuring only the encode/decode path")
+	flag.StringVar(&config.HDRPath, "hdr", "", "Write order latency histogram in HdrHistogram log format to this path")
+	flag.StringVar(&config.HistBuckets, "hist-buckets", "", "Print an order latency distribution table split at these comma-separated boundaries, e.g. 1ms,5ms,10ms,50ms,100ms")
+	seed := flag.Int64("seed", 0, "Seed for reproducible order streams (0 picks one from the clock and logs it)")
+	configFile := flag.String("config", "", "YAML config file; flags set on the command line override its values")
+	symbolsFile := flag.String("symbols-file", "", "File of symbols to trade (newline or comma separated, '#' comments)")
 	flag.Parse()
 
-	config.Symbols = []string{"AAPL", "GOOGL", "MSFT", "AMZN", "TSLA"}
+	if latencySampleCap < 1 {
+		log.Fatalf("-latency-samples must be at least 1")
+	}
+	if err := validateHistogram(latencyHistogram); err != nil {
+		log.Fatalf("Invalid -histogram: %v", err)
+	}
 
-	log.Printf("Starting stress test with config: %+v", config)
+	if *configFile != "" {
+		if err := applyConfigFile(flag.CommandLine, *configFile, &config); err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		log.Printf("Loaded config from %s", *configFile)
+	}
 
-	// Setup graceful shutdown with immediate exit
-	ctx, cancel := context.WithCancel(context.Background())
+	// The reservoir keeps only a sample, and -hdr exports every order
+	if config.HDRPath != "" && latencyHistogram != HistogramHDR {
+		latencyHistogram = HistogramHDR
+		log.Printf("Recording latencies with -histogram %s, since -hdr exports every order", HistogramHDR)
+	}
+	// Recreate the recorders now that -histogram is known
+	stats = newStressStats()
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	if len(config.Symbols) == 0 {
+		config.Symbols = defaultSymbols
+	}
+	if *symbolsFile != "" {
+		symbols, err := loadSymbolsFile(*symbolsFile)
+		if err != nil {
+			log.Fatalf("Failed to load symbols: %v", err)
+		}
+		config.Symbols = symbols
+		log.Printf("Loaded %d symbols from %s", len(symbols), *symbolsFile)
+	}
 
-	// Track if we should force exit
-	forceExit := false
+	if err := config.validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+	if protocolVersion < ProtocolVersionBase || protocolVersion > protocol.ProtocolVersion {
+		log.Fatalf("Invalid config: protocol-version must be from %d to %d", ProtocolVersionBase, protocol.ProtocolVersion)
+	}
+	if minServerVersion > protocolVersion {
+		log.Fatalf("Invalid config: min-server-version %d is above protocol-version %d, so no engine could satisfy both", minServerVersion, protocolVersion)
+	}
+	// validate has already checked the level
+	logLevel, _ := parseLogLevel(config.LogLevel)
+	setupLogging(os.Stderr, logLevel)
+	seedRun(*seed)
 
-	// Handle shutdown signal - IMMEDIATE EXIT
-	go func() {
-		<-sigChan
-		log.Println("\n🛑 Received shutdown signal, exiting immediately...")
-		cancel()
-		forceExit = true
-
-		// Wait 500ms for graceful cleanup, then force exit
-		time.Sleep(500 * time.Millisecond)
-		log.Println("Force exiting...")
-		os.Exit(0)
-	}()
+	if _, err := newPriceModel(config); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
 
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, config.Concurrency)
+	// validate has already checked the engine list
+	addrs, _ := parseEngineAddrs(config.EngineAddr)
+	sessionCache := newTLSSessionCache(config.TLSSessionCache)
+	for _, addr := range addrs {
+		tlsConfig, err := buildTLSConfig(addr, config.TLSCA, config.TLSServerName, config.TLSInsecure)
+		if err != nil {
+			log.Fatalf("Invalid TLS config: %v", err)
+		}
+		tlsConfig.ClientSessionCache = sessionCache
+		engineTLS[addr] = tlsConfig
+	}
+	dryRun = config.DryRun
+	if config.TokensFile != "" {
+		tokens, err := loadTokenFile(config.TokensFile, config.ReuseTokens)
+		if err != nil {
+			log.Fatalf("Failed to load tokens: %v", err)
+		}
+		// -verify-depth logs in one user beyond -users
+		needed := config.NumUsers
+		if config.VerifyDepth > 0 {
+			needed++
+		}
+		if err := tokens.Require(needed); err != nil {
+			log.Fatalf("Invalid tokens file: %v", err)
+		}
+		tokenList = tokens
+		log.Printf("Authenticating with %d pre-issued trading tokens from %s", len(tokens.tokens), config.TokensFile)
+	}
+	orderIDPrefix = config.OrderPrefix
+	orderIDLen, userIDLen = config.OrderIDLen, config.UserIDLen
+	engineSocket = socketOptions{NoDelay: config.NoDelay, SendBuffer: config.SendBuffer, RecvBuffer: config.RecvBuffer}
+	orderLimiter = newOrderLimiter(config.Rate)
+	totalBudget = newOrderBudget(config.TotalOrders)
+	signupSlots = newSignupSlots(config.SignupConc)
+	connSlots = newConnSlots(config.MaxConns)
+	frontendClient = newFrontendClient(config.HTTPTimeout, config.Concurrency)
+	ioTimeout = config.IOTimeout
+	pendingFills = newFillTracker(config.TrackFills)
+
+	if config.Smoke {
+		smokeCtx := context.Background()
+		if config.TestDuration > 0 {
+			var cancel context.CancelFunc
+			smokeCtx, cancel = context.WithTimeout(smokeCtx, config.TestDuration)
+			defer cancel()
+		}
+		if err := runSmoke(smokeCtx, config); err != nil {
+			log.Printf("SMOKE FAIL: %v", err)
+			os.Exit(1)
+		}
+		log.Printf("SMOKE PASS: %s -> %s", config.FrontendURL, config.EngineAddr)
+		return
+	}
+
+	if config.Probe {
+		probeCtx := context.Background()
+		if config.TestDuration > 0 {
+			var cancel context.CancelFunc
+			probeCtx, cancel = context.WithTimeout(probeCtx, config.TestDuration)
+			defer cancel()
+		}
+		result, err := runProbe(probeCtx, config)
+		if err != nil {
+			log.Printf("PROBE FAIL: %v", err)
+			os.Exit(1)
+		}
+		log.Printf("PROBE OK: %s", result)
+		return
+	}
+
+	if config.VerifyTIF {
+		tifCtx := context.Background()
+		if config.TestDuration > 0 {
+			var cancel context.CancelFunc
+			tifCtx, cancel = context.WithTimeout(tifCtx, config.TestDuration)
+			defer cancel()
+		}
+		results, err := runTIFVerification(tifCtx, config)
+		passed := err == nil
+		for _, r := range results {
+			log.Print(r)
+			passed = passed && r.Pass
+		}
+		if err != nil {
+			log.Printf("TIF verification incomplete: %v", err)
+		}
+		if !passed {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if config.SingleUser {
+		if err := startSingleUser(config); err != nil {
+			log.Fatalf("Failed to log in the shared user: %v", err)
+		}
+	}
+	if !config.SkipPreflight && !config.DryRun {
+		preflightOrExit(config)
+	}
+
+	if config.Manifest != "" {
+		if err := writeManifest(config.Manifest, newManifest(config, time.Now())); err != nil {
+			log.Fatalf("Failed to write manifest: %v", err)
+		}
+		log.Printf("Run manifest written to %s", config.Manifest)
+	}
+
+	log.Printf("Starting stress test with config: %+v", config)
 
+	if code := runUntilShutdown(config); code != exitOK {
+		log.Printf("Exiting with status %d", code)
+		os.Exit(code)
+	}
+}
+
+// runStressTest runs the workload until every user finishes or ctx is
+// cancelled (by -duration or a signal), then drains, prints the final
+// results, writes the requested output files and returns the report.
+func runStressTest(ctx context.Context, config StressConfig, interrupted func() bool) Report {
 	startTime := time.Now()
 
+	// -max-error-rate aborts the run through this context
+	ctx, abort := context.WithCancel(ctx)
+	defer abort()
+	var abortReason atomic.Value
+	if config.MaxErrorRate > 0 {
+		go runErrorBreaker(ctx, config.MaxErrorRate, errorBreakerInterval, func(rate float64) {
+			reason := fmt.Sprintf("error rate %.1f%% over the last %ds exceeded -max-error-rate %.1f%%",
+				rate*100, errorBreakerWindow*int(errorBreakerInterval/time.Second), config.MaxErrorRate*100)
+			slog.Error("aborting run", "reason", reason)
+			abortReason.Store(reason)
+			abort()
+		})
+	}
+
+	var wg sync.WaitGroup
+	limiter := newConcurrencyLimiter(config.Concurrency)
+
+	if config.MetricsAddr != "" {
+		startMetricsServer(config.MetricsAddr)
+	}
+	if config.PprofAddr != "" {
+		startPprofServer(config.PprofAddr)
+	}
+
+	var replay []replayOrder
+	if config.Replay != "" {
+		orders, err := loadReplayFile(config.Replay)
+		if err != nil {
+			log.Fatalf("Failed to load replay: %v", err)
+		}
+		log.Printf("Replaying %d orders from %s", len(orders), config.Replay)
+		replay = orders
+	}
+
+	startWarmup(ctx, startTime, config.Warmup)
+	if config.Autoscale {
+		startAutoscaler(ctx, limiter, config.LatencySLO)
+	}
+
 	// Start live reporter
-	go startLiveReporter(config, startTime, ctx)
+	schedule, err := parseReportInterval(config.ReportInterval)
+	if err != nil {
+		log.Fatalf("Invalid report interval: %v", err)
+	}
+	go startLiveReporter(ctx, config, startTime, schedule)
+	go startCPUMonitor(ctx, config.CPUThreshold, config.CPUBackoff)
+
+	if config.OrdersCSV != "" {
+		w, err := startOrderCSVWriter(config.OrdersCSV)
+		if err != nil {
+			log.Fatalf("Failed to start order CSV writer: %v", err)
+		}
+		orderLog = w
+	}
+
+	var timeSeries *timeSeriesWriter
+	if config.TimeSeriesPath != "" {
+		ts, err := startTimeSeriesWriter(ctx, config.TimeSeriesPath, startTime)
+		if err != nil {
+			log.Fatalf("Failed to start time-series writer: %v", err)
+		}
+		timeSeries = ts
+	}
+
+	var depthDone chan DepthReport
+	if config.VerifyDepth > 0 {
+		depthDone = make(chan DepthReport, 1)
+		go func() { depthDone <- runDepthVerification(ctx, config) }()
+	}
 
 	// Launch workers
 	workersDone := make(chan bool, 1)
 	go func() {
-		for i := 1; i <= config.NumUsers; i++ {
-			// Check if we should exit early
-			if forceExit {
-				break
-			}
-
-			wg.Add(1)
-			semaphore <- struct{}{} // Acquire
-
-			go func(userID int) {
-				defer func() { <-semaphore }() // Release
-				userWorkerWithContext(ctx, config, userID, &wg)
-			}(i)
+		if replay != nil {
+			atomic.AddInt64(&activeUsers, 1)
+			runReplay(ctx, config, replay, config.ReplayTiming)
+			atomic.AddInt64(&activeUsers, -1)
+			workersDone <- true
+			return
 		}
+		launchUsers(ctx, config.NumUsers, config.RampUp, limiter, &wg, func(userID int) {
+			if config.CrossAccounts >= 2 {
+				crossAccountWorker(ctx, config, userID, &wg)
+			} else {
+				userWorkerWithContext(ctx, config, userID, &wg)
+			}
+		})
 
 		wg.Wait()
 		workersDone <- true
 	}()
 
 	// Wait for completion or cancellation
+	var abandoned int64
 	select {
 	case <-workersDone:
 		// Normal completion
 	case <-ctx.Done():
-		// Cancelled by signal
-		log.Println("Waiting for active connections to close...")
-		// Give 500ms for cleanup
-		time.Sleep(500 * time.Millisecond)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			slog.Info("test duration reached, draining in-flight orders", "duration", config.TestDuration)
+		}
+		abandoned = drainWorkers(workersDone, config.DrainTimeout)
 	}
 
-	if forceExit {
-		log.Println("Exited by user signal")
-		os.Exit(0)
+	// The check's orders are counted in the results, so wait for it first
+	var depth *DepthReport
+	if depthDone != nil {
+		d := <-depthDone
+		depth = &d
 	}
 
-	duration := time.Since(startTime)
+	// Results cover the measured phase only, after any warmup
+	if inWarmup() {
+		slog.Warn("run ended during warmup, no orders were measured")
+		resetMeasurement()
+	}
+	duration := max(time.Since(measurementStart()), 0)
+
+	if timeSeries != nil {
+		timeSeries.Close()
+	}
+	if orderLog != nil {
+		if err := orderLog.Close(); err != nil {
+			slog.Error("failed to write order CSV", "err", err)
+		} else {
+			log.Printf("Order CSV written to %s", config.OrdersCSV)
+		}
+	}
 
 	// Final stats
 	statsMutex.Lock()
-	finalStats := stats
+	finalStats := stats.snapshot()
 	statsMutex.Unlock()
+	report := buildReport(&finalStats, config, duration, interrupted())
+	report.AbandonedOrders = abandoned
+	if reason, ok := abortReason.Load().(string); ok {
+		report.Aborted = reason
+	}
+	if config.Autoscale {
+		report.AutoscaleConcurrency = limiter.Limit()
+	}
+	report.DepthVerification = depth
 
-	usersCreated := atomic.LoadInt64(&finalStats.UsersCreated)
-	usersLoggedIn := atomic.LoadInt64(&finalStats.UsersLoggedIn)
-	ordersSubmitted := atomic.LoadInt64(&finalStats.OrdersSubmitted)
-	ordersAccepted := atomic.LoadInt64(&finalStats.OrdersAccepted)
-	errors := atomic.LoadInt64(&finalStats.Errors)
+	log.Printf("=== FINAL RESULTS ===")
+	report.Log()
+	log.Printf("=====================")
 
-	avgSignup := averageLatency(finalStats.SignupLatencies)
-	avgLogin := averageLatency(finalStats.LoginLatencies)
-	avgOrder := averageLatency(finalStats.OrderLatencies)
+	if config.OutputJSON != "" {
+		if err := writeJSONReport(config.OutputJSON, report); err != nil {
+			slog.Error("failed to write JSON report", "err", err)
+		} else {
+			log.Printf("JSON report written to %s", config.OutputJSON)
+		}
+	}
 
-	ordersPerSec := float64(ordersSubmitted) / duration.Seconds()
+	if config.HDRPath != "" {
+		if err := writeHDRHistogram(config.HDRPath, finalStats.OrderLatencies, measurementStart(), measurementStart().Add(duration)); err != nil {
+			slog.Error("failed to write HDR histogram", "err", err)
+		} else {
+			log.Printf("HDR histogram written to %s", config.HDRPath)
+		}
+	}
 
-	log.Printf("=== FINAL RESULTS ===")
-	log.Printf("Test completed in %v", duration)
-	log.Printf("Users: %d created, %d logged in", usersCreated, usersLoggedIn)
-	log.Printf("Orders: %d submitted, %d accepted (%.1f%%)", ordersSubmitted, ordersAccepted,
-		float64(ordersAccepted)/float64(ordersSubmitted)*100)
-	log.Printf("Throughput: %.1f orders/sec", ordersPerSec)
-	log.Printf("Errors: %d", errors)
-	log.Printf("Average Latencies: Signup=%.2fms, Login=%.2fms, Order=%.2fms",
-		float64(avgSignup.Nanoseconds())/1e6,
-		float64(avgLogin.Nanoseconds())/1e6,
-		float64(avgOrder.Nanoseconds())/1e6)
-	log.Printf("=====================")
+	return report
 }
@@ -0,0 +1,673 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"maps"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestTokenPreview(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{"", ""},
+		{"short", "short"},
+		{"abcdefghijklmnopqrst", "abcdefghijklmnopqrst"},
+		{"abcdefghijklmnopqrstuvwxyz", "abcdefghijklmnopqrst"},
+	}
+	for _, tt := range tests {
+		if got := tokenPreview(tt.token); got != tt.want {
+			t.Errorf("tokenPreview(%q) = %q, want %q", tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeAuthResponseShapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "nested",
+			body: `{"message":"ok","user":{"id":"u1"},"tokens":{"sessionToken":"s","tradingToken":"nested-token"}}`,
+			want: "nested-token",
+		},
+		{
+			name: "flat",
+			body: `{"message":"ok","user":{"id":"u1"},"sessionToken":"s","tradingToken":"flat-token"}`,
+			want: "flat-token",
+		},
+		{
+			name: "nested preferred",
+			body: `{"tokens":{"tradingToken":"nested-token"},"tradingToken":"flat-token"}`,
+			want: "nested-token",
+		},
+		{
+			name: "empty nested falls back to flat",
+			body: `{"tokens":{"tradingToken":""},"tradingToken":"flat-token"}`,
+			want: "flat-token",
+		},
+		{
+			name:    "missing",
+			body:    `{"message":"ok","tokens":{"sessionToken":"s"}}`,
+			wantErr: errMissingTradingToken,
+		},
+	}
+
+	for _, tt := range tests {
+		tokens, err := decodeAuthResponse(strings.NewReader(tt.body))
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if tokens.TradingToken != tt.want {
+			t.Errorf("%s: TradingToken = %q, want %q", tt.name, tokens.TradingToken, tt.want)
+		}
+	}
+}
+
+func TestLoginVersionNegotiation(t *testing.T) {
+	defer func(asked, minimum int) { protocolVersion, minServerVersion = asked, minimum }(protocolVersion, minServerVersion)
+
+	tests := []struct {
+		name          string
+		asked, min    int
+		reported      uint8
+		wantErr       string
+		wantRequested uint8
+	}{
+		{name: "legacy engine", asked: 1, min: 1, reported: 0, wantRequested: 1},
+		{name: "negotiated down", asked: 2, min: 1, reported: 1, wantRequested: 2},
+		{name: "newer than asked", asked: 1, min: 1, reported: 3, wantErr: "engine answered with version 3, but this client asked for 1", wantRequested: 1},
+		{name: "below minimum", asked: 2, min: 2, reported: 0, wantErr: "engine speaks version 1, older than -min-server-version 2", wantRequested: 2},
+	}
+	for _, tt := range tests {
+		protocolVersion, minServerVersion = tt.asked, tt.min
+		client, server := net.Pipe()
+		requested := make(chan uint8, 1)
+		go func() {
+			defer server.Close()
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			_, version, _ := protocol.DecodeLoginRequest(body)
+			requested <- version
+			server.Write(protocol.EncodeLoginResponse(protocol.LoginResponse{Success: true, Message: "ok", Version: tt.reported}))
+		}()
+
+		err := authenticateTCP(context.Background(), client, "token")
+		client.Close()
+		if got := <-requested; got != tt.wantRequested {
+			t.Errorf("%s: login requested version %d, want %d", tt.name, got, tt.wantRequested)
+		}
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, errVersionMismatch) || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want a version mismatch saying %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCoordinatedOmissionCorrection(t *testing.T) {
+	resetGlobals(t)
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	// The first response stalls; the rest are instant
+	const stall = 200 * time.Millisecond
+	go serveFakeOrders(server, func(i int) time.Duration {
+		if i == 0 {
+			return stall
+		}
+		return 0
+	})
+
+	// Ten orders scheduled 10ms apart, sent serially like a single connection
+	const n = 10
+	interval := 10 * time.Millisecond
+	scheduleStart := time.Now()
+	for i := 0; i < n; i++ {
+		intended := scheduleStart.Add(time.Duration(i) * interval)
+		if wait := time.Until(intended); wait > 0 {
+			time.Sleep(wait)
+		}
+		if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{Intended: intended}); err != nil {
+			t.Fatalf("submit %d: %v", i, err)
+		}
+	}
+
+	statsMutex.Lock()
+	snap := stats.snapshot()
+	statsMutex.Unlock()
+	corrected := snap.OrderLatencies.(*latencyReservoir).Samples()
+	uncorrected := snap.UncorrectedLatencies.(*latencyReservoir).Samples()
+
+	if len(corrected) != n || len(uncorrected) != n {
+		t.Fatalf("recorded %d corrected and %d uncorrected samples, want %d", len(corrected), len(uncorrected), n)
+	}
+
+	// Only one order actually stalled, so the uncorrected median is tiny,
+	// while most corrected samples include the time spent queued behind it.
+	if p50 := percentile(uncorrected, 0.5); p50 > stall/4 {
+		t.Errorf("uncorrected p50 = %v, want well under %v", p50, stall)
+	}
+	if p50 := percentile(corrected, 0.5); p50 < stall/2 {
+		t.Errorf("corrected p50 = %v, want at least %v", p50, stall/2)
+	}
+}
+
+func TestSingleUserSignsUpOnce(t *testing.T) {
+	var signups atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if r.URL.Path == "/api/auth/stress-signup" {
+			signups.Add(1)
+		}
+		io.WriteString(w, `{"tokens":{"sessionToken":"session","tradingToken":"shared"}}`)
+	}))
+	defer srv.Close()
+
+	saved := frontendClient
+	frontendClient = newFrontendClient(5*time.Second, 4)
+	defer func() { frontendClient = saved }()
+	defer func() { tokenList = nil }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{FrontendURL: srv.URL, SingleUser: true}
+	if err := startSingleUser(config); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 200
+	var wg sync.WaitGroup
+	for userID := 1; userID <= workers; userID++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens, ok := signupAndLogin(context.Background(), config, userID)
+			if !ok || tokens.TradingToken != "shared" || tokens.SessionToken != "session" {
+				t.Errorf("user %d got %+v, %v; want the shared user's tokens", userID, tokens, ok)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := signups.Load(); n != 1 {
+		t.Errorf("%d workers made %d signup calls, want 1", workers, n)
+	}
+}
+
+func TestSignupRetriesRateLimit(t *testing.T) {
+	resetGlobals(t)
+
+	// The first signup and the first login are rate limited
+	var signups, logins atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		calls := &logins
+		if r.URL.Path == "/api/auth/stress-signup" {
+			calls = &signups
+		}
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"message":"Too many requests","code":"RATE_LIMITED"}`)
+			return
+		}
+		io.WriteString(w, `{"tokens":{"tradingToken":"token"}}`)
+	}))
+	defer srv.Close()
+
+	saved := frontendClient
+	frontendClient = newFrontendClient(5*time.Second, 4)
+	defer func() { frontendClient = saved }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	tokens, ok := signupAndLogin(context.Background(), StressConfig{FrontendURL: srv.URL}, 1)
+	if !ok || tokens.TradingToken != "token" {
+		t.Fatalf("got %+v, %v; want the user logged in after retrying", tokens, ok)
+	}
+	if signups.Load() != 2 || logins.Load() != 2 {
+		t.Errorf("%d signup and %d login calls, want 2 of each", signups.Load(), logins.Load())
+	}
+	snap := stats.snapshot()
+	if snap.RateLimitedRetries != 2 || snap.Errors != 0 {
+		t.Errorf("%d rate-limited retries and %d errors, want 2 and 0", snap.RateLimitedRetries, snap.Errors)
+	}
+
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	for header, want := range map[string]time.Duration{
+		"3":  3 * time.Second,
+		"-1": 0,
+		now.Add(7 * time.Second).Format(http.TimeFormat): 7 * time.Second,
+		now.Add(-time.Minute).Format(http.TimeFormat):    0,
+	} {
+		if got, ok := retryAfter(header, now); !ok || got != want {
+			t.Errorf("Retry-After %q = %v, %v; want %v", header, got, ok, want)
+		}
+	}
+	for _, header := range []string{"", "soon", "1.5"} {
+		if _, ok := retryAfter(header, now); ok {
+			t.Errorf("Retry-After %q parsed", header)
+		}
+	}
+}
+
+func TestSignupExistingUserLogsIn(t *testing.T) {
+	resetGlobals(t)
+
+	var logins atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if r.URL.Path == "/api/auth/stress-signup" {
+			w.WriteHeader(http.StatusConflict)
+			io.WriteString(w, `{"message":"Email already registered","code":"EMAIL_TAKEN"}`)
+			return
+		}
+		logins.Add(1)
+		io.WriteString(w, `{"tokens":{"tradingToken":"token"}}`)
+	}))
+	defer srv.Close()
+
+	saved := frontendClient
+	frontendClient = newFrontendClient(5*time.Second, 4)
+	defer func() { frontendClient = saved }()
+
+	tokens, ok := signupAndLogin(context.Background(), StressConfig{FrontendURL: srv.URL}, 1)
+	if !ok || tokens.TradingToken != "token" {
+		t.Fatalf("signupAndLogin = %+v, %v; want the worker to log in", tokens, ok)
+	}
+	if n := logins.Load(); n != 1 {
+		t.Errorf("%d logins, want 1", n)
+	}
+	if n := atomic.LoadInt64(&stats.Errors); n != 0 {
+		t.Errorf("existing user counted as %d errors", n)
+	}
+	if n := atomic.LoadInt64(&stats.UsersExisting); n != 1 {
+		t.Errorf("UsersExisting = %d, want 1", n)
+	}
+
+	// Other signup failures are still errors
+	for _, fe := range []*FrontendError{
+		{Status: http.StatusBadRequest, Message: "Password too weak"},
+		{Status: http.StatusInternalServerError, Message: "database down"},
+	} {
+		if fe.userExists() {
+			t.Errorf("%v treated as an existing user", fe)
+		}
+	}
+	if fe := (&FrontendError{Status: http.StatusBadRequest, Message: "User already exists"}); !fe.userExists() {
+		t.Errorf("%v not treated as an existing user", fe)
+	}
+}
+
+func TestInvalidOrderNotSent(t *testing.T) {
+	resetGlobals(t)
+
+	// Nothing reads the server side, so any write would block and time out
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	client.SetDeadline(time.Now().Add(time.Second))
+
+	var dials int32
+	pool := NewConnPool(1, func() (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return client, nil
+	})
+	defer pool.Close()
+
+	err := withRetry(context.Background(), pool, 3, func(conn net.Conn) error {
+		_, err := submitOrderTCP(context.Background(), conn, "user_1", "AAPL", 0, protocol.OrderTypeLimit, 0, 100, submitOptions{})
+		return err
+	})
+	if !errors.Is(err, protocol.ErrInvalidOrder) {
+		t.Fatalf("submit error = %v, want ErrInvalidOrder", err)
+	}
+
+	statsMutex.Lock()
+	categories := maps.Clone(stats.ErrorCategories)
+	statsMutex.Unlock()
+	if got := categories[ErrCategoryInvalidOrder]; got != 1 || len(categories) != 1 {
+		t.Errorf("categories = %v, want one invalid_order", categories)
+	}
+	if got := atomic.LoadInt64(&stats.RetryAttempts); got != 0 {
+		t.Errorf("RetryAttempts = %d, want invalid orders never retried", got)
+	}
+	// The untouched connection stays in the pool
+	if _, err := pool.Get(context.Background()); err != nil || atomic.LoadInt32(&dials) != 1 {
+		t.Errorf("pool redialed after an invalid order (dials %d, err %v)", dials, err)
+	}
+}
+
+func TestMismatchedOrderResponseDetected(t *testing.T) {
+	resetGlobals(t)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	// Answer with another order's ID, as a desynchronized stream would
+	go func() {
+		defer server.Close()
+		if _, err := protocol.ReadFrame(server); err != nil {
+			return
+		}
+		resp := protocol.OrderResponse{OrderID: "order_other", Accepted: true, Message: "Order accepted"}
+		server.Write(protocol.EncodeOrderResponse(resp))
+	}()
+
+	_, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{OrderID: "order_sent"})
+	if !errors.Is(err, errCorrelation) {
+		t.Fatalf("submit error = %v, want a correlation mismatch", err)
+	}
+
+	statsMutex.Lock()
+	categories := maps.Clone(stats.ErrorCategories)
+	statsMutex.Unlock()
+
+	if got := categories[ErrCategoryCorrelation]; got != 1 {
+		t.Errorf("correlation_error = %d, want 1 (categories %v)", got, categories)
+	}
+	if got := atomic.LoadInt64(&stats.OrdersSubmitted); got != 0 {
+		t.Errorf("OrdersSubmitted = %d, want the mismatched response left uncounted", got)
+	}
+}
+
+// TestLiveReporterConcurrentWithSubmits runs the live status block while
+// orders are being recorded. It only fails on its own if the snapshot is
+// inconsistent; run with -race to catch unsynchronized reads of stats.
+func TestLiveReporterConcurrentWithSubmits(t *testing.T) {
+	resetGlobals(t)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go serveFakeOrders(server, func(int) time.Duration { return 0 })
+
+	const n = 200
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < n; i++ {
+			if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{}); err != nil {
+				t.Errorf("submit %d: %v", i, err)
+				return
+			}
+		}
+	}()
+
+	config := StressConfig{NumUsers: 1, Concurrency: 1}
+	start := time.Now()
+	for reporting := true; reporting; {
+		select {
+		case <-done:
+			reporting = false
+		default:
+		}
+		logLiveStatus(config, start)
+		snap := takeLiveSnapshot()
+		if snap.OrdersAccepted > snap.OrdersSubmitted {
+			t.Fatalf("snapshot has %d accepted of %d submitted", snap.OrdersAccepted, snap.OrdersSubmitted)
+		}
+		if snap.OrdersSubmitted > 0 && snap.MinOrderLatency > snap.MaxOrderLatency {
+			t.Fatalf("snapshot min latency %v exceeds max %v", snap.MinOrderLatency, snap.MaxOrderLatency)
+		}
+	}
+
+	if snap := takeLiveSnapshot(); snap.OrdersSubmitted != n || snap.OrdersAccepted != n {
+		t.Errorf("final snapshot = %d submitted, %d accepted, want %d each", snap.OrdersSubmitted, snap.OrdersAccepted, n)
+	}
+}
+
+func TestSoakRunsUntilCancelled(t *testing.T) {
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		OrdersPerUser:    5,
+		OrderConcurrency: 2,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+		TestDuration:     100 * time.Millisecond,
+		Soak:             true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.TestDuration)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	start := time.Now()
+	userWorkerWithContext(ctx, config, 1, &wg)
+
+	if elapsed := time.Since(start); elapsed < config.TestDuration {
+		t.Errorf("soak worker returned after %v, before the %v duration", elapsed, config.TestDuration)
+	}
+	if got := atomic.LoadInt64(&stats.OrdersSubmitted); got <= int64(config.OrdersPerUser) {
+		t.Errorf("soak worker sent %d orders, want more than the %d-order quota", got, config.OrdersPerUser)
+	}
+}
+
+func TestUsersCompletedReachesNumUsers(t *testing.T) {
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         4,
+		OrdersPerUser:    20,
+		Concurrency:      2,
+		OrderConcurrency: 2,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+		CPUThreshold:     100,
+		DrainTimeout:     time.Second,
+	}
+	report := runStressTest(context.Background(), config, func() bool { return false })
+
+	if report.UsersCompleted != int64(config.NumUsers) {
+		t.Errorf("UsersCompleted = %d, want %d", report.UsersCompleted, config.NumUsers)
+	}
+	if n := atomic.LoadInt64(&ordersInFlight); n != 0 {
+		t.Errorf("%d orders still in flight after the run", n)
+	}
+}
+
+func TestRunStressTestStopsAtDuration(t *testing.T) {
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         2,
+		Concurrency:      2,
+		OrderConcurrency: 2,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+		CPUThreshold:     100,
+		DrainTimeout:     time.Second,
+		TestDuration:     200 * time.Millisecond,
+		Soak:             true,
+		OutputJSON:       filepath.Join(t.TempDir(), "report.json"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.TestDuration)
+	defer cancel()
+	start := time.Now()
+	report := runStressTest(ctx, config, func() bool { return false })
+
+	if elapsed := time.Since(start); elapsed > config.TestDuration+config.DrainTimeout {
+		t.Errorf("run took %v, want it to stop shortly after the %v duration", elapsed, config.TestDuration)
+	}
+	if report.Interrupted {
+		t.Error("timed-out run reported as interrupted")
+	}
+	if report.OrdersSubmitted == 0 || report.DurationSec <= 0 {
+		t.Errorf("report = %d orders over %.3fs, want a non-empty measured run", report.OrdersSubmitted, report.DurationSec)
+	}
+	if _, err := os.Stat(config.OutputJSON); err != nil {
+		t.Errorf("JSON report not written: %v", err)
+	}
+}
+
+func TestCancelMidLaunchReleasesEverything(t *testing.T) {
+	const users, limit = 10, 2
+	limiter := newConcurrencyLimiter(limit)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Each user holds its slot until ctx is cancelled, so the launcher is
+	// left waiting for a third slot when the cancel arrives
+	var wg sync.WaitGroup
+	var started atomic.Int64
+	launched := make(chan struct{})
+	go func() {
+		launchUsers(ctx, users, 0, limiter, &wg, func(int) {
+			defer wg.Done()
+			started.Add(1)
+			<-ctx.Done()
+		})
+		close(launched)
+	}()
+	for started.Load() < limit {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	waited := make(chan struct{})
+	go func() {
+		<-launched
+		wg.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("wg.Wait did not return after cancelling mid-launch")
+	}
+	if got := started.Load(); got != limit {
+		t.Errorf("started %d users, want %d", got, limit)
+	}
+	limiter.mu.Lock()
+	active := limiter.active
+	limiter.mu.Unlock()
+	if active != 0 {
+		t.Errorf("%d slots still held after every user returned", active)
+	}
+}
+
+func TestUserEmailsSeeded(t *testing.T) {
+	defer func(seed int64) { runSeed = seed }(runSeed)
+
+	// Signups carry the seeded email
+	var signedUp []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SignupRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		signedUp = append(signedUp, req.Email)
+	}))
+	defer srv.Close()
+
+	saved := frontendClient
+	frontendClient = newFrontendClient(5*time.Second, 1)
+	defer func() { frontendClient = saved }()
+
+	emails := func(seed int64) []string {
+		runSeed = seed
+		signedUp = nil
+		for userNum := 1; userNum <= 3; userNum++ {
+			if _, _, err := createUser(srv.URL, userNum); err != nil {
+				t.Fatalf("seed %d user %d: %v", seed, userNum, err)
+			}
+		}
+		return signedUp
+	}
+
+	first, again := emails(42), emails(42)
+	if !slices.Equal(first, again) {
+		t.Errorf("seed 42 signed up %v, then %v", first, again)
+	}
+	if first[0] != "stress-16-1@example.com" {
+		t.Errorf("user 1 email = %q, want stress-16-1@example.com", first[0])
+	}
+
+	seen := make(map[string]int64)
+	for _, seed := range []int64{1, 2, 36, 42, 43, -1, math.MaxInt64, math.MinInt64, 1760000000000000000} {
+		runSeed = seed
+		for userNum := 1; userNum <= 20; userNum++ {
+			email := userEmail(userNum)
+			if prev, ok := seen[email]; ok {
+				t.Errorf("%s used by seeds %d and %d", email, prev, seed)
+			}
+			seen[email] = seed
+		}
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sweepRow is one concurrency level's result in a -sweep
+type sweepRow struct {
+	Concurrency int
+	Orders      int64
+	Errors      int64
+	Duration    time.Duration
+	RPS         float64
+	P50, P99    time.Duration
+}
+
+// parseSweep parses -sweep, a comma-separated list of positive concurrency
+// levels such as "10,50,100". An empty spec disables the sweep.
+func parseSweep(spec string) ([]int, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var levels []int
+	for _, field := range strings.Split(spec, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid sweep level %q (want a positive concurrency)", field)
+		}
+		levels = append(levels, n)
+	}
+	return levels, nil
+}
+
+// runSweep runs a short fixed-order test at each concurrency level in turn
+// and prints a table of the results. Level N runs N users at once, each
+// sending -orders orders, and -duration caps each level rather than the
+// whole sweep. The users of the largest level are logged in once up front
+// and reused by every level, so the frontend is not part of any
+// measurement. Stats are reset between levels; a cancelled ctx stops the
+// sweep after the level in progress drains.
+func runSweep(ctx context.Context, config StressConfig, levels []int) []sweepRow {
+	if tokenList == nil && !dryRun {
+		tokens, err := loginSweepUsers(ctx, config, slices.Max(levels))
+		if err != nil {
+			slog.Error("sweep stopped, could not log in users", "err", err)
+			return nil
+		}
+		tokenList = tokens
+		defer func() { tokenList = nil }()
+	}
+
+	var rows []sweepRow
+	for _, level := range levels {
+		if ctx.Err() != nil {
+			break
+		}
+		log.Printf("Sweep: running %d concurrent users", level)
+		rows = append(rows, runSweepLevel(ctx, config, level))
+	}
+	logSweep(rows)
+	return rows
+}
+
+// runSweepLevel runs level users concurrently until each has sent its
+// orders, and summarizes the orders they sent
+func runSweepLevel(ctx context.Context, config StressConfig, level int) sweepRow {
+	statsMutex.Lock()
+	stats = newStressStats()
+	statsMutex.Unlock()
+
+	if config.TestDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.TestDuration)
+		defer cancel()
+	}
+	config.Concurrency = level
+	config.NumUsers = level
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	launchUsers(ctx, level, 0, newConcurrencyLimiter(level), &wg, func(userID int) {
+		userWorkerWithContext(ctx, config, userID, &wg)
+	})
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	statsMutex.Lock()
+	snap := stats.snapshot()
+	statsMutex.Unlock()
+	pcts := snap.OrderLatencies.Percentiles(0.50, 0.99)
+	row := sweepRow{
+		Concurrency: level,
+		Orders:      atomic.LoadInt64(&snap.OrdersSubmitted),
+		Errors:      atomic.LoadInt64(&snap.Errors),
+		Duration:    elapsed,
+		P50:         pcts[0],
+		P99:         pcts[1],
+	}
+	if elapsed > 0 {
+		row.RPS = float64(row.Orders) / elapsed.Seconds()
+	}
+	return row
+}
+
+// loginSweepUsers signs up and logs in users 1..n, config.Concurrency at a
+// time, and returns their tokens for every level to reuse
+func loginSweepUsers(ctx context.Context, config StressConfig, n int) (*preissuedTokens, error) {
+	tokens := make([]AuthTokens, n)
+	failed := make([]bool, n)
+	limiter := newConcurrencyLimiter(config.Concurrency)
+	var wg sync.WaitGroup
+	launchUsers(ctx, n, 0, limiter, &wg, func(userID int) {
+		defer wg.Done()
+		t, ok := signupAndLogin(ctx, config, userID)
+		tokens[userID-1], failed[userID-1] = t, !ok
+	})
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i, f := range failed {
+		if f {
+			return nil, fmt.Errorf("user %d could not sign up or log in", i+1)
+		}
+	}
+	return &preissuedTokens{tokens: tokens}, nil
+}
+
+// logSweep prints the concurrency curve
+func logSweep(rows []sweepRow) {
+	log.Printf("=== SWEEP RESULTS ===")
+	log.Printf("%11s %12s %10s %10s %8s %8s", "Concurrency", "Orders/sec", "p50 (ms)", "p99 (ms)", "Orders", "Errors")
+	for _, r := range rows {
+		log.Printf("%11d %12.1f %10.2f %10.2f %8d %8d", r.Concurrency, r.RPS, toMs(r.P50), toMs(r.P99), r.Orders, r.Errors)
+	}
+	log.Printf("=====================")
+}
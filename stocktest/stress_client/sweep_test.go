@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestSweepRunsEachLevel(t *testing.T) {
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         1,
+		Concurrency:      1,
+		OrdersPerUser:    20,
+		OrderConcurrency: 2,
+		OrderMix:         "market=50,limit=50",
+		PriceModel:       PriceModelUniform,
+		PriceRef:         100,
+		PriceSpread:      10,
+		Symbols:          []string{"AAPL"},
+	}
+	levels := []int{1, 2, 4}
+	rows := runSweep(context.Background(), config, levels)
+	if len(rows) != len(levels) {
+		t.Fatalf("sweep produced %d rows, want one per level (%d)", len(rows), len(levels))
+	}
+	for i, row := range rows {
+		if row.Concurrency != levels[i] {
+			t.Errorf("row %d is for concurrency %d, want %d", i, row.Concurrency, levels[i])
+		}
+		if want := int64(levels[i] * config.OrdersPerUser); row.Orders != want {
+			t.Errorf("concurrency %d sent %d orders, want %d", row.Concurrency, row.Orders, want)
+		}
+		if row.Errors != 0 || row.RPS <= 0 || row.P99 < row.P50 {
+			t.Errorf("concurrency %d: errors=%d rps=%.1f p50=%v p99=%v", row.Concurrency, row.Errors, row.RPS, row.P50, row.P99)
+		}
+	}
+}
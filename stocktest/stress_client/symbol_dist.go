@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Symbol distributions selectable with -symbol-dist
+const (
+	SymbolDistUniform = "uniform"
+	SymbolDistZipf    = "zipf"
+)
+
+// defaultZipfSkew is the -zipf-skew exponent: with 1.0 the first symbol
+// gets twice the orders of the second and three times those of the third
+const defaultZipfSkew = 1.0
+
+// SymbolPicker chooses the symbol of each order. Each draws one value from
+// the worker's source per pick, so switching distributions leaves the rest
+// of a seeded stream in step.
+type SymbolPicker interface {
+	Pick(r *rand.Rand) string
+}
+
+// newSymbolPicker builds the distribution named by config.SymbolDist over
+// config.Symbols
+func newSymbolPicker(config StressConfig) (SymbolPicker, error) {
+	switch config.SymbolDist {
+	case "", SymbolDistUniform:
+		return UniformSymbols(config.Symbols), nil
+	case SymbolDistZipf:
+		if config.ZipfSkew <= 0 {
+			return nil, fmt.Errorf("zipf-skew must be positive, got %g", config.ZipfSkew)
+		}
+		return newZipfSymbols(config.Symbols, config.ZipfSkew), nil
+	default:
+		return nil, fmt.Errorf("unknown symbol dist %q (want uniform or zipf)", config.SymbolDist)
+	}
+}
+
+// UniformSymbols picks every symbol equally often
+type UniformSymbols []string
+
+// Pick returns a uniformly chosen symbol
+func (u UniformSymbols) Pick(r *rand.Rand) string {
+	return u[r.Intn(len(u))]
+}
+
+// ZipfSymbols picks the symbol of rank k (1-based, in list order) with
+// weight 1/k^skew, so the first few symbols take most of the flow as the
+// most traded names do on a real exchange
+type ZipfSymbols struct {
+	symbols []string
+	// cumulative weights, normalized so the last is 1
+	cdf []float64
+}
+
+func newZipfSymbols(symbols []string, skew float64) *ZipfSymbols {
+	z := &ZipfSymbols{symbols: symbols, cdf: make([]float64, len(symbols))}
+	var total float64
+	for i := range symbols {
+		total += 1 / math.Pow(float64(i+1), skew)
+		z.cdf[i] = total
+	}
+	for i := range z.cdf {
+		z.cdf[i] /= total
+	}
+	return z
+}
+
+// Pick returns a symbol drawn by rank
+func (z *ZipfSymbols) Pick(r *rand.Rand) string {
+	u := r.Float64()
+	i := sort.SearchFloat64s(z.cdf, u)
+	return z.symbols[min(i, len(z.symbols)-1)]
+}
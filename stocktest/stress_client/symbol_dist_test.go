@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestZipfSymbolPopularity(t *testing.T) {
+	config := StressConfig{
+		OrderMix:   defaultOrderMix,
+		PriceRef:   100,
+		Symbols:    defaultSymbols,
+		SymbolDist: SymbolDistZipf,
+		ZipfSkew:   defaultZipfSkew,
+	}
+	gen, err := newOrderGenerator(config, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const orders = 20000
+	counts := make(map[string]int)
+	for i := 0; i < orders; i++ {
+		counts[gen.Next().Symbol]++
+	}
+
+	// Skew 1 over five symbols gives AAPL 1/(1+1/2+1/3+1/4+1/5), about 44%
+	if share := float64(counts["AAPL"]) / orders; share < 0.40 || share > 0.48 {
+		t.Errorf("AAPL got %.1f%% of orders, want about 44%%", share*100)
+	}
+	for i := 1; i < len(defaultSymbols); i++ {
+		if counts[defaultSymbols[i]] >= counts[defaultSymbols[i-1]] {
+			t.Errorf("%s got %d orders, not fewer than %s's %d", defaultSymbols[i], counts[defaultSymbols[i]], defaultSymbols[i-1], counts[defaultSymbols[i-1]])
+		}
+	}
+
+	// Uniform picks draw exactly as before, keeping seeded streams
+	uniform, _ := newSymbolPicker(StressConfig{Symbols: defaultSymbols})
+	r1, r2 := rand.New(rand.NewSource(7)), rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		if got, want := uniform.Pick(r1), defaultSymbols[r2.Intn(len(defaultSymbols))]; got != want {
+			t.Fatalf("uniform pick %d = %s, want %s", i, got, want)
+		}
+	}
+
+	config.ZipfSkew = 0
+	if _, err := newSymbolPicker(config); err == nil {
+		t.Error("zipf accepted a skew of 0")
+	}
+}
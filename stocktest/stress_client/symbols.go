@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var defaultSymbols = []string{"AAPL", "GOOGL", "MSFT", "AMZN", "TSLA"}
+
+// loadSymbolsFile reads a symbol universe from path. Symbols may be separated
+// by newlines or commas; surrounding whitespace, blank lines and lines
+// starting with '#' are ignored.
+func loadSymbolsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open symbols file: %w", err)
+	}
+	defer f.Close()
+
+	var symbols []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, field := range strings.Split(line, ",") {
+			if sym := strings.TrimSpace(field); sym != "" {
+				symbols = append(symbols, sym)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbols file: %w", err)
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbols file %s contains no symbols", path)
+	}
+	return symbols, nil
+}
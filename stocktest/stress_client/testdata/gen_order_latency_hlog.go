@@ -0,0 +1,59 @@
+//go:build ignore
+
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+// Generates order_latency.hlog, the reference log for TestWriteHDRHistogram,
+// with hdrhistogram-go's own histogram and log writer rather than the stress
+// client's export path:
+//
+//	go run testdata/gen_order_latency_hlog.go
+package main
+
+import (
+	"log"
+	"os"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+func main() {
+	// 1µs to 1000µs in nanoseconds, tracked from 1ns to 60s at 3 digits
+	h := hdrhistogram.New(1, 60_000_000_000, 3)
+	for us := int64(1); us <= 1000; us++ {
+		if err := h.RecordValue(us * 1000); err != nil {
+			log.Fatal(err)
+		}
+	}
+	const startMs = 1700000000000
+	h.SetStartTimeMs(startMs)
+	h.SetEndTimeMs(startMs + 10_000)
+	h.SetTag("order_latency")
+
+	f, err := os.Create("testdata/order_latency.hlog")
+	if err != nil {
+		log.Fatal(err)
+	}
+	w := hdrhistogram.NewHistogramLogWriter(f)
+	w.SetBaseTime(startMs)
+	for _, step := range []func() error{
+		w.OutputLogFormatVersion,
+		func() error { return w.OutputStartTime(startMs) },
+		func() error { return w.OutputBaseTime(startMs) },
+		w.OutputLegend,
+		func() error { return w.OutputIntervalHistogram(h) },
+	} {
+		if err := step(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
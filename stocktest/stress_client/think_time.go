@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// ThinkTime draws the pause a user takes before each order, modelling the
+// pace of a human trader
+type ThinkTime interface {
+	Sample(r *rand.Rand) time.Duration
+}
+
+// parseThinkTime parses a -think-time spec: "0" (no pause), a fixed
+// duration such as "20ms", "exp:50ms" (exponential with that mean) or
+// "uniform:0-100ms" (the lower bound may omit the unit). It returns nil
+// for no pause.
+func parseThinkTime(spec string) (ThinkTime, error) {
+	kind, arg, found := strings.Cut(strings.TrimSpace(spec), ":")
+	if !found {
+		if kind == "" || kind == "0" {
+			return nil, nil
+		}
+		d, err := time.ParseDuration(kind)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid think-time %q (want 0, a duration, exp:<mean> or uniform:<min>-<max>)", spec)
+		}
+		if d == 0 {
+			return nil, nil
+		}
+		return FixedThinkTime{D: d}, nil
+	}
+
+	switch kind {
+	case "exp":
+		mean, err := time.ParseDuration(arg)
+		if err != nil || mean <= 0 {
+			return nil, fmt.Errorf("invalid think-time %q: exp needs a positive mean", spec)
+		}
+		return ExpThinkTime{Mean: mean}, nil
+	case "uniform":
+		lo, hi, ok := strings.Cut(arg, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid think-time %q: uniform needs <min>-<max>", spec)
+		}
+		maxD, err := time.ParseDuration(hi)
+		if err != nil {
+			return nil, fmt.Errorf("invalid think-time %q: %w", spec, err)
+		}
+		minD, err := parseBoundWithUnitOf(lo, hi)
+		if err != nil {
+			return nil, fmt.Errorf("invalid think-time %q: %w", spec, err)
+		}
+		if minD < 0 || maxD < minD {
+			return nil, fmt.Errorf("invalid think-time %q: want 0 <= min <= max", spec)
+		}
+		return UniformThinkTime{Min: minD, Max: maxD}, nil
+	}
+	return nil, fmt.Errorf("unknown think-time distribution %q (want exp or uniform)", kind)
+}
+
+// parseBoundWithUnitOf parses s as a duration, borrowing the unit of other
+// when s is a bare number, so "0-100ms" reads as 0ms to 100ms
+func parseBoundWithUnitOf(s, other string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	unit := strings.TrimLeft(other, "0123456789.")
+	return time.ParseDuration(s + unit)
+}
+
+// FixedThinkTime pauses for the same duration before every order
+type FixedThinkTime struct {
+	D time.Duration
+}
+
+// Sample returns the fixed pause
+func (f FixedThinkTime) Sample(*rand.Rand) time.Duration { return f.D }
+
+// ExpThinkTime draws exponentially distributed pauses, the gaps between
+// arrivals of a Poisson process
+type ExpThinkTime struct {
+	Mean time.Duration
+}
+
+// Sample returns an exponentially distributed pause
+func (e ExpThinkTime) Sample(r *rand.Rand) time.Duration {
+	return time.Duration(r.ExpFloat64() * float64(e.Mean))
+}
+
+// UniformThinkTime draws pauses uniformly from [Min, Max]
+type UniformThinkTime struct {
+	Min, Max time.Duration
+}
+
+// Sample returns a uniformly distributed pause
+func (u UniformThinkTime) Sample(r *rand.Rand) time.Duration {
+	return u.Min + time.Duration(r.Int63n(int64(u.Max-u.Min)+1))
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestThinkTimeDistributions(t *testing.T) {
+	tests := []struct {
+		spec     string
+		mean     time.Duration
+		min, max time.Duration
+	}{
+		{spec: "20ms", mean: 20 * time.Millisecond, min: 20 * time.Millisecond, max: 20 * time.Millisecond},
+		{spec: "exp:50ms", mean: 50 * time.Millisecond, max: time.Hour},
+		{spec: "uniform:0-100ms", mean: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{spec: "uniform:10ms-30ms", mean: 20 * time.Millisecond, min: 10 * time.Millisecond, max: 30 * time.Millisecond},
+	}
+	const samples = 50000
+	for _, tt := range tests {
+		think, err := parseThinkTime(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.spec, err)
+		}
+		r := rand.New(rand.NewSource(1))
+		var sum time.Duration
+		for i := 0; i < samples; i++ {
+			d := think.Sample(r)
+			if d < tt.min || d > tt.max {
+				t.Fatalf("%s: sample %v outside [%v, %v]", tt.spec, d, tt.min, tt.max)
+			}
+			sum += d
+		}
+		mean := sum / samples
+		if diff := math.Abs(float64(mean - tt.mean)); diff > 0.03*float64(tt.mean) {
+			t.Errorf("%s: mean %v, want %v within 3%%", tt.spec, mean, tt.mean)
+		}
+	}
+
+	for _, spec := range []string{"", "0", "0s"} {
+		if think, err := parseThinkTime(spec); err != nil || think != nil {
+			t.Errorf("%q = %v, %v; want no think time", spec, think, err)
+		}
+	}
+	for _, spec := range []string{"fast", "-5ms", "exp:0", "exp:soon", "uniform:100ms", "uniform:50ms-10ms", "gauss:5ms"} {
+		if _, err := parseThinkTime(spec); err == nil {
+			t.Errorf("%q parsed, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// tifResult is the outcome of one -verify-tif check
+type tifResult struct {
+	Check  string
+	Pass   bool
+	Detail string
+}
+
+// String formats the result as the line -verify-tif prints for it
+func (r tifResult) String() string {
+	verdict := "PASS"
+	if !r.Pass {
+		verdict = "FAIL"
+	}
+	return fmt.Sprintf("%s %s: %s", r.Check, verdict, r.Detail)
+}
+
+// runTIFVerification checks for -verify-tif that the engine enforces the
+// immediate-or-cancel and fill-or-kill order types. Two users, a maker and a
+// taker, trade on -verify-depth-symbol, which nothing else may trade, on the
+// engine that owns it. Failing to log in, connect or read the book is
+// returned as an error rather than a failed check.
+func runTIFVerification(ctx context.Context, config StressConfig) ([]tifResult, error) {
+	addrs, err := parseEngineAddrs(config.EngineAddr)
+	if err != nil {
+		return nil, err
+	}
+	symbol := config.DepthSymbol
+	addr := addrs[symbolShard(symbol, len(addrs))]
+
+	var conns [2]net.Conn
+	for i := range conns {
+		userID := sharedUserID + i
+		tokens, ok := signupAndLogin(ctx, config, userID)
+		if !ok {
+			return nil, fmt.Errorf("could not log in verification user %d", userID)
+		}
+		conn, err := dialEngine(ctx, addr, tokens.TradingToken)
+		if err != nil {
+			return nil, fmt.Errorf("engine %s: %w", addr, err)
+		}
+		defer conn.Close()
+		conns[i] = conn
+	}
+	maker, taker := conns[0], conns[1]
+	makerID, takerID := userIDString(sharedUserID), userIDString(sharedUserID+1)
+
+	ioc, err := verifyIOC(ctx, taker, takerID, symbol, config.PriceRef, depthVerifySettle)
+	if err != nil {
+		return nil, fmt.Errorf("IOC check: %w", err)
+	}
+	fok, err := verifyFOK(ctx, maker, taker, makerID, takerID, symbol, config.PriceRef, depthVerifySettle)
+	if err != nil {
+		return []tifResult{ioc}, fmt.Errorf("FOK check: %w", err)
+	}
+	return []tifResult{ioc, fok}, nil
+}
+
+// verifyIOC sends a one-share IOC buy at price into symbol's empty book.
+// With nothing to match it must be cancelled at once, so it passes when,
+// after settle, no bid rests at its price.
+func verifyIOC(ctx context.Context, conn net.Conn, userID, symbol string, price float64, settle time.Duration) (tifResult, error) {
+	r := tifResult{Check: "IOC"}
+	book, err := queryDepth(conn, symbol)
+	if err != nil {
+		return r, err
+	}
+	if len(book.Asks) > 0 || len(book.Bids) > 0 {
+		return r, fmt.Errorf("%s book is not empty (%d bids, %d asks)", symbol, len(book.Bids), len(book.Asks))
+	}
+
+	resp, err := submitOrderTCP(ctx, conn, userID, symbol, protocol.OrderSideBuy, protocol.OrderTypeIOC, 1, price, submitOptions{})
+	if err != nil {
+		return r, err
+	}
+	if err := sleepCtx(ctx, settle); err != nil {
+		return r, err
+	}
+	if book, err = queryDepth(conn, symbol); err != nil {
+		return r, err
+	}
+
+	if qty := levelQuantity(book.Bids, price); qty > 0 {
+		r.Detail = fmt.Sprintf("unfilled buy rested in the book at %.2f with quantity %d", price, qty)
+		return r, nil
+	}
+	r.Pass = true
+	r.Detail = "unfilled buy cancelled, nothing rested"
+	if !resp.Accepted {
+		r.Detail = fmt.Sprintf("unfilled buy rejected (%s), nothing rested", resp.Message)
+	}
+	return r, nil
+}
+
+// verifyFOK rests a one-share sell from maker at price, then sends a
+// two-share FOK buy from taker at the same price. The buy cannot be filled
+// in full, so it must be killed whole: after settle the sell must still
+// rest untouched and no bid may rest. The resting sell is cancelled before
+// returning.
+func verifyFOK(ctx context.Context, maker, taker net.Conn, makerID, takerID, symbol string, price float64, settle time.Duration) (tifResult, error) {
+	r := tifResult{Check: "FOK"}
+	rest, err := submitOrderTCP(ctx, maker, makerID, symbol, protocol.OrderSideSell, protocol.OrderTypeLimit, 1, price, submitOptions{})
+	if err != nil {
+		return r, err
+	}
+	if !rest.Accepted {
+		return r, fmt.Errorf("resting sell rejected: %s", rest.Message)
+	}
+	defer submitCancelTCP(maker, rest.OrderID)
+
+	resp, err := submitOrderTCP(ctx, taker, takerID, symbol, protocol.OrderSideBuy, protocol.OrderTypeFOK, 2, price, submitOptions{})
+	if err != nil {
+		return r, err
+	}
+	if err := sleepCtx(ctx, settle); err != nil {
+		return r, err
+	}
+	book, err := queryDepth(taker, symbol)
+	if err != nil {
+		return r, err
+	}
+
+	switch ask, bid := levelQuantity(book.Asks, price), levelQuantity(book.Bids, price); {
+	case ask < 1:
+		r.Detail = fmt.Sprintf("buy of 2 partially filled against 1 available: resting sell quantity %d, want 1", ask)
+	case bid > 0:
+		r.Detail = fmt.Sprintf("unfilled buy rested in the book at %.2f with quantity %d", price, bid)
+	default:
+		r.Pass = true
+		r.Detail = "buy of 2 against 1 available killed whole"
+		if !resp.Accepted {
+			r.Detail = fmt.Sprintf("buy of 2 against 1 available rejected (%s)", resp.Message)
+		}
+	}
+	return r, nil
+}
+
+// levelQuantity is the total quantity resting at price, matched to the tick
+func levelQuantity(levels []protocol.BookLevel, price float64) int64 {
+	var qty int64
+	for _, l := range levels {
+		if math.Round(l.Price/depthVerifyTick) == math.Round(price/depthVerifyTick) {
+			qty += l.Quantity
+		}
+	}
+	return qty
+}
+
+// sleepCtx waits d or until ctx is cancelled
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	"stress_client/protocol"
+)
+
+// fakeTIFEngine is a one-book matching engine for the -verify-tif checks.
+// With enforce unset it treats IOC and FOK orders as plain limits.
+type fakeTIFEngine struct {
+	mu         sync.Mutex
+	enforce    bool
+	bids, asks []protocol.Order
+}
+
+// connect returns the client end of a connection served by the engine
+func (e *fakeTIFEngine) connect(t *testing.T) net.Conn {
+	client, server := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	go func() {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			var reply []byte
+			switch body[0] {
+			case protocol.MessageTypeSubmitOrder:
+				o, _ := protocol.DecodeSubmitOrder(body)
+				reply = protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: e.submit(o)})
+			case protocol.MessageTypeCancelOrder:
+				orderID, _ := protocol.DecodeCancelOrder(body)
+				e.mu.Lock()
+				e.asks = slices.DeleteFunc(e.asks, func(o protocol.Order) bool { return o.OrderID == orderID })
+				e.mu.Unlock()
+				reply = protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: orderID, Accepted: true})
+			case protocol.MessageTypeDepthRequest:
+				symbol, _ := protocol.DecodeDepthRequest(body)
+				d := protocol.BookDepth{Symbol: symbol}
+				e.mu.Lock()
+				for _, o := range e.bids {
+					d.Bids = append(d.Bids, protocol.BookLevel{Price: o.Price, Quantity: o.Quantity})
+				}
+				for _, o := range e.asks {
+					d.Asks = append(d.Asks, protocol.BookLevel{Price: o.Price, Quantity: o.Quantity})
+				}
+				e.mu.Unlock()
+				reply = protocol.EncodeDepthResponse(d)
+			}
+			if _, err := server.Write(reply); err != nil {
+				return
+			}
+		}
+	}()
+	return client
+}
+
+// submit matches o against the opposite side and rests any remainder
+func (e *fakeTIFEngine) submit(o protocol.Order) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	opposite, own := &e.asks, &e.bids
+	crosses := func(price float64) bool { return price <= o.Price }
+	if o.Side == protocol.OrderSideSell {
+		opposite, own = &e.bids, &e.asks
+		crosses = func(price float64) bool { return price >= o.Price }
+	}
+	immediate := e.enforce && (o.Type == protocol.OrderTypeIOC || o.Type == protocol.OrderTypeFOK)
+
+	var available int64
+	for _, r := range *opposite {
+		if crosses(r.Price) {
+			available += r.Quantity
+		}
+	}
+	if e.enforce && o.Type == protocol.OrderTypeFOK && available < o.Quantity {
+		return false
+	}
+	remaining := o.Quantity
+	kept := (*opposite)[:0]
+	for _, r := range *opposite {
+		if remaining > 0 && crosses(r.Price) {
+			fill := min(remaining, r.Quantity)
+			remaining -= fill
+			r.Quantity -= fill
+		}
+		if r.Quantity > 0 {
+			kept = append(kept, r)
+		}
+	}
+	*opposite = kept
+	if remaining > 0 && !immediate {
+		o.Quantity = remaining
+		*own = append(*own, o)
+	}
+	return true
+}
+
+func TestTIFVerification(t *testing.T) {
+	resetGlobals(t)
+
+	ctx := context.Background()
+	for _, enforce := range []bool{true, false} {
+		engine := &fakeTIFEngine{enforce: enforce}
+		ioc, err := verifyIOC(ctx, engine.connect(t), "user_2", defaultVerifyDepthSymbol, 100, 0)
+		if err != nil {
+			t.Fatalf("verifyIOC: %v", err)
+		}
+		if ioc.Pass != enforce {
+			t.Errorf("enforce=%v: %s", enforce, ioc)
+		}
+
+		engine = &fakeTIFEngine{enforce: enforce}
+		fok, err := verifyFOK(ctx, engine.connect(t), engine.connect(t), "user_1", "user_2", defaultVerifyDepthSymbol, 100, 0)
+		if err != nil {
+			t.Fatalf("verifyFOK: %v", err)
+		}
+		if fok.Pass != enforce {
+			t.Errorf("enforce=%v: %s", enforce, fok)
+		}
+		if enforce && len(engine.asks) != 0 {
+			t.Errorf("resting sell not cancelled after the FOK check: %+v", engine.asks)
+		}
+	}
+
+	// A book that already holds orders cannot give a clean IOC result
+	engine := &fakeTIFEngine{asks: []protocol.Order{{OrderID: "stale", Price: 100, Quantity: 5}}}
+	if _, err := verifyIOC(ctx, engine.connect(t), "user_2", defaultVerifyDepthSymbol, 100, 0); err == nil {
+		t.Error("verifyIOC accepted a non-empty book")
+	}
+}
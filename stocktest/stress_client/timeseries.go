@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var timeSeriesHeader = []string{
+	"timestamp", "elapsed_s", "orders_submitted", "orders_accepted", "errors",
+	"error_rate", "p50_ms", "p99_ms", "active_connections", "bytes_tx", "bytes_rx",
+}
+
+// timeSeriesWriter appends one CSV row of per-second metrics on its own 1s
+// ticker, independent of the live reporter interval.
+type timeSeriesWriter struct {
+	file    *os.File
+	w       *csv.Writer
+	start   time.Time
+	done    chan struct{}
+	stopped chan struct{}
+
+	// Totals at the previous row, used to compute per-second deltas, and
+	// the measurement resets they follow
+	lastResets    int
+	lastSubmitted int64
+	lastAccepted  int64
+	lastErrors    int64
+	lastTX        int64
+	lastRX        int64
+}
+
+// startTimeSeriesWriter creates path and starts writing a row every second
+// until ctx is cancelled or Close is called. Both paths flush the file.
+func startTimeSeriesWriter(ctx context.Context, path string, start time.Time) (*timeSeriesWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time-series file: %w", err)
+	}
+
+	t := &timeSeriesWriter{
+		file:    f,
+		w:       csv.NewWriter(f),
+		start:   start,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if err := t.w.Write(timeSeriesHeader); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write time-series header: %w", err)
+	}
+
+	intervalWindowsEnabled[windowTimeSeries].Store(true)
+
+	go t.run(ctx)
+	return t, nil
+}
+
+func (t *timeSeriesWriter) run(ctx context.Context) {
+	defer close(t.stopped)
+	defer t.file.Close()
+	defer t.w.Flush()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.writeRow(time.Now())
+			return
+		case <-t.done:
+			t.writeRow(time.Now())
+			return
+		case now := <-ticker.C:
+			t.writeRow(now)
+			t.w.Flush()
+		}
+	}
+}
+
+// Close writes a final row, flushes, and waits for the writer to exit.
+func (t *timeSeriesWriter) Close() {
+	select {
+	case <-t.done:
+	default:
+		close(t.done)
+	}
+	<-t.stopped
+}
+
+func (t *timeSeriesWriter) writeRow(now time.Time) {
+	submitted, accepted, errors, resets := sampleOrderCounters()
+	window := stats.takeIntervalLatencies(windowTimeSeries)
+
+	// Warmup ended since the last row and zeroed the order and error counts
+	if resets != t.lastResets {
+		t.lastResets = resets
+		t.lastSubmitted, t.lastAccepted, t.lastErrors = 0, 0, 0
+	}
+
+	tx := atomic.LoadInt64(&bytesSent)
+	rx := atomic.LoadInt64(&bytesReceived)
+
+	dSubmitted := submitted - t.lastSubmitted
+	dAccepted := accepted - t.lastAccepted
+	dErrors := errors - t.lastErrors
+	dTX := tx - t.lastTX
+	dRX := rx - t.lastRX
+	t.lastSubmitted, t.lastAccepted, t.lastErrors = submitted, accepted, errors
+	t.lastTX, t.lastRX = tx, rx
+
+	errorRate := 0.0
+	if attempts := dSubmitted + dErrors; attempts > 0 {
+		errorRate = float64(dErrors) / float64(attempts)
+	}
+
+	// window was merged into a new recorder, so no other goroutine touches it
+	pcts := window.Percentiles(0.50, 0.99)
+	p50, p99 := pcts[0], pcts[1]
+
+	t.w.Write([]string{
+		now.UTC().Format(time.RFC3339Nano),
+		strconv.FormatFloat(now.Sub(t.start).Seconds(), 'f', 3, 64),
+		strconv.FormatInt(dSubmitted, 10),
+		strconv.FormatInt(dAccepted, 10),
+		strconv.FormatInt(dErrors, 10),
+		strconv.FormatFloat(errorRate, 'f', 4, 64),
+		strconv.FormatFloat(float64(p50.Nanoseconds())/1e6, 'f', 3, 64),
+		strconv.FormatFloat(float64(p99.Nanoseconds())/1e6, 'f', 3, 64),
+		strconv.FormatInt(atomic.LoadInt64(&activeConns), 10),
+		strconv.FormatInt(dTX, 10),
+		strconv.FormatInt(dRX, 10),
+	})
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SkewStats counts orders sent with one -timestamp-skew value
+type SkewStats struct {
+	OrdersSubmitted int64
+	OrdersAccepted  int64
+}
+
+// parseTimestampSkews parses -timestamp-skew, a comma-separated list of
+// durations such as "0,5s,-2s". Each is subtracted from the order
+// timestamp, so positive skews send stale orders and negative ones
+// future-dated orders. An empty spec disables skewing.
+func parseTimestampSkews(spec string) ([]time.Duration, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var skews []time.Duration
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		d, err := time.ParseDuration(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp-skew %q: %w", field, err)
+		}
+		skews = append(skews, d)
+	}
+	return skews, nil
+}
+
+// logTimestampSkew prints acceptance per skew bucket, most future-dated
+// first
+func logTimestampSkew(skews map[string]SkewReport) {
+	if len(skews) == 0 {
+		return
+	}
+	// Keys are time.Duration strings, which validate has already parsed
+	keys := slices.SortedFunc(maps.Keys(skews), func(a, b string) int {
+		da, _ := time.ParseDuration(a)
+		db, _ := time.ParseDuration(b)
+		return cmp.Compare(da, db)
+	})
+	log.Printf("Timestamp Skew (positive = stale, negative = future-dated):")
+	for _, skew := range keys {
+		s := skews[skew]
+		log.Printf("  %10s: %d submitted, %d accepted (%.1f%%)", skew, s.OrdersSubmitted, s.OrdersAccepted, s.AcceptedPct)
+	}
+}
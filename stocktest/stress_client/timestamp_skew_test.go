@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestTimestampSkewOnWire(t *testing.T) {
+	resetGlobals(t)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// The fake engine rejects future-dated orders and reports each timestamp
+	client, server := net.Pipe()
+	defer client.Close()
+	stamps := make(chan int64, 3)
+	go func() {
+		defer server.Close()
+		for {
+			body, err := protocol.ReadFrame(server)
+			if err != nil {
+				return
+			}
+			o, err := protocol.DecodeSubmitOrder(body)
+			if err != nil {
+				return
+			}
+			stamps <- o.TimestampMs
+			resp := protocol.OrderResponse{OrderID: o.OrderID, Accepted: o.TimestampMs <= time.Now().UnixMilli()}
+			if _, err := server.Write(protocol.EncodeOrderResponse(resp)); err != nil {
+				return
+			}
+		}
+	}()
+
+	skews, err := parseTimestampSkews("0, 10s,-10s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, skew := range skews {
+		before := time.Now().Add(-skew).UnixMilli()
+		opts := submitOptions{TimestampSkew: skew, SkewTracked: true}
+		if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, opts); err != nil {
+			t.Fatalf("submit with skew %v: %v", skew, err)
+		}
+		after := time.Now().Add(-skew).UnixMilli()
+		if got := <-stamps; got < before || got > after {
+			t.Errorf("skew %v: timestamp_ms = %d, want within [%d, %d]", skew, got, before, after)
+		}
+	}
+
+	statsMutex.Lock()
+	snap := stats.snapshot()
+	statsMutex.Unlock()
+	want := map[time.Duration]SkewStats{
+		0:                 {OrdersSubmitted: 1, OrdersAccepted: 1},
+		10 * time.Second:  {OrdersSubmitted: 1, OrdersAccepted: 1},
+		-10 * time.Second: {OrdersSubmitted: 1, OrdersAccepted: 0},
+	}
+	if len(snap.Skews) != len(want) {
+		t.Fatalf("skew buckets = %d, want %d", len(snap.Skews), len(want))
+	}
+	for skew, w := range want {
+		if got := snap.Skews[skew]; got == nil || *got != w {
+			t.Errorf("skew %v = %+v, want %+v", skew, got, w)
+		}
+	}
+
+	for _, spec := range []string{"5", "1s,,2s", "soon"} {
+		if _, err := parseTimestampSkews(spec); err == nil {
+			t.Errorf("parseTimestampSkews(%q) accepted", spec)
+		}
+	}
+}
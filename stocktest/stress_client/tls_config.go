@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+)
+
+// defaultTLSSessionCacheSize is the default -tls-session-cache-size
+const defaultTLSSessionCacheSize = 64
+
+// engineTLS holds the TLS configuration for each engine address, built from
+// the -tls-* flags at startup
+var engineTLS = map[string]*tls.Config{}
+
+// tlsConfigFor returns the TLS configuration for dialing addr. An address
+// missing from engineTLS gets the configuration buildTLSConfig gives it with
+// no -tls-* flags, so only a loopback engine is dialed unverified.
+func tlsConfigFor(addr string) (*tls.Config, error) {
+	if cfg, ok := engineTLS[addr]; ok {
+		return cfg, nil
+	}
+	return buildTLSConfig(addr, "", "", false)
+}
+
+// buildTLSConfig returns the TLS configuration for connecting to engineAddr.
+// With caPath the engine certificate is verified against that CA, using
+// serverName (or the address host) for SNI and verification. Verification is
+// skipped only with insecure, or for a loopback engine when no CA is given,
+// which keeps local runs against a self-signed engine working.
+func buildTLSConfig(engineAddr, caPath, serverName string, insecure bool) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(engineAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid engine address %q: %w", engineAddr, err)
+	}
+	if serverName == "" {
+		serverName = host
+	}
+
+	cfg := &tls.Config{ServerName: serverName}
+	switch {
+	case caPath != "":
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", caPath)
+		}
+		cfg.RootCAs = pool
+		cfg.InsecureSkipVerify = insecure
+	case insecure || isLoopback(host):
+		cfg.InsecureSkipVerify = true
+	default:
+		return nil, fmt.Errorf("engine %s is not loopback: pass -tls-ca to verify its certificate or -tls-insecure to skip verification", engineAddr)
+	}
+	return cfg, nil
+}
+
+// newTLSSessionCache returns the client session cache shared by every engine
+// connection, so a reconnect resumes an earlier session instead of running
+// a full handshake. size 0 disables resumption.
+func newTLSSessionCache(size int) tls.ClientSessionCache {
+	if size <= 0 {
+		return nil
+	}
+	return tls.NewLRUClientSessionCache(size)
+}
+
+// dialTLS opens a TLS connection to addr and counts whether the handshake
+// resumed a cached session or ran in full
+func dialTLS(addr string) (*tls.Conn, error) {
+	cfg, err := tlsConfigFor(addr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := tls.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if conn.ConnectionState().DidResume {
+		atomic.AddInt64(&stats.TLSResumed, 1)
+	} else {
+		atomic.AddInt64(&stats.TLSFullHandshakes, 1)
+	}
+	return conn, nil
+}
+
+// isLoopback reports whether host is localhost or a loopback IP
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
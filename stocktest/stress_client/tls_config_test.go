@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+// writeTestCA writes a self-signed CA certificate to a temp file
+func writeTestCA(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "stress test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(crand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+// startFakeTLSEngine serves the engine's TLS TCP protocol on a loopback
+// port: it accepts any login token and answers every order with accept.
+func startFakeTLSEngine(t *testing.T, accept bool) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fake engine"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(crand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				for {
+					body, err := protocol.ReadFrame(conn)
+					if err != nil {
+						return
+					}
+					switch body[0] {
+					case protocol.MessageTypeLoginRequest:
+						conn.Write(protocol.EncodeLoginResponse(protocol.LoginResponse{Success: true, Message: "ok"}))
+					case protocol.MessageTypeSubmitOrder:
+						o, _ := protocol.DecodeSubmitOrder(body)
+						conn.Write(protocol.EncodeOrderResponse(protocol.OrderResponse{OrderID: o.OrderID, Accepted: accept, Message: "fake"}))
+					case protocol.MessageTypeHeartbeat:
+						conn.Write(protocol.EncodeHeartbeatAck())
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestTLSSessionResumption(t *testing.T) {
+	resetGlobals(t)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	addr := srv.Listener.Addr().String()
+	cfg, err := buildTLSConfig(addr, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ClientSessionCache = newTLSSessionCache(defaultTLSSessionCacheSize)
+	engineTLS[addr] = cfg
+	defer delete(engineTLS, addr)
+
+	for i, wantResume := range []bool{false, true} {
+		conn, err := dialTLS(addr)
+		if err != nil {
+			t.Fatalf("dial %d: %v", i+1, err)
+		}
+		if got := conn.ConnectionState().DidResume; got != wantResume {
+			t.Errorf("connection %d resumed = %v, want %v", i+1, got, wantResume)
+		}
+		// TLS 1.3 sends the session ticket after the handshake, so read a
+		// response for the client to store it
+		io.WriteString(conn, "GET / HTTP/1.1\r\nHost: engine\r\nConnection: close\r\n\r\n")
+		io.Copy(io.Discard, conn)
+		conn.Close()
+	}
+	if full, resumed := atomic.LoadInt64(&stats.TLSFullHandshakes), atomic.LoadInt64(&stats.TLSResumed); full != 1 || resumed != 1 {
+		t.Errorf("handshakes = %d full, %d resumed, want 1 and 1", full, resumed)
+	}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	ca := writeTestCA(t)
+
+	tests := []struct {
+		name         string
+		addr         string
+		ca           string
+		serverName   string
+		insecure     bool
+		wantErr      bool
+		wantInsecure bool
+		wantRoots    bool
+		wantSNI      string
+	}{
+		{name: "loopback defaults to skip", addr: "localhost:50052", wantInsecure: true, wantSNI: "localhost"},
+		{name: "loopback ip", addr: "127.0.0.1:50052", wantInsecure: true, wantSNI: "127.0.0.1"},
+		{name: "remote without ca fails", addr: "staging.example.com:50052", wantErr: true},
+		{name: "remote insecure opt-in", addr: "staging.example.com:50052", insecure: true, wantInsecure: true, wantSNI: "staging.example.com"},
+		{name: "remote with ca verifies", addr: "staging.example.com:50052", ca: ca, wantRoots: true, wantSNI: "staging.example.com"},
+		{name: "loopback with ca verifies", addr: "localhost:50052", ca: ca, wantRoots: true, wantSNI: "localhost"},
+		{name: "servername override", addr: "10.0.0.5:50052", ca: ca, serverName: "engine.internal", wantRoots: true, wantSNI: "engine.internal"},
+		{name: "missing ca file", addr: "staging.example.com:50052", ca: filepath.Join(t.TempDir(), "missing.pem"), wantErr: true},
+		{name: "bad address", addr: "no-port", insecure: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := buildTLSConfig(tt.addr, tt.ca, tt.serverName, tt.insecure)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildTLSConfig: %v", err)
+			}
+			if cfg.InsecureSkipVerify != tt.wantInsecure {
+				t.Errorf("InsecureSkipVerify = %v, want %v", cfg.InsecureSkipVerify, tt.wantInsecure)
+			}
+			if (cfg.RootCAs != nil) != tt.wantRoots {
+				t.Errorf("RootCAs set = %v, want %v", cfg.RootCAs != nil, tt.wantRoots)
+			}
+			if cfg.ServerName != tt.wantSNI {
+				t.Errorf("ServerName = %q, want %q", cfg.ServerName, tt.wantSNI)
+			}
+		})
+	}
+}
+
+func TestTLSConfigForUnlistedEngine(t *testing.T) {
+	// An address the -tls-* flags never configured is not dialed unverified
+	// unless it is loopback
+	if _, err := tlsConfigFor("staging.example.com:50052"); err == nil {
+		t.Error("unlisted remote engine got a TLS config")
+	}
+	if _, err := dialTLS("staging.example.com:50052"); err == nil {
+		t.Error("dialed an unlisted remote engine")
+	}
+	cfg, err := tlsConfigFor("127.0.0.1:50052")
+	if err != nil {
+		t.Fatalf("unlisted loopback engine: %v", err)
+	}
+	if !cfg.InsecureSkipVerify {
+		t.Error("unlisted loopback engine verifies its certificate")
+	}
+}
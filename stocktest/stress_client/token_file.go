@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// tokenList holds the tokens of -tokens-file, or the one shared user of
+// -single-user. When set, users authenticate with these instead of signing
+// up and logging in.
+var tokenList *preissuedTokens
+
+// sharedUserID is the user -single-user and -smoke sign up as
+const sharedUserID = 1
+
+// preissuedTokens assigns tokens obtained before the run to user IDs
+type preissuedTokens struct {
+	tokens []AuthTokens
+	// reuse hands tokens out again, round-robin, once each has been used
+	reuse bool
+}
+
+// loadTokenFile reads the trading tokens at path
+func loadTokenFile(path string, reuse bool) (*preissuedTokens, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tokens file: %w", err)
+	}
+	defer f.Close()
+	tokens, err := parseTokens(f)
+	if err != nil {
+		return nil, fmt.Errorf("tokens file %s: %w", path, err)
+	}
+	p := &preissuedTokens{reuse: reuse}
+	for _, t := range tokens {
+		p.tokens = append(p.tokens, AuthTokens{TradingToken: t})
+	}
+	return p, nil
+}
+
+// loginSharedUser signs up and logs in the one user behind -single-user and
+// -smoke. Dry runs and -tokens-file skip the frontend.
+func loginSharedUser(config StressConfig) (AuthTokens, error) {
+	if dryRun {
+		return AuthTokens{TradingToken: dryRunToken}, nil
+	}
+	if tokenList != nil {
+		return tokenList.For(sharedUserID)
+	}
+	email, password, err := createUser(config.FrontendURL, sharedUserID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	return loginUser(config.FrontendURL, email, password)
+}
+
+// startSingleUser logs in the shared user once for -single-user and hands
+// its tokens to every user ID, so workers skip signup and all open their
+// engine connections with the same trading token
+func startSingleUser(config StressConfig) error {
+	tokens, err := loginSharedUser(config)
+	if err != nil {
+		return err
+	}
+	tokenList = &preissuedTokens{tokens: []AuthTokens{tokens}, reuse: true}
+	log.Printf("Single user: every worker connects with the trading token of user %d", sharedUserID)
+	return nil
+}
+
+// parseTokens reads one token per line. Surrounding whitespace is trimmed,
+// and blank lines and lines starting with # are skipped.
+func parseTokens(r io.Reader) ([]string, error) {
+	var tokens []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("no tokens")
+	}
+	return tokens, nil
+}
+
+// Require fails unless there is a token for each of users user IDs
+func (p *preissuedTokens) Require(users int) error {
+	if !p.reuse && len(p.tokens) < users {
+		return fmt.Errorf("tokens file has %d tokens but the run needs %d; add tokens or set -reuse-tokens", len(p.tokens), users)
+	}
+	return nil
+}
+
+// For returns the token for userID, counting from 1. Without reuse, IDs past
+// the end of the list have no token.
+func (p *preissuedTokens) For(userID int) (AuthTokens, error) {
+	i := userID - 1
+	if p.reuse {
+		i %= len(p.tokens)
+	}
+	if i < 0 || i >= len(p.tokens) {
+		return AuthTokens{}, fmt.Errorf("no pre-issued token for user %d (%d in the tokens file)", userID, len(p.tokens))
+	}
+	return p.tokens[i], nil
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenFile(t *testing.T) {
+	tokens, err := loadTokenFile(filepath.Join("testdata", "tokens.txt"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AuthTokens{{TradingToken: "tok_alice"}, {TradingToken: "tok_bob"}, {TradingToken: "tok_carol"}}
+	if !reflect.DeepEqual(tokens.tokens, want) {
+		t.Fatalf("tokens = %+v, want %+v", tokens.tokens, want)
+	}
+	if err := tokens.Require(3); err != nil {
+		t.Errorf("Require(3): %v", err)
+	}
+	if err := tokens.Require(4); err == nil || !strings.Contains(err.Error(), "-reuse-tokens") {
+		t.Errorf("Require(4) = %v, want an error suggesting -reuse-tokens", err)
+	}
+	if got, err := tokens.For(2); err != nil || got.TradingToken != "tok_bob" {
+		t.Errorf("For(2) = %q, %v; want tok_bob", got.TradingToken, err)
+	}
+	if _, err := tokens.For(4); err == nil {
+		t.Error("For(4) returned a token past the end of the list")
+	}
+
+	// With reuse, user 4 wraps around to the first token
+	tokens.reuse = true
+	if err := tokens.Require(100); err != nil {
+		t.Errorf("Require(100) with reuse: %v", err)
+	}
+	if got, _ := tokens.For(4); got.TradingToken != "tok_alice" {
+		t.Errorf("For(4) with reuse = %q, want tok_alice", got.TradingToken)
+	}
+
+	// Workers authenticate with their token and never reach the frontend
+	defer func() { tokenList = nil }()
+	tokenList = tokens
+	got, ok := signupAndLogin(context.Background(), StressConfig{FrontendURL: "http://127.0.0.1:1"}, 3)
+	if !ok || got.TradingToken != "tok_carol" {
+		t.Errorf("signupAndLogin = %q, %v; want tok_carol", got.TradingToken, ok)
+	}
+
+	if _, err := parseTokens(strings.NewReader("# only comments\n\n")); err == nil {
+		t.Error("a file with no tokens parsed without error")
+	}
+}
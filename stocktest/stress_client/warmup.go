@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// measureStart is when measured (post-warmup) results begin, as UnixNano
+var measureStart atomic.Int64
+
+// inWarmup reports whether the run is still in its -warmup phase
+func inWarmup() bool {
+	return time.Now().UnixNano() < measureStart.Load()
+}
+
+// measurementStart returns when measured results began
+func measurementStart() time.Time {
+	return time.Unix(0, measureStart.Load())
+}
+
+// startWarmup marks the run as warming up until start+warmup, then discards
+// everything recorded about orders so far, including errors, so that the
+// final report and its error rate cover only the measured phase. Users and
+// heartbeats are kept.
+func startWarmup(ctx context.Context, start time.Time, warmup time.Duration) {
+	measureStart.Store(start.Add(warmup).UnixNano())
+	if warmup <= 0 {
+		return
+	}
+
+	slog.Info("warming up, orders sent before measurement starts are excluded", "warmup", warmup)
+	go func() {
+		timer := time.NewTimer(time.Until(measurementStart()))
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			resetMeasurement()
+			slog.Info("warmup complete, measurement started")
+		}
+	}()
+}
+
+// measurementResets counts calls to resetMeasurement, guarded by statsMutex.
+// Samplers that difference counters between reads start over when it
+// changes, rather than subtracting a total recorded before the reset.
+var measurementResets int
+
+// sampleOrderCounters reads the order and error totals along with the
+// number of resets they follow, consistently with resetMeasurement
+func sampleOrderCounters() (submitted, accepted, errors int64, resets int) {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+	return atomic.LoadInt64(&stats.OrdersSubmitted), atomic.LoadInt64(&stats.OrdersAccepted),
+		atomic.LoadInt64(&stats.Errors), measurementResets
+}
+
+// resetMeasurement clears order and error counts and latencies recorded so
+// far
+func resetMeasurement() {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+	measurementResets++
+
+	for _, counter := range []*int64{
+		&stats.OrdersSubmitted, &stats.OrdersAccepted, &stats.Errors,
+		&stats.CancelsSubmitted, &stats.CancelsAccepted, &stats.CancelsSkipped,
+		&stats.ModifiesSubmitted, &stats.ModifiesAccepted, &stats.ModifiesSkipped,
+		&stats.QueriesSubmitted, &stats.QueryErrors,
+		&stats.RetryAttempts, &stats.RetriedSucceeded, &stats.RetriedFailed,
+		&stats.FragmentedSubmitted, &stats.FragmentedAccepted, &stats.FragmentedErrors,
+	} {
+		atomic.StoreInt64(counter, 0)
+	}
+	stats.ErrorCategories = nil
+
+	for _, sh := range stats.shards {
+		sh.reset()
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesRowAfterWarmup(t *testing.T) {
+	resetGlobals(t)
+
+	var out strings.Builder
+	ts := &timeSeriesWriter{w: csv.NewWriter(&out), start: time.Now()}
+
+	// Warmup orders, then the reset that ends warmup, then measured ones
+	atomic.StoreInt64(&stats.OrdersSubmitted, 10)
+	atomic.StoreInt64(&stats.OrdersAccepted, 8)
+	ts.writeRow(time.Now())
+	resetMeasurement()
+	atomic.AddInt64(&stats.OrdersSubmitted, 3)
+	atomic.AddInt64(&stats.OrdersAccepted, 2)
+	ts.writeRow(time.Now())
+	ts.w.Flush()
+
+	rows, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if submitted, accepted := rows[1][2], rows[1][3]; submitted != "3" || accepted != "2" {
+		t.Errorf("first measured row has %s submitted and %s accepted, want 3 and 2", submitted, accepted)
+	}
+}
+
+func TestWarmupErrorsExcluded(t *testing.T) {
+	resetGlobals(t)
+
+	// A rough warmup, then a clean measured window
+	for i := 0; i < 50; i++ {
+		recordError(ErrCategoryIOTimeout)
+	}
+	atomic.StoreInt64(&stats.OrdersSubmitted, 10)
+	resetMeasurement()
+	atomic.AddInt64(&stats.OrdersSubmitted, 100)
+
+	snap := stats.snapshot()
+	r := buildReport(&snap, StressConfig{}, time.Second, false)
+	if r.Errors != 0 || len(r.ErrorCategories) != 0 {
+		t.Errorf("report has %d errors (%v), want the warmup's excluded", r.Errors, r.ErrorCategories)
+	}
+	if code := exitCode(r, 0.01); code != exitOK {
+		t.Errorf("exit code %d for a clean measured window, want %d", code, exitOK)
+	}
+}
+
+func TestWarmupSamplesExcluded(t *testing.T) {
+	resetGlobals(t)
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	// Warmup responses are slow, measured ones fast
+	const slow = 30 * time.Millisecond
+	go serveFakeOrders(server, func(i int) time.Duration {
+		if i < 5 {
+			return slow
+		}
+		return 0
+	})
+
+	submit := func() {
+		if _, err := submitOrderTCP(context.Background(), client, "user_1", "AAPL", 0, 1, 1, 100, submitOptions{}); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		submit()
+	}
+	resetMeasurement()
+	for i := 0; i < 20; i++ {
+		submit()
+	}
+
+	statsMutex.Lock()
+	snap := stats.snapshot()
+	statsMutex.Unlock()
+	if got := atomic.LoadInt64(&snap.OrdersSubmitted); got != 20 {
+		t.Errorf("OrdersSubmitted = %d, want 20 measured orders", got)
+	}
+	if n := snap.OrderLatencies.Count(); n != 20 {
+		t.Errorf("recorded %d latency samples, want 20", n)
+	}
+	if p100 := snap.OrderLatencies.Percentile(1); p100 >= slow {
+		t.Errorf("max percentile %v includes a warmup sample (>= %v)", p100, slow)
+	}
+	if live := takeLiveSnapshot(); live.MaxOrderLatency >= slow {
+		t.Errorf("MaxOrderLatency %v includes a warmup sample", live.MaxOrderLatency)
+	}
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"log/slog"
+	"runtime/debug"
+)
+
+// submitOrder is the submit path of user workers. Tests replace it to inject
+// faults.
+var submitOrder = submitOrderTCP
+
+// recoverWorkerPanic is deferred at the top of worker goroutines. It turns a
+// panic into a worker_panic error, logged with the user, the stack and attrs
+// describing what the goroutine was doing, so one bad order or user does not
+// take the process down and the final report still prints.
+func recoverWorkerPanic(userID int, attrs ...any) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	recordError(ErrCategoryWorkerPanic)
+	attrs = append([]any{"user_id", userID, "panic", r}, attrs...)
+	slog.Error("worker panic recovered", append(attrs, "stack", string(debug.Stack()))...)
+}
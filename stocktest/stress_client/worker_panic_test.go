@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 Ayon Sarkar. All Rights Reserved.
+ *
+ * This source code is licensed under the terms found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * USE FOR EVALUATION ONLY. NO PRODUCTION USE OR COPYING PERMITTED.
+ */
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stress_client/protocol"
+)
+
+func TestWorkerPanicRecovered(t *testing.T) {
+	resetGlobals(t)
+
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	prev := slog.Default()
+	defer func() {
+		slog.SetDefault(prev)
+		log.SetOutput(os.Stderr)
+	}()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	log.SetOutput(io.Discard)
+
+	// The third order panics as a bug in the submit path would
+	var calls atomic.Int64
+	submitOrder = func(ctx context.Context, conn net.Conn, userID, symbol string, side, orderType int, quantity int64, price float64, opts submitOptions) (protocol.OrderResponse, error) {
+		if calls.Add(1) == 3 {
+			var orders []protocol.Order
+			_ = orders[1]
+		}
+		return submitOrderTCP(ctx, conn, userID, symbol, side, orderType, quantity, price, opts)
+	}
+	defer func() { submitOrder = submitOrderTCP }()
+
+	config := StressConfig{
+		EngineAddr:       "unused:0",
+		NumUsers:         2,
+		OrdersPerUser:    10,
+		Concurrency:      2,
+		OrderConcurrency: 2,
+		OrderMix:         defaultOrderMix,
+		PriceRef:         100,
+		Symbols:          defaultSymbols,
+		CPUThreshold:     100,
+		DrainTimeout:     time.Second,
+	}
+	report := runStressTest(context.Background(), config, func() bool { return false })
+
+	if n := report.ErrorCategories[ErrCategoryWorkerPanic]; n != 1 {
+		t.Errorf("worker_panic = %d, want 1", n)
+	}
+	if report.OrdersSubmitted != 19 || report.UsersCompleted != 2 {
+		t.Errorf("report = %d orders, %d users completed; want the other 19 orders and both users", report.OrdersSubmitted, report.UsersCompleted)
+	}
+	if n := atomic.LoadInt64(&ordersInFlight); n != 0 {
+		t.Errorf("%d orders still in flight after the panic", n)
+	}
+}